@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// APIKeys the static API keys by subject
+type APIKeys map[string]string
+
+// ParseAPIKeys parses the keys written subject:key separated by commas
+func ParseAPIKeys(s string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		i := strings.Index(entry, ":")
+		if i <= 0 || i == len(entry)-1 {
+			return nil, fmt.Errorf("the API key %q is not subject:key", entry)
+		}
+		keys[entry[:i]] = entry[i+1:]
+	}
+	return keys, nil
+}
+
+// Authenticate implements Authenticator, all the keys are compared in constant time
+func (k APIKeys) Authenticate(token string) (*Identity, error) {
+	var identity *Identity
+	for subject, key := range k {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			identity = &Identity{Subject: subject}
+		}
+	}
+	if identity == nil {
+		return nil, fmt.Errorf("%w: unknown API key", ErrInvalidToken)
+	}
+	return identity, nil
+}
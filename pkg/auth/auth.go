@@ -0,0 +1,149 @@
+// Package auth authenticates the callers of todod with a bearer token,
+// a static API key or a JWT signed by a key of a JWKS file.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidToken the token is unknown, expired or badly signed
+var ErrInvalidToken = errors.New("invalid token")
+
+// Identity the authenticated caller
+type Identity struct {
+	// Subject the name of the caller
+	Subject string
+	// Roles the roles of the caller
+	Roles []string
+	// Tenant the tenant of the caller, empty if the token doesn't choose it
+	Tenant string
+}
+
+// RoleAdmin the role of the callers who access the todos of all the owners
+const RoleAdmin = "admin"
+
+// HasRole tells if the identity has the role
+func (i *Identity) HasRole(role string) bool {
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator checks a bearer token
+type Authenticator interface {
+	// Authenticate returns the identity of the token or an error wrapping ErrInvalidToken
+	Authenticate(token string) (*Identity, error)
+}
+
+// Chain tries the authenticators in the order, the first one which accepts the token wins
+type Chain []Authenticator
+
+// Authenticate implements Authenticator
+func (c Chain) Authenticate(token string) (*Identity, error) {
+	err := ErrInvalidToken
+	for _, a := range c {
+		var identity *Identity
+		if identity, err = a.Authenticate(token); err == nil {
+			return identity, nil
+		}
+	}
+	return nil, err
+}
+
+// WithAdmins returns an authenticator which gives the role admin to the subjects authenticated by a
+func WithAdmins(a Authenticator, subjects []string) Authenticator {
+	admins := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		admins[subject] = true
+	}
+	return &adminsAuthenticator{Authenticator: a, admins: admins}
+}
+
+type adminsAuthenticator struct {
+	Authenticator
+	admins map[string]bool
+}
+
+// Authenticate implements Authenticator
+func (a *adminsAuthenticator) Authenticate(token string) (*Identity, error) {
+	identity, err := a.Authenticator.Authenticate(token)
+	if err != nil {
+		return nil, err
+	}
+	if a.admins[identity.Subject] && !identity.HasRole(RoleAdmin) {
+		identity.Roles = append(identity.Roles, RoleAdmin)
+	}
+	return identity, nil
+}
+
+type identityKey struct{}
+
+// NewContext returns a context with the identity
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity of the context
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+// AuthFunc returns the function of the grpc_auth interceptors which authenticates the bearer token of the metadata
+func AuthFunc(a Authenticator) grpc_auth.AuthFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		token, err := grpc_auth.AuthFromMD(ctx, "bearer")
+		if err != nil {
+			return nil, err
+		}
+		identity, err := a.Authenticate(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		ctxlogrus.AddFields(ctx, log.Fields{"auth.sub": identity.Subject})
+		return NewContext(ctx, identity), nil
+	}
+}
+
+// Handler returns a handler which calls next only with a valid bearer token in the header Authorization
+func Handler(a Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "bearer "
+		header := r.Header.Get("Authorization")
+		if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+			unauthenticated(w, "Request unauthenticated with bearer")
+			return
+		}
+		identity, err := a.Authenticate(header[len(prefix):])
+		if err != nil {
+			unauthenticated(w, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), identity)))
+	})
+}
+
+// unauthenticated answers like the REST gateway for the code Unauthenticated
+func unauthenticated(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   message,
+		"code":    codes.Unauthenticated,
+		"message": message,
+	})
+}
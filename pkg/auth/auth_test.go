@@ -0,0 +1,144 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/sjeandeaux/todo/pkg/auth"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auth Suite")
+}
+
+var _ = Describe("Auth", func() {
+
+	var keys APIKeys
+
+	BeforeEach(func() {
+		var err error
+		keys, err = ParseAPIKeys("alice:secret-a, bob:secret-b")
+		Ω(err).NotTo(HaveOccurred())
+	})
+
+	Describe("API keys", func() {
+		It("should parse subject:key", func() {
+			Ω(keys).Should(Equal(APIKeys{"alice": "secret-a", "bob": "secret-b"}))
+		})
+
+		It("should refuse an entry without subject or key", func() {
+			for _, s := range []string{"secret", ":secret", "alice:"} {
+				_, err := ParseAPIKeys(s)
+				Ω(err).Should(HaveOccurred(), s)
+			}
+		})
+
+		It("should authenticate the subject of the key", func() {
+			Ω(keys.Authenticate("secret-b")).Should(Equal(&Identity{Subject: "bob"}))
+		})
+
+		It("should refuse an unknown key", func() {
+			_, err := keys.Authenticate("secret")
+			Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue())
+		})
+	})
+
+	Describe("Chain", func() {
+		It("should return the identity of the first authenticator which accepts the token", func() {
+			chain := Chain{APIKeys{"alice": "a"}, APIKeys{"bob": "b"}}
+			Ω(chain.Authenticate("b")).Should(Equal(&Identity{Subject: "bob"}))
+		})
+
+		It("should return the error of the last authenticator", func() {
+			_, err := Chain{APIKeys{"alice": "a"}}.Authenticate("b")
+			Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue())
+		})
+	})
+
+	Describe("Admins", func() {
+		It("should give the role admin to the subjects", func() {
+			admins := WithAdmins(keys, []string{"bob"})
+			Ω(admins.Authenticate("secret-b")).Should(Equal(&Identity{Subject: "bob", Roles: []string{RoleAdmin}}))
+			Ω(admins.Authenticate("secret-a")).Should(Equal(&Identity{Subject: "alice"}))
+		})
+
+		It("should tell if an identity has a role", func() {
+			identity := &Identity{Subject: "bob", Roles: []string{"reader", RoleAdmin}}
+			Ω(identity.HasRole(RoleAdmin)).Should(BeTrue())
+			Ω(identity.HasRole("writer")).Should(BeFalse())
+		})
+	})
+
+	Describe("gRPC", func() {
+		authenticate := func(md metadata.MD) (context.Context, error) {
+			return AuthFunc(keys)(metadata.NewIncomingContext(context.Background(), md))
+		}
+
+		It("should put the identity in the context", func() {
+			ctx, err := authenticate(metadata.Pairs("authorization", "Bearer secret-a"))
+			Ω(err).NotTo(HaveOccurred())
+			identity, ok := FromContext(ctx)
+			Ω(ok).Should(BeTrue())
+			Ω(identity).Should(Equal(&Identity{Subject: "alice"}))
+		})
+
+		It("should refuse the calls without token", func() {
+			_, err := authenticate(metadata.MD{})
+			Ω(status.Code(err)).Should(Equal(codes.Unauthenticated))
+		})
+
+		It("should refuse the calls with an invalid token", func() {
+			_, err := authenticate(metadata.Pairs("authorization", "Bearer secret"))
+			Ω(status.Code(err)).Should(Equal(codes.Unauthenticated))
+		})
+	})
+
+	Describe("HTTP", func() {
+		var handler http.Handler
+
+		BeforeEach(func() {
+			handler = Handler(keys, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				identity, _ := FromContext(r.Context())
+				w.Write([]byte(identity.Subject))
+			}))
+		})
+
+		serve := func(authorization string) *httptest.ResponseRecorder {
+			request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if authorization != "" {
+				request.Header.Set("Authorization", authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		It("should call the handler with the identity", func() {
+			recorder := serve("bearer secret-a")
+			Ω(recorder.Code).Should(Equal(http.StatusOK))
+			Ω(recorder.Body.String()).Should(Equal("alice"))
+		})
+
+		It("should answer 401 without token", func() {
+			recorder := serve("")
+			Ω(recorder.Code).Should(Equal(http.StatusUnauthorized))
+			Ω(recorder.Header().Get("WWW-Authenticate")).Should(Equal("Bearer"))
+			Ω(recorder.Body.String()).Should(ContainSubstring(`"code":16`))
+		})
+
+		It("should answer 401 with an invalid token", func() {
+			Ω(serve("Bearer secret").Code).Should(Equal(http.StatusUnauthorized))
+			Ω(serve("Basic secret-a").Code).Should(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// signingMethods the accepted algorithms, the keys of a JWKS are public so the HMAC ones are refused
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// JWKS checks the JWTs with the public keys of a JWKS file, the file is read again when it changes.
+// The claim sub is the subject, the claim roles the roles and the claim tenant the tenant.
+type JWKS struct {
+	file     string
+	issuer   string
+	audience string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	keys    map[string]interface{}
+}
+
+// NewJWKS loads the keys of file, the claims iss and aud are checked if issuer and audience are not empty
+func NewJWKS(file, issuer, audience string) (*JWKS, error) {
+	j := &JWKS{file: file, issuer: issuer, audience: audience}
+	if _, err := j.current(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Authenticate implements Authenticator
+func (j *JWKS) Authenticate(token string) (*Identity, error) {
+	parser := &jwt.Parser{ValidMethods: signingMethods}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(token, claims, j.key); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, fmt.Errorf("%w: no expiration", ErrInvalidToken)
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	if j.issuer != "" && !claims.VerifyIssuer(j.issuer, true) {
+		return nil, fmt.Errorf("%w: bad issuer", ErrInvalidToken)
+	}
+	if j.audience != "" && !containsString(stringsClaim(claims["aud"]), j.audience) {
+		return nil, fmt.Errorf("%w: bad audience", ErrInvalidToken)
+	}
+	tenant, _ := claims["tenant"].(string)
+	return &Identity{Subject: subject, Roles: stringsClaim(claims["roles"]), Tenant: tenant}, nil
+}
+
+// key returns the key of the header kid, the only key of the file without kid
+func (j *JWKS) key(token *jwt.Token) (interface{}, error) {
+	keys, err := j.current()
+	if err != nil {
+		return nil, err
+	}
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return key, nil
+}
+
+// current returns the keys, they are loaded again if the file changed.
+// The previous keys are kept if the new file is invalid.
+func (j *JWKS) current() (map[string]interface{}, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	info, err := os.Stat(j.file)
+	if err == nil && j.keys != nil && info.ModTime().Equal(j.modTime) && info.Size() == j.size {
+		return j.keys, nil
+	}
+	var keys map[string]interface{}
+	if err == nil {
+		keys, err = loadJWKS(j.file)
+	}
+	if err != nil {
+		if j.keys == nil {
+			return nil, err
+		}
+		log.WithError(err).Warn("the JWKS can't be reloaded, the previous keys are used")
+		return j.keys, nil
+	}
+	if j.keys != nil {
+		log.WithField("file", j.file).Info("the JWKS is reloaded")
+	}
+	j.keys, j.modTime, j.size = keys, info.ModTime(), info.Size()
+	return keys, nil
+}
+
+// jwk a key of the JWKS, only the members of the RSA and EC public keys
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func loadJWKS(file string) (map[string]interface{}, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: %w", file, k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no signing key", file)
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unknown curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("the point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unknown key type %q", k.Kty)
+	}
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// stringsClaim returns a claim which is a string or an array of strings
+func stringsClaim(claim interface{}) []string {
+	switch c := claim.(type) {
+	case string:
+		return []string{c}
+	case []interface{}:
+		values := make([]string, 0, len(c))
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func containsString(values []string, expected string) bool {
+	for _, v := range values {
+		if v == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	. "github.com/sjeandeaux/todo/pkg/auth"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// encode encodes a number of a JWK
+func encode(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+// writeJWKS writes the public keys by kid in file
+func writeJWKS(file string, keys map[string]interface{}) {
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": encode(k.N), "e": encode(big.NewInt(int64(k.E)))})
+		case *ecdsa.PrivateKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": encode(k.X), "y": encode(k.Y)})
+		}
+	}
+	content, err := json.Marshal(set)
+	Ω(err).NotTo(HaveOccurred())
+	Ω(ioutil.WriteFile(file, content, 0600)).Should(Succeed())
+}
+
+// sign returns a JWT with the claims signed by key
+func sign(method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	Ω(err).NotTo(HaveOccurred())
+	return signed
+}
+
+var _ = Describe("JWKS", func() {
+
+	var (
+		dir    string
+		file   string
+		rsaKey *rsa.PrivateKey
+		ecKey  *ecdsa.PrivateKey
+		jwks   *JWKS
+		claims jwt.MapClaims
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "jwks")
+		Ω(err).NotTo(HaveOccurred())
+		file = filepath.Join(dir, "jwks.json")
+
+		rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Ω(err).NotTo(HaveOccurred())
+		ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Ω(err).NotTo(HaveOccurred())
+		writeJWKS(file, map[string]interface{}{"rsa": rsaKey, "ec": ecKey})
+
+		jwks, err = NewJWKS(file, "https://issuer", "todo")
+		Ω(err).NotTo(HaveOccurred())
+
+		claims = jwt.MapClaims{
+			"sub":   "alice",
+			"iss":   "https://issuer",
+			"aud":   []string{"todo", "other"},
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": []string{"admin"},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should authenticate the JWT signed by a RSA key", func() {
+		token := sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		Ω(jwks.Authenticate(token)).Should(Equal(&Identity{Subject: "alice", Roles: []string{"admin"}}))
+	})
+
+	It("should authenticate the JWT signed by an EC key", func() {
+		claims["aud"] = "todo"
+		token := sign(jwt.SigningMethodES256, "ec", ecKey, claims)
+		Ω(jwks.Authenticate(token)).Should(Equal(&Identity{Subject: "alice", Roles: []string{"admin"}}))
+	})
+
+	It("should return the tenant of the claim tenant", func() {
+		claims["tenant"] = "acme"
+		token := sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		Ω(jwks.Authenticate(token)).Should(Equal(&Identity{Subject: "alice", Roles: []string{"admin"}, Tenant: "acme"}))
+	})
+
+	It("should refuse the JWT signed by another key", func() {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		Ω(err).NotTo(HaveOccurred())
+		_, err = jwks.Authenticate(sign(jwt.SigningMethodRS256, "rsa", other, claims))
+		Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue())
+	})
+
+	It("should refuse the JWT with an unknown kid", func() {
+		_, err := jwks.Authenticate(sign(jwt.SigningMethodRS256, "unknown", rsaKey, claims))
+		Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue())
+	})
+
+	It("should refuse the JWT signed with HMAC", func() {
+		_, err := jwks.Authenticate(sign(jwt.SigningMethodHS256, "rsa", []byte("secret"), claims))
+		Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue())
+	})
+
+	It("should refuse the expired JWT", func() {
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err := jwks.Authenticate(sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims))
+		Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue())
+	})
+
+	It("should refuse the JWT without expiration, subject, good issuer or good audience", func() {
+		for claim, value := range map[string]interface{}{"exp": nil, "sub": nil, "iss": "https://other", "aud": "other"} {
+			invalid := jwt.MapClaims{}
+			for k, v := range claims {
+				invalid[k] = v
+			}
+			if value == nil {
+				delete(invalid, claim)
+			} else {
+				invalid[claim] = value
+			}
+			_, err := jwks.Authenticate(sign(jwt.SigningMethodRS256, "rsa", rsaKey, invalid))
+			Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue(), claim)
+		}
+	})
+
+	It("should reload the keys when the file changes", func() {
+		rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+		Ω(err).NotTo(HaveOccurred())
+		writeJWKS(file, map[string]interface{}{"rotated": rotated})
+		later := time.Now().Add(time.Minute)
+		Ω(os.Chtimes(file, later, later)).Should(Succeed())
+
+		Ω(jwks.Authenticate(sign(jwt.SigningMethodRS256, "rotated", rotated, claims))).Should(Equal(&Identity{Subject: "alice", Roles: []string{"admin"}}))
+		_, err = jwks.Authenticate(sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims))
+		Ω(errors.Is(err, ErrInvalidToken)).Should(BeTrue())
+	})
+
+	It("should keep the keys when the new file is invalid", func() {
+		Ω(ioutil.WriteFile(file, []byte("{"), 0600)).Should(Succeed())
+		token := sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		Ω(jwks.Authenticate(token)).Should(Equal(&Identity{Subject: "alice", Roles: []string{"admin"}}))
+	})
+
+	It("should fail on a file without key", func() {
+		Ω(ioutil.WriteFile(file, []byte(`{"keys":[]}`), 0600)).Should(Succeed())
+		_, err := NewJWKS(file, "", "")
+		Ω(err).Should(HaveOccurred())
+	})
+})
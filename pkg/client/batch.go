@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+)
+
+// BatchResult the outcome of a todo of a batch
+type BatchResult struct {
+	// ID the ID of the created todo
+	ID string
+	// Changed tells if the todo is created, updated or moved to the trash
+	Changed bool
+	// Err the failure of the todo, ErrAborted on the others when a todo of an atomic batch fails
+	Err error
+}
+
+// fromBatchStatus returns the error of the status of a todo of a batch, nil without status
+func fromBatchStatus(s *pb.BatchStatus) error {
+	if s == nil {
+		return nil
+	}
+	return fromStatus(status.ErrorProto(&spb.Status{Code: s.GetCode(), Message: s.GetMessage(), Details: s.GetDetails()}))
+}
+
+// BatchCreate creates the todos in one call, at most 1000, and returns their results in the same order.
+// If atomic is set, all the todos are created or none of them, the error is ErrUnimplemented if the daemon can't.
+func (m *ToDoManager) BatchCreate(cxt context.Context, toDos []ToDo, atomic bool) ([]BatchResult, error) {
+	request := &pb.BatchCreateRequest{Atomic: atomic}
+	for _, toDo := range toDos {
+		request.Requests = append(request.Requests, toCreateRequest(toDo))
+	}
+	response, err := m.Client.BatchCreate(cxt, request)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	results := []BatchResult{}
+	for _, result := range response.GetResults() {
+		results = append(results, BatchResult{ID: result.GetId(), Changed: result.GetId() != "", Err: fromBatchStatus(result.GetStatus())})
+	}
+	return results, nil
+}
+
+// BatchUpdate updates the fields listed in paths of the todos like Update in one call, at most 1000,
+// and returns their results in the same order. Atomic is like in BatchCreate.
+func (m *ToDoManager) BatchUpdate(cxt context.Context, toDos []ToDo, paths []string, atomic bool) ([]BatchResult, error) {
+	request := &pb.BatchUpdateRequest{Atomic: atomic}
+	for _, toDo := range toDos {
+		r, err := toUpdateRequest(toDo, paths)
+		if err != nil {
+			return nil, err
+		}
+		request.Requests = append(request.Requests, r)
+	}
+	response, err := m.Client.BatchUpdate(cxt, request)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	results := []BatchResult{}
+	for _, result := range response.GetResults() {
+		results = append(results, BatchResult{Changed: result.GetUpdated() > 0, Err: fromBatchStatus(result.GetStatus())})
+	}
+	return results, nil
+}
+
+// BatchDelete moves the todos to the trash like Delete in one call, at most 1000, the version of a todo is checked if it isn't 0.
+// It returns their results in the same order. Atomic is like in BatchCreate.
+func (m *ToDoManager) BatchDelete(cxt context.Context, toDos []ToDo, atomic bool) ([]BatchResult, error) {
+	request := &pb.BatchDeleteRequest{Atomic: atomic}
+	for _, toDo := range toDos {
+		request.Requests = append(request.Requests, &pb.DeleteRequest{Id: toDo.ID, ExpectedVersion: toDo.Version})
+	}
+	response, err := m.Client.BatchDelete(cxt, request)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	results := []BatchResult{}
+	for _, result := range response.GetResults() {
+		results = append(results, BatchResult{Changed: result.GetDeleted() > 0, Err: fromBatchStatus(result.GetStatus())})
+	}
+	return results, nil
+}
@@ -0,0 +1,89 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sjeandeaux/todo/pkg/client"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Batch", func() {
+	var (
+		manager *client.ToDoManager
+		mock    *mockToDoServiceClient
+	)
+
+	BeforeEach(func() {
+		mock = &mockToDoServiceClient{}
+		manager = &client.ToDoManager{
+			Client: mock,
+		}
+	})
+
+	Describe("BatchCreate", func() {
+		It("should return the IDs and the failures in order", func() {
+			mock.expectedRequest = &pb.BatchCreateRequest{Atomic: true, Requests: []*pb.CreateRequest{
+				{ToDo: &pb.ToDo{Title: "first", Reminder: &timestamp.Timestamp{}, State: pb.ToDo_IN_PROGRESS}},
+				{ToDo: &pb.ToDo{Reminder: &timestamp.Timestamp{}}},
+			}}
+			mock.response = &pb.BatchCreateResponse{Results: []*pb.BatchCreateResponse_Result{
+				{Status: &pb.BatchStatus{Code: int32(codes.Aborted), Message: "not applied"}},
+				{Status: &pb.BatchStatus{Code: int32(codes.InvalidArgument), Message: "title is required"}},
+			}}
+			results, err := manager.BatchCreate(context.TODO(), []client.ToDo{{Title: "first", State: "IN_PROGRESS"}, {}}, true)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(results).Should(HaveLen(2))
+			Ω(results[0].Changed).Should(BeFalse())
+			Ω(errors.Is(results[0].Err, client.ErrAborted)).Should(BeTrue())
+			Ω(errors.Is(results[1].Err, client.ErrInvalidArgument)).Should(BeTrue())
+			Ω(results[1].Err.Error()).Should(Equal("title is required"))
+		})
+
+		It("should return ErrUnimplemented when the daemon can't apply an atomic batch", func() {
+			mock.expectedRequest = &pb.BatchCreateRequest{Atomic: true}
+			mock.err = status.Error(codes.Unimplemented, "the backend doesn't support the atomic batches")
+			_, err := manager.BatchCreate(context.TODO(), nil, true)
+			Ω(errors.Is(err, client.ErrUnimplemented)).Should(BeTrue())
+		})
+	})
+
+	Describe("BatchUpdate", func() {
+		It("should tell which todos changed", func() {
+			mock.expectedRequest = &pb.BatchUpdateRequest{Requests: []*pb.UpdateRequest{{
+				ToDo:            &pb.ToDo{Id: "id", Title: "changed", Reminder: &timestamp.Timestamp{}},
+				UpdateMask:      &field_mask.FieldMask{Paths: []string{client.FieldTitle}},
+				ExpectedVersion: 3,
+			}}}
+			mock.response = &pb.BatchUpdateResponse{Results: []*pb.BatchUpdateResponse_Result{{Updated: 1}}}
+			results, err := manager.BatchUpdate(context.TODO(), []client.ToDo{{ID: "id", Title: "changed", Version: 3}}, []string{client.FieldTitle}, false)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(results).Should(Equal([]client.BatchResult{{Changed: true}}))
+		})
+
+		It("should refuse the batch without paths", func() {
+			_, err := manager.BatchUpdate(context.TODO(), []client.ToDo{{ID: "id", Title: "changed"}}, nil, false)
+			Ω(errors.Is(err, client.ErrInvalidArgument)).Should(BeTrue())
+		})
+	})
+
+	Describe("BatchDelete", func() {
+		It("should send the IDs and the versions", func() {
+			mock.expectedRequest = &pb.BatchDeleteRequest{Requests: []*pb.DeleteRequest{{Id: "a", ExpectedVersion: 2}, {Id: "b"}}}
+			mock.response = &pb.BatchDeleteResponse{Results: []*pb.BatchDeleteResponse_Result{
+				{Deleted: 1},
+				{Status: &pb.BatchStatus{Code: int32(codes.NotFound), Message: "todo not found"}},
+			}}
+			results, err := manager.BatchDelete(context.TODO(), []client.ToDo{{ID: "a", Version: 2}, {ID: "b"}}, false)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(results[0]).Should(Equal(client.BatchResult{Changed: true}))
+			Ω(errors.Is(results[1].Err, client.ErrNotFound)).Should(BeTrue())
+		})
+	})
+})
@@ -2,9 +2,13 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/protobuf/field_mask"
 	grpc "google.golang.org/grpc"
 )
 
@@ -34,15 +38,38 @@ type ToDo struct {
 	Tags []string
 	//Reminder of todo
 	Reminder int64
+	//Owner of todo, the caller if empty on creation
+	Owner string
+	//ListID the list of todo, empty if the todo is in no list
+	ListID string
+	//Version of todo, incremented by each change, the expected version on update if not 0
+	Version int64
+	//CreatedAt the time of creation, set by the daemon
+	CreatedAt time.Time
+	//UpdatedAt the time of the last change, set by the daemon
+	UpdatedAt time.Time
+	//CompletedAt the time the todo moved to DONE, zero if it isn't DONE, set by the daemon
+	CompletedAt time.Time
+	//DeletedAt the time the todo moved to the trash, zero if it isn't in the trash, set by the daemon
+	DeletedAt time.Time
 }
 
 // Create create a toDo
 func (m *ToDoManager) Create(cxt context.Context, toDo ToDo) (string, error) {
+	response, err := m.Client.Create(cxt, toCreateRequest(toDo))
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return response.GetId(), nil
+}
+
+// toCreateRequest returns the request of the creation of toDo
+func toCreateRequest(toDo ToDo) *pb.CreateRequest {
 	state, ok := pb.ToDo_State_value[toDo.State]
 	if !ok {
 		state = int32(pb.ToDo_NOT_STARTED)
 	}
-	request := &pb.CreateRequest{
+	return &pb.CreateRequest{
 		ToDo: &pb.ToDo{
 			Id:          toDo.ID,
 			Title:       toDo.Title,
@@ -50,23 +77,48 @@ func (m *ToDoManager) Create(cxt context.Context, toDo ToDo) (string, error) {
 			Tags:        toDo.Tags,
 			Reminder:    &timestamp.Timestamp{Seconds: int64(toDo.Reminder)},
 			State:       pb.ToDo_State(state),
+			Owner:       toDo.Owner,
+			ListId:      toDo.ListID,
 		},
 	}
+}
 
-	response, err := m.Client.Create(cxt, request)
+// The fields of a todo which can be updated
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldTags        = "tags"
+	FieldState       = "state"
+	FieldReminder    = "reminder"
+	FieldListID      = "listId"
+)
+
+// Update update the fields of a todo listed in paths, the other fields are kept,
+// the error is ErrInvalidArgument if paths is empty
+// and ErrAborted if toDo.Version isn't 0 and the todo has changed since
+func (m *ToDoManager) Update(cxt context.Context, toDo ToDo, paths []string) (bool, error) {
+	request, err := toUpdateRequest(toDo, paths)
 	if err != nil {
-		return "", err
+		return false, err
 	}
-	return response.GetId(), nil
+	response, err := m.Client.Update(cxt, request)
+	if err != nil {
+		return false, fromStatus(err)
+	}
+	return response.Updated > 0, nil
 }
 
-// Update update a todo
-func (m *ToDoManager) Update(cxt context.Context, toDo ToDo) (bool, error) {
+// toUpdateRequest returns the request of the update of the fields of toDo listed in paths,
+// an empty mask would make the daemon replace all the fields
+func toUpdateRequest(toDo ToDo, paths []string) (*pb.UpdateRequest, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no field to update", ErrInvalidArgument)
+	}
 	state, ok := pb.ToDo_State_value[toDo.State]
 	if !ok {
 		state = int32(pb.ToDo_NOT_STARTED)
 	}
-	request := &pb.UpdateRequest{
+	return &pb.UpdateRequest{
 		ToDo: &pb.ToDo{
 			Id:          toDo.ID,
 			Title:       toDo.Title,
@@ -74,77 +126,106 @@ func (m *ToDoManager) Update(cxt context.Context, toDo ToDo) (bool, error) {
 			Tags:        toDo.Tags,
 			Reminder:    &timestamp.Timestamp{Seconds: int64(toDo.Reminder)},
 			State:       pb.ToDo_State(state),
+			ListId:      toDo.ListID,
 		},
-	}
-
-	response, err := m.Client.Update(cxt, request)
-	if err != nil {
-		return false, err
-	}
-	return response.Updated > 0, nil
+		UpdateMask:      &field_mask.FieldMask{Paths: paths},
+		ExpectedVersion: toDo.Version,
+	}, nil
 }
 
-// Delete a todo
-func (m *ToDoManager) Delete(cxt context.Context, id string) (bool, error) {
+// Delete moves a todo to the trash, the error is ErrAborted if version isn't 0 and the todo has another version
+func (m *ToDoManager) Delete(cxt context.Context, id string, version int64) (bool, error) {
 	request := &pb.DeleteRequest{
-		Id: id,
+		Id:              id,
+		ExpectedVersion: version,
 	}
 	response, err := m.Client.Delete(cxt, request)
 	if err != nil {
-		return false, err
+		return false, fromStatus(err)
 	}
 	return response.Deleted > 0, nil
 }
 
-// Search todos
+// Restore takes a todo out of the trash, the error is ErrNotFound if it isn't in the trash
+func (m *ToDoManager) Restore(cxt context.Context, id string) (bool, error) {
+	response, err := m.Client.Restore(cxt, &pb.RestoreRequest{Id: id})
+	if err != nil {
+		return false, fromStatus(err)
+	}
+	return response.Restored > 0, nil
+}
+
+// Purge removes a todo from the trash for good, the error is ErrNotFound if it isn't in the trash
+func (m *ToDoManager) Purge(cxt context.Context, id string) (bool, error) {
+	response, err := m.Client.Purge(cxt, &pb.PurgeRequest{Id: id})
+	if err != nil {
+		return false, fromStatus(err)
+	}
+	return response.Purged > 0, nil
+}
+
+// Read a todo, the error is ErrNotFound if it doesn't exist
 func (m *ToDoManager) Read(cxt context.Context, id string) (*ToDo, error) {
 	request := &pb.ReadRequest{
 		Id: id,
 	}
 	response, err := m.Client.Read(cxt, request)
 	if err != nil {
-		return nil, err
+		return nil, fromStatus(err)
 	}
 
 	if todo := response.GetToDo(); todo != nil {
-		return &ToDo{
-			ID:          todo.GetId(),
-			Title:       todo.GetTitle(),
-			Description: todo.GetDescription(),
-			Tags:        todo.GetTags(),
-			State:       todo.GetState().String(),
-			Reminder:    todo.GetReminder().GetSeconds(),
-		}, nil
+		result := newToDo(todo)
+		return &result, nil
 	}
 
 	return nil, nil
 
 }
 
-// Search todos
+// Search todos, it walks all the pages
 func (m *ToDoManager) Search(cxt context.Context, pattern string, tags []string, states []string) ([]ToDo, error) {
-	request := &pb.SearchRequest{
+	it := m.Iterate(cxt, Query{
 		Pattern: pattern,
 		Tags:    tags,
-		States:  toState(states),
+		States:  states,
+	})
+
+	result := []ToDo{}
+	for it.Next() {
+		result = append(result, it.ToDo())
 	}
-	response, err := m.Client.Search(cxt, request)
-	if err != nil {
+	if err := it.Err(); err != nil {
 		return nil, err
 	}
+	return result, nil
+}
+
+func newToDo(todo *pb.ToDo) ToDo {
+	return ToDo{
+		ID:          todo.GetId(),
+		Title:       todo.GetTitle(),
+		Description: todo.GetDescription(),
+		Tags:        todo.GetTags(),
+		State:       todo.GetState().String(),
+		Reminder:    todo.GetReminder().GetSeconds(),
+		Owner:       todo.GetOwner(),
+		ListID:      todo.GetListId(),
+		Version:     todo.GetVersion(),
+		CreatedAt:   toTime(todo.GetCreatedAt()),
+		UpdatedAt:   toTime(todo.GetUpdatedAt()),
+		CompletedAt: toTime(todo.GetCompletedAt()),
+		DeletedAt:   toTime(todo.GetDeletedAt()),
+	}
+}
 
-	result := []ToDo{}
-	for _, todo := range response.GetToDos() {
-		result = append(result, ToDo{
-			ID:          todo.GetId(),
-			Title:       todo.GetTitle(),
-			Description: todo.GetDescription(),
-			Tags:        todo.GetTags(),
-			State:       todo.GetState().String(),
-			Reminder:    todo.GetReminder().GetSeconds(),
-		})
+// toTime returns the time of the timestamp, zero if it is missing
+func toTime(t *timestamp.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
 	}
-	return result, nil
+	value, _ := ptypes.Timestamp(t)
+	return value
 }
 
 func toState(states []string) []pb.ToDo_State {
@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sjeandeaux/todo/pkg/client"
 	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestService(t *testing.T) {
@@ -125,6 +131,57 @@ var _ = Describe("Todo", func() {
 			})
 		})
 
+		Context("With a todo which is not found by the daemon", func() {
+			It("should return ErrNotFound", func() {
+				mock.expectedRequest = &pb.ReadRequest{
+					Id: "id",
+				}
+				mock.err = status.Error(codes.NotFound, "todo not found")
+				response, err := manager.Read(context.TODO(), "id")
+				Ω(response).Should(BeNil())
+				Ω(errors.Is(err, client.ErrNotFound)).Should(BeTrue())
+				Ω(err.Error()).Should(Equal("todo not found"))
+			})
+		})
+
+		Context("With an invalid id", func() {
+			It("should return ErrInvalidArgument with the violations", func() {
+				mock.expectedRequest = &pb.ReadRequest{
+					Id: "id",
+				}
+				st, _ := status.New(codes.InvalidArgument, "invalid todo ID").WithDetails(&errdetails.BadRequest{
+					FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "id", Description: "invalid todo ID"}},
+				})
+				mock.err = st.Err()
+				_, err := manager.Read(context.TODO(), "id")
+				Ω(errors.Is(err, client.ErrInvalidArgument)).Should(BeTrue())
+
+				var clientErr *client.Error
+				Ω(errors.As(err, &clientErr)).Should(BeTrue())
+				Ω(clientErr.Violations()).Should(Equal(map[string]string{"id": "invalid todo ID"}))
+			})
+		})
+
+		Context("With a client which calls too often", func() {
+			It("should return ErrResourceExhausted with the retry delay", func() {
+				mock.expectedRequest = &pb.ReadRequest{
+					Id: "id",
+				}
+				st, _ := status.New(codes.ResourceExhausted, "too many calls").WithDetails(&errdetails.RetryInfo{
+					RetryDelay: ptypes.DurationProto(2 * time.Second),
+				})
+				mock.err = st.Err()
+				_, err := manager.Read(context.TODO(), "id")
+				Ω(errors.Is(err, client.ErrResourceExhausted)).Should(BeTrue())
+
+				var clientErr *client.Error
+				Ω(errors.As(err, &clientErr)).Should(BeTrue())
+				delay, ok := clientErr.RetryDelay()
+				Ω(ok).Should(BeTrue())
+				Ω(delay).Should(Equal(2 * time.Second))
+			})
+		})
+
 		Context("With an issue", func() {
 			It("should fail", func() {
 				mock.expectedRequest = &pb.ReadRequest{
@@ -150,6 +207,7 @@ var _ = Describe("Todo", func() {
 						Reminder:    &timestamp.Timestamp{Seconds: int64(666)},
 						State:       pb.ToDo_IN_PROGRESS,
 					},
+					UpdateMask: &field_mask.FieldMask{Paths: []string{"title", "state"}},
 				}
 				mock.response = &pb.UpdateResponse{Updated: 6}
 
@@ -160,7 +218,7 @@ var _ = Describe("Todo", func() {
 					Tags:        []string{"", ""},
 					Reminder:    666,
 					State:       "IN_PROGRESS",
-				})).Should(Equal(true))
+				}, []string{client.FieldTitle, client.FieldState})).Should(Equal(true))
 			})
 		})
 
@@ -175,6 +233,7 @@ var _ = Describe("Todo", func() {
 						Reminder:    &timestamp.Timestamp{Seconds: int64(666)},
 						State:       pb.ToDo_IN_PROGRESS,
 					},
+					UpdateMask: &field_mask.FieldMask{Paths: []string{"title", "state"}},
 				}
 				mock.response = &pb.UpdateResponse{Updated: 0}
 
@@ -185,7 +244,7 @@ var _ = Describe("Todo", func() {
 					Tags:        []string{"", ""},
 					Reminder:    666,
 					State:       "IN_PROGRESS",
-				})).Should(Equal(false))
+				}, []string{client.FieldTitle, client.FieldState})).Should(Equal(false))
 			})
 		})
 
@@ -200,6 +259,7 @@ var _ = Describe("Todo", func() {
 						Reminder:    &timestamp.Timestamp{Seconds: int64(666)},
 						State:       pb.ToDo_IN_PROGRESS,
 					},
+					UpdateMask: &field_mask.FieldMask{Paths: []string{"title", "state"}},
 				}
 				mock.err = errors.New("error")
 				response, err := manager.Update(context.TODO(), client.ToDo{
@@ -209,12 +269,44 @@ var _ = Describe("Todo", func() {
 					Tags:        []string{"", ""},
 					Reminder:    666,
 					State:       "IN_PROGRESS",
-				})
+				}, []string{client.FieldTitle, client.FieldState})
 
 				Ω(response).Should(Equal(false))
 				Ω(err).Should(Equal(mock.err))
 			})
 		})
+
+		Context("With a todo which has changed since its version", func() {
+			It("should return ErrAborted", func() {
+				mock.expectedRequest = &pb.UpdateRequest{
+					ToDo: &pb.ToDo{
+						Id:       "id",
+						Title:    "title",
+						Reminder: &timestamp.Timestamp{},
+						State:    pb.ToDo_NOT_STARTED,
+					},
+					UpdateMask:      &field_mask.FieldMask{Paths: []string{"title"}},
+					ExpectedVersion: 3,
+				}
+				mock.err = status.Error(codes.Aborted, "the todo has changed")
+				_, err := manager.Update(context.TODO(), client.ToDo{
+					ID:      "id",
+					Title:   "title",
+					Version: 3,
+				}, []string{client.FieldTitle})
+
+				Ω(errors.Is(err, client.ErrAborted)).Should(BeTrue())
+			})
+		})
+
+		Context("Without paths", func() {
+			It("should return ErrInvalidArgument without calling the daemon", func() {
+				mock.err = status.Error(codes.Internal, "unexpected call")
+				_, err := manager.Update(context.TODO(), client.ToDo{ID: "id", Title: "title"}, nil)
+
+				Ω(errors.Is(err, client.ErrInvalidArgument)).Should(BeTrue())
+			})
+		})
 	})
 	Describe("Delete", func() {
 		Context("With a correct todo payload", func() {
@@ -224,7 +316,7 @@ var _ = Describe("Todo", func() {
 				}
 				mock.response = &pb.DeleteResponse{Deleted: 6}
 
-				Ω(manager.Delete(context.TODO(), "id")).Should(Equal(true))
+				Ω(manager.Delete(context.TODO(), "id", 0)).Should(Equal(true))
 			})
 		})
 
@@ -235,7 +327,7 @@ var _ = Describe("Todo", func() {
 				}
 				mock.response = &pb.DeleteResponse{Deleted: 0}
 
-				Ω(manager.Delete(context.TODO(), "id")).Should(Equal(false))
+				Ω(manager.Delete(context.TODO(), "id", 0)).Should(Equal(false))
 			})
 		})
 
@@ -245,12 +337,68 @@ var _ = Describe("Todo", func() {
 					Id: "id",
 				}
 				mock.err = errors.New("error")
-				response, err := manager.Delete(context.TODO(), "id")
+				response, err := manager.Delete(context.TODO(), "id", 0)
 
 				Ω(response).Should(Equal(false))
 				Ω(err).Should(Equal(mock.err))
 			})
 		})
+
+		Context("With the expected version", func() {
+			It("should send it", func() {
+				mock.expectedRequest = &pb.DeleteRequest{
+					Id:              "id",
+					ExpectedVersion: 2,
+				}
+				mock.response = &pb.DeleteResponse{Deleted: 1}
+
+				Ω(manager.Delete(context.TODO(), "id", 2)).Should(Equal(true))
+			})
+		})
+	})
+
+	Describe("Trash", func() {
+		Context("With the todos in the trash", func() {
+			It("should iterate them with their deletion time", func() {
+				mock.expectedRequest = &pb.SearchRequest{States: []pb.ToDo_State{}, Trashed: true, OrderBy: "deleted desc"}
+				mock.response = &pb.SearchResponse{ToDos: []*pb.ToDo{
+					{Id: "1", DeletedAt: &timestamp.Timestamp{Seconds: 60}},
+				}}
+
+				it := manager.Iterate(context.TODO(), client.Query{Trashed: true, OrderBy: "deleted desc"})
+				Ω(it.Next()).Should(BeTrue())
+				Ω(it.ToDo().DeletedAt).Should(Equal(time.Unix(60, 0).UTC()))
+				Ω(it.Next()).Should(BeFalse())
+				Ω(it.Err()).Should(BeNil())
+			})
+		})
+
+		Context("With a todo in the trash", func() {
+			It("should restore it", func() {
+				mock.expectedRequest = &pb.RestoreRequest{Id: "id"}
+				mock.response = &pb.RestoreResponse{Restored: 1}
+
+				Ω(manager.Restore(context.TODO(), "id")).Should(BeTrue())
+			})
+
+			It("should purge it", func() {
+				mock.expectedRequest = &pb.PurgeRequest{Id: "id"}
+				mock.response = &pb.PurgeResponse{Purged: 1}
+
+				Ω(manager.Purge(context.TODO(), "id")).Should(BeTrue())
+			})
+		})
+
+		Context("With a todo which is not in the trash", func() {
+			It("should return ErrNotFound", func() {
+				mock.expectedRequest = &pb.PurgeRequest{Id: "id"}
+				mock.err = status.Error(codes.NotFound, "todo not found")
+
+				purged, err := manager.Purge(context.TODO(), "id")
+				Ω(purged).Should(BeFalse())
+				Ω(errors.Is(err, client.ErrNotFound)).Should(BeTrue())
+			})
+		})
 	})
 
 	Describe("Search", func() {
@@ -295,4 +443,167 @@ var _ = Describe("Todo", func() {
 			})
 		})
 	})
+
+	Describe("Iterate", func() {
+		Context("With several pages", func() {
+			It("should walk all of them", func() {
+				mock.pages = []mockPage{
+					{expectedToken: "", response: &pb.SearchResponse{ToDos: []*pb.ToDo{{Id: "1"}, {Id: "2"}}, NextPageToken: "page-2", TotalSize: 3}},
+					{expectedToken: "page-2", response: &pb.SearchResponse{ToDos: []*pb.ToDo{{Id: "3"}}, TotalSize: 3}},
+				}
+
+				it := manager.Iterate(context.TODO(), client.Query{OrderBy: "title desc", PageSize: 2})
+				ids := []string{}
+				for it.Next() {
+					ids = append(ids, it.ToDo().ID)
+				}
+				Ω(it.Err()).Should(BeNil())
+				Ω(ids).Should(Equal([]string{"1", "2", "3"}))
+				Ω(it.TotalSize()).Should(Equal(int64(3)))
+			})
+		})
+
+		Context("With an issue", func() {
+			It("should stop with the error", func() {
+				mock.expectedRequest = &pb.SearchRequest{States: []pb.ToDo_State{}, OrderBy: "nope"}
+				mock.err = status.Error(codes.InvalidArgument, "unknown order")
+
+				it := manager.Iterate(context.TODO(), client.Query{OrderBy: "nope"})
+				Ω(it.Next()).Should(BeFalse())
+				Ω(errors.Is(it.Err(), client.ErrInvalidArgument)).Should(BeTrue())
+			})
+		})
+
+		Context("With time ranges", func() {
+			It("should send the bounds and return the timestamps", func() {
+				completed := time.Unix(1573046180, 0).UTC()
+				mock.expectedRequest = &pb.SearchRequest{
+					States:      []pb.ToDo_State{},
+					OrderBy:     "completed",
+					CompletedAt: &pb.TimeRange{After: &timestamp.Timestamp{Seconds: 1573046180}},
+				}
+				mock.response = &pb.SearchResponse{ToDos: []*pb.ToDo{{Id: "1", State: pb.ToDo_DONE, CompletedAt: &timestamp.Timestamp{Seconds: 1573046180}}}, TotalSize: 1}
+
+				it := manager.Iterate(context.TODO(), client.Query{OrderBy: "completed", Completed: client.TimeRange{After: completed}})
+				Ω(it.Next()).Should(BeTrue())
+				Ω(it.ToDo().CompletedAt).Should(Equal(completed))
+				Ω(it.ToDo().CreatedAt.IsZero()).Should(BeTrue())
+				Ω(it.Next()).Should(BeFalse())
+				Ω(it.Err()).Should(BeNil())
+			})
+		})
+	})
+
+	Describe("Stream", func() {
+		Context("With todos", func() {
+			It("should call fn on each of them", func() {
+				mock.expectedRequest = &pb.SearchRequest{Pattern: "micro", MatchMode: pb.SearchRequest_FULLTEXT, Tags: []string{"golang"}, States: []pb.ToDo_State{pb.ToDo_DONE}, OrderBy: "title"}
+				mock.response = &mockSearchStreamClient{todos: []*pb.ToDo{{Id: "1"}, {Id: "2"}}}
+
+				ids := []string{}
+				query := client.Query{Pattern: "micro", MatchMode: "fulltext", Tags: []string{"golang"}, States: []string{"DONE"}, OrderBy: "title", PageSize: 1}
+				err := manager.Stream(context.TODO(), query, func(todo client.ToDo) error {
+					ids = append(ids, todo.ID)
+					return nil
+				})
+				Ω(err).Should(BeNil())
+				Ω(ids).Should(Equal([]string{"1", "2"}))
+			})
+		})
+
+		Context("With an error in fn", func() {
+			It("should stop and return it", func() {
+				mock.expectedRequest = &pb.SearchRequest{States: []pb.ToDo_State{}}
+				mock.response = &mockSearchStreamClient{todos: []*pb.ToDo{{Id: "1"}, {Id: "2"}}}
+				stop := errors.New("stop")
+
+				calls := 0
+				err := manager.Stream(context.TODO(), client.Query{}, func(todo client.ToDo) error {
+					calls++
+					return stop
+				})
+				Ω(err).Should(Equal(stop))
+				Ω(calls).Should(Equal(1))
+			})
+		})
+
+		Context("With an issue in the middle of the stream", func() {
+			It("should return the error of the status", func() {
+				mock.expectedRequest = &pb.SearchRequest{States: []pb.ToDo_State{}}
+				mock.response = &mockSearchStreamClient{todos: []*pb.ToDo{{Id: "1"}}, err: status.Error(codes.Unavailable, "gone")}
+
+				err := manager.Stream(context.TODO(), client.Query{}, func(todo client.ToDo) error { return nil })
+				Ω(errors.Is(err, client.ErrUnavailable)).Should(BeTrue())
+			})
+		})
+	})
+
+	Describe("Watch", func() {
+		Context("With events", func() {
+			It("should call fn on each of them", func() {
+				mock.expectedRequest = &pb.WatchRequest{Tags: []string{"golang"}, States: []pb.ToDo_State{}}
+				mock.response = &mockWatchClient{events: []*pb.Event{
+					{Type: pb.Event_CREATED, ToDo: &pb.ToDo{Id: "1", Title: "a"}},
+					{Type: pb.Event_UPDATED, ToDo: &pb.ToDo{Id: "1", Title: "b"}, Previous: &pb.ToDo{Id: "1", Title: "a"}},
+				}}
+
+				events := []client.Event{}
+				err := manager.Watch(context.TODO(), client.Query{Tags: []string{"golang"}}, func(e client.Event) error {
+					events = append(events, e)
+					return nil
+				})
+				Ω(err).Should(BeNil())
+				Ω(events).Should(HaveLen(2))
+				Ω(events[0].Type).Should(Equal("CREATED"))
+				Ω(events[0].Previous).Should(BeNil())
+				Ω(events[1].Type).Should(Equal("UPDATED"))
+				Ω(events[1].ToDo.Title).Should(Equal("b"))
+				Ω(events[1].Previous.Title).Should(Equal("a"))
+			})
+		})
+
+		Context("With an issue", func() {
+			It("should return the error of the status", func() {
+				mock.expectedRequest = &pb.WatchRequest{States: []pb.ToDo_State{}}
+				mock.response = &mockWatchClient{err: status.Error(codes.Unavailable, "shutting down")}
+
+				err := manager.Watch(context.TODO(), client.Query{}, func(e client.Event) error { return nil })
+				Ω(errors.Is(err, client.ErrUnavailable)).Should(BeTrue())
+			})
+		})
+	})
+
+	Describe("History", func() {
+		Context("With a todo which has changed", func() {
+			It("should return its records", func() {
+				mock.expectedRequest = &pb.GetHistoryRequest{Id: "1"}
+				mock.response = &pb.GetHistoryResponse{Records: []*pb.HistoryRecord{
+					{ToDoId: "1", Type: pb.Event_CREATED, Actor: "alice", Time: &timestamp.Timestamp{Seconds: 60}, RequestId: "r1", Version: 1,
+						Changes: []*pb.HistoryRecord_Change{{Field: "title", NewValue: "a"}}},
+					{ToDoId: "1", Type: pb.Event_UPDATED, Actor: "bob", Time: &timestamp.Timestamp{Seconds: 120}, RequestId: "r2", Version: 2,
+						Changes: []*pb.HistoryRecord_Change{{Field: "title", OldValue: "a", NewValue: "b"}}},
+				}}
+
+				records, err := manager.History(context.TODO(), "1")
+				Ω(err).Should(BeNil())
+				Ω(records).Should(Equal([]client.HistoryRecord{
+					{Type: "CREATED", Actor: "alice", Time: time.Unix(60, 0).UTC(), RequestID: "r1", Version: 1,
+						Changes: []client.Change{{Field: "title", NewValue: "a"}}},
+					{Type: "UPDATED", Actor: "bob", Time: time.Unix(120, 0).UTC(), RequestID: "r2", Version: 2,
+						Changes: []client.Change{{Field: "title", OldValue: "a", NewValue: "b"}}},
+				}))
+			})
+		})
+
+		Context("With a todo which is not found by the daemon", func() {
+			It("should return ErrNotFound", func() {
+				mock.expectedRequest = &pb.GetHistoryRequest{Id: "1"}
+				mock.err = status.Error(codes.NotFound, "todo not found")
+
+				records, err := manager.History(context.TODO(), "1")
+				Ω(records).Should(BeNil())
+				Ω(errors.Is(err, client.ErrNotFound)).Should(BeTrue())
+			})
+		})
+	})
 })
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The errors returned by the daemon, use errors.Is to check them.
+var (
+	// ErrNotFound the todo doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidArgument the request is not valid, see Violations
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrUnavailable the daemon or its store can't be reached
+	ErrUnavailable = errors.New("unavailable")
+	// ErrDeadlineExceeded the daemon didn't answer in time
+	ErrDeadlineExceeded = errors.New("deadline exceeded")
+	// ErrInternal the daemon failed
+	ErrInternal = errors.New("internal error")
+	// ErrUnauthenticated the token is missing or not valid
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrPermissionDenied the caller can't access the todos of another owner or lacks a role on a list
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrFailedPrecondition the state forbids the request, ex: the deletion of a list with todos
+	ErrFailedPrecondition = errors.New("failed precondition")
+	// ErrResourceExhausted the tenant has reached its quota or the client calls too often, see RetryDelay
+	ErrResourceExhausted = errors.New("resource exhausted")
+	// ErrAborted the todo has changed since its version was read, read it again then retry
+	ErrAborted = errors.New("aborted")
+	// ErrUnimplemented the daemon doesn't support the call, ex: an atomic batch on a store without transaction
+	ErrUnimplemented = errors.New("unimplemented")
+)
+
+var sentinels = map[codes.Code]error{
+	codes.NotFound:           ErrNotFound,
+	codes.InvalidArgument:    ErrInvalidArgument,
+	codes.Unavailable:        ErrUnavailable,
+	codes.DeadlineExceeded:   ErrDeadlineExceeded,
+	codes.Internal:           ErrInternal,
+	codes.Unauthenticated:    ErrUnauthenticated,
+	codes.PermissionDenied:   ErrPermissionDenied,
+	codes.FailedPrecondition: ErrFailedPrecondition,
+	codes.ResourceExhausted:  ErrResourceExhausted,
+	codes.Aborted:            ErrAborted,
+	codes.Unimplemented:      ErrUnimplemented,
+}
+
+// Error an error returned by the daemon
+type Error struct {
+	// Status the gRPC status
+	Status *status.Status
+
+	sentinel error
+}
+
+// Error the message of the status
+func (e *Error) Error() string {
+	return e.Status.Message()
+}
+
+// Unwrap returns the sentinel error matching the code
+func (e *Error) Unwrap() error {
+	return e.sentinel
+}
+
+// Violations returns the fields which are not valid
+func (e *Error) Violations() map[string]string {
+	result := map[string]string{}
+	for _, detail := range e.Status.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				result[violation.GetField()] = violation.GetDescription()
+			}
+		}
+	}
+	return result
+}
+
+// RetryDelay returns how long to wait before calling again when the client calls too often
+func (e *Error) RetryDelay() (time.Duration, bool) {
+	for _, detail := range e.Status.Details() {
+		if retry, ok := detail.(*errdetails.RetryInfo); ok {
+			delay, err := ptypes.Duration(retry.GetRetryDelay())
+			return delay, err == nil
+		}
+	}
+	return 0, false
+}
+
+// fromStatus wraps the status errors with a sentinel in an Error, the other errors are kept
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	sentinel, ok := sentinels[st.Code()]
+	if !ok {
+		return err
+	}
+	return &Error{Status: st, sentinel: sentinel}
+}
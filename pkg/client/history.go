@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// HistoryRecord a change on a todo
+type HistoryRecord struct {
+	//Type CREATED, UPDATED or DELETED
+	Type string
+	//Actor the subject who made the change
+	Actor string
+	//Time of the change
+	Time time.Time
+	//RequestID the ID of the request which made the change
+	RequestID string
+	//Version of the todo after the change
+	Version int64
+	//Changes the fields which changed
+	Changes []Change
+}
+
+// Change the old and the new values of a field, empty if it had or has no value
+type Change struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// History returns the changes on a todo from the oldest to the newest, the error is ErrNotFound if it neither exists nor has a history
+func (m *ToDoManager) History(cxt context.Context, id string) ([]HistoryRecord, error) {
+	response, err := m.Client.GetHistory(cxt, &pb.GetHistoryRequest{Id: id})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	result := []HistoryRecord{}
+	for _, r := range response.GetRecords() {
+		record := HistoryRecord{
+			Type:      r.GetType().String(),
+			Actor:     r.GetActor(),
+			Time:      toTime(r.GetTime()),
+			RequestID: r.GetRequestId(),
+			Version:   r.GetVersion(),
+			Changes:   []Change{},
+		}
+		for _, c := range r.GetChanges() {
+			record.Changes = append(record.Changes, Change{Field: c.GetField(), OldValue: c.GetOldValue(), NewValue: c.GetNewValue()})
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
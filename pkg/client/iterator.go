@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// Query the filters and the order of a search
+type Query struct {
+	//Pattern on the description
+	Pattern string
+	//MatchMode how the pattern matches: LITERAL if empty, PREFIX, REGEX or FULLTEXT
+	MatchMode string
+	//Tags the todos must have all of them
+	Tags []string
+	//States the todos must have one of them
+	States []string
+	//OrderBy created, updated, completed, deleted, reminder, title or state followed by " desc" for a descending order
+	OrderBy string
+	//PageSize the number of todos by call, the daemon chooses if 0
+	PageSize int32
+	//Owner of the todos, only an admin can choose another owner than the caller out of a list
+	Owner string
+	//ListID the list of the todos, the members of the list get all its todos
+	ListID string
+	//Created the range of the creation times
+	Created TimeRange
+	//Updated the range of the last change times
+	Updated TimeRange
+	//Completed the range of the completion times, the todos which are not DONE are excluded if it has a bound
+	Completed TimeRange
+	//Trashed searches the todos in the trash instead of the others
+	Trashed bool
+	//Deleted the range of the times the todos moved to the trash
+	Deleted TimeRange
+}
+
+// TimeRange the times from After included to Before excluded, a zero bound is open
+type TimeRange struct {
+	After  time.Time
+	Before time.Time
+}
+
+// toTimeRange returns the range, nil if both bounds are open
+func toTimeRange(r TimeRange) *pb.TimeRange {
+	if r.After.IsZero() && r.Before.IsZero() {
+		return nil
+	}
+	result := &pb.TimeRange{}
+	if !r.After.IsZero() {
+		result.After, _ = ptypes.TimestampProto(r.After)
+	}
+	if !r.Before.IsZero() {
+		result.Before, _ = ptypes.TimestampProto(r.Before)
+	}
+	return result
+}
+
+// The match modes of a query
+const (
+	MatchLiteral  = "LITERAL"
+	MatchPrefix   = "PREFIX"
+	MatchRegex    = "REGEX"
+	MatchFullText = "FULLTEXT"
+)
+
+// toMatchMode returns the match mode, an unknown mode is sent as is so the daemon refuses it
+func toMatchMode(mode string) pb.SearchRequest_MatchMode {
+	if mode == "" {
+		return pb.SearchRequest_LITERAL
+	}
+	value, ok := pb.SearchRequest_MatchMode_value[strings.ToUpper(mode)]
+	if !ok {
+		return -1
+	}
+	return pb.SearchRequest_MatchMode(value)
+}
+
+// Iterator walks all the pages of a search
+//
+//	it := manager.Iterate(ctx, client.Query{Tags: []string{"job"}})
+//	for it.Next() {
+//		fmt.Println(it.ToDo())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	ctx     context.Context
+	client  pb.ToDoServiceClient
+	request *pb.SearchRequest
+
+	page    []*pb.ToDo
+	current *pb.ToDo
+	total   int64
+	last    bool
+	err     error
+}
+
+// Iterate returns an iterator on the todos matching the query, the pages are fetched when needed
+func (m *ToDoManager) Iterate(cxt context.Context, q Query) *Iterator {
+	return &Iterator{
+		ctx:    cxt,
+		client: m.Client,
+		request: &pb.SearchRequest{
+			Pattern:     q.Pattern,
+			MatchMode:   toMatchMode(q.MatchMode),
+			Tags:        q.Tags,
+			States:      toState(q.States),
+			OrderBy:     q.OrderBy,
+			PageSize:    q.PageSize,
+			Owner:       q.Owner,
+			ListId:      q.ListID,
+			CreatedAt:   toTimeRange(q.Created),
+			UpdatedAt:   toTimeRange(q.Updated),
+			CompletedAt: toTimeRange(q.Completed),
+			Trashed:     q.Trashed,
+			DeletedAt:   toTimeRange(q.Deleted),
+		},
+	}
+}
+
+// Next moves to the next todo, it returns false at the end or on error
+func (it *Iterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.last || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *Iterator) fetch() {
+	response, err := it.client.Search(it.ctx, it.request)
+	if err != nil {
+		it.err = fromStatus(err)
+		return
+	}
+	it.page = response.GetToDos()
+	it.total = response.GetTotalSize()
+	it.request.PageToken = response.GetNextPageToken()
+	it.last = it.request.PageToken == ""
+}
+
+// ToDo the current todo
+func (it *Iterator) ToDo() ToDo {
+	return newToDo(it.current)
+}
+
+// TotalSize the number of todos matching the query, known after the first call of Next
+func (it *Iterator) TotalSize() int64 {
+	return it.total
+}
+
+// Err the error which stopped the iteration
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// List a list of todos shared with other users
+type List struct {
+	//ID of list
+	ID string
+	//Name of list
+	Name string
+	//Owner of list, the caller who created it
+	Owner string
+	//Members the users who share the list
+	Members []Member
+}
+
+// Member a user who shares a list
+type Member struct {
+	//Subject of the user
+	Subject string
+	//Role VIEWER, EDITOR or OWNER
+	Role string
+}
+
+// CreateList creates a list owned by the caller
+func (m *ToDoManager) CreateList(cxt context.Context, name string) (string, error) {
+	response, err := m.Client.CreateList(cxt, &pb.CreateListRequest{List: &pb.List{Name: name}})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return response.GetId(), nil
+}
+
+// ReadList reads a list, the error is ErrNotFound if it doesn't exist or it isn't shared with the caller
+func (m *ToDoManager) ReadList(cxt context.Context, id string) (*List, error) {
+	response, err := m.Client.ReadList(cxt, &pb.ReadListRequest{Id: id})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	list := newList(response.GetList())
+	return &list, nil
+}
+
+// RenameList renames a list
+func (m *ToDoManager) RenameList(cxt context.Context, id string, name string) (bool, error) {
+	response, err := m.Client.UpdateList(cxt, &pb.UpdateListRequest{List: &pb.List{Id: id, Name: name}})
+	if err != nil {
+		return false, fromStatus(err)
+	}
+	return response.GetUpdated() > 0, nil
+}
+
+// DeleteList deletes a list, the error is ErrFailedPrecondition if the list has todos
+func (m *ToDoManager) DeleteList(cxt context.Context, id string) (bool, error) {
+	response, err := m.Client.DeleteList(cxt, &pb.DeleteListRequest{Id: id})
+	if err != nil {
+		return false, fromStatus(err)
+	}
+	return response.GetDeleted() > 0, nil
+}
+
+// Lists returns the lists owned by or shared with the caller
+func (m *ToDoManager) Lists(cxt context.Context) ([]List, error) {
+	response, err := m.Client.SearchLists(cxt, &pb.SearchListsRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	result := []List{}
+	for _, list := range response.GetLists() {
+		result = append(result, newList(list))
+	}
+	return result, nil
+}
+
+// ShareList gives the role VIEWER, EDITOR or OWNER on a list to the user subject
+func (m *ToDoManager) ShareList(cxt context.Context, id string, subject string, role string) (*List, error) {
+	value, ok := pb.List_Role_value[role]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidArgument, role)
+	}
+	response, err := m.Client.ShareList(cxt, &pb.ShareListRequest{Id: id, Subject: subject, Role: pb.List_Role(value)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	list := newList(response.GetList())
+	return &list, nil
+}
+
+// UnshareList removes the user subject from the members of a list
+func (m *ToDoManager) UnshareList(cxt context.Context, id string, subject string) (*List, error) {
+	response, err := m.Client.UnshareList(cxt, &pb.UnshareListRequest{Id: id, Subject: subject})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	list := newList(response.GetList())
+	return &list, nil
+}
+
+func newList(list *pb.List) List {
+	result := List{
+		ID:    list.GetId(),
+		Name:  list.GetName(),
+		Owner: list.GetOwner(),
+	}
+	for _, member := range list.GetMembers() {
+		result.Members = append(result.Members, Member{Subject: member.GetSubject(), Role: member.GetRole().String()})
+	}
+	return result
+}
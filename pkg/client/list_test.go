@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sjeandeaux/todo/pkg/client"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("List", func() {
+	var (
+		manager *client.ToDoManager
+		mock    *mockToDoServiceClient
+	)
+
+	BeforeEach(func() {
+		mock = &mockToDoServiceClient{}
+		manager = &client.ToDoManager{
+			Client: mock,
+		}
+	})
+
+	Describe("CreateList", func() {
+		It("should return the ID", func() {
+			mock.expectedRequest = &pb.CreateListRequest{List: &pb.List{Name: "Groceries"}}
+			mock.response = &pb.CreateListResponse{Id: "id"}
+			Ω(manager.CreateList(context.TODO(), "Groceries")).Should(Equal("id"))
+		})
+	})
+
+	Describe("Lists", func() {
+		It("should convert the lists", func() {
+			mock.expectedRequest = &pb.SearchListsRequest{}
+			mock.response = &pb.SearchListsResponse{Lists: []*pb.List{{
+				Id:      "id",
+				Name:    "Groceries",
+				Owner:   "alice",
+				Members: []*pb.List_Member{{Subject: "bob", Role: pb.List_EDITOR}},
+			}}}
+			Ω(manager.Lists(context.TODO())).Should(Equal([]client.List{{
+				ID:      "id",
+				Name:    "Groceries",
+				Owner:   "alice",
+				Members: []client.Member{{Subject: "bob", Role: "EDITOR"}},
+			}}))
+		})
+	})
+
+	Describe("ShareList", func() {
+		It("should send the role", func() {
+			mock.expectedRequest = &pb.ShareListRequest{Id: "id", Subject: "bob", Role: pb.List_OWNER}
+			mock.response = &pb.ShareListResponse{List: &pb.List{Id: "id", Members: []*pb.List_Member{{Subject: "bob", Role: pb.List_OWNER}}}}
+			Ω(manager.ShareList(context.TODO(), "id", "bob", "OWNER")).Should(Equal(&client.List{
+				ID:      "id",
+				Members: []client.Member{{Subject: "bob", Role: "OWNER"}},
+			}))
+		})
+
+		It("should refuse an unknown role", func() {
+			_, err := manager.ShareList(context.TODO(), "id", "bob", "READER")
+			Ω(errors.Is(err, client.ErrInvalidArgument)).Should(BeTrue())
+		})
+
+		It("should return ErrPermissionDenied without the role OWNER", func() {
+			mock.expectedRequest = &pb.ShareListRequest{Id: "id", Subject: "bob"}
+			mock.err = status.Error(codes.PermissionDenied, "the role OWNER is required on the list")
+			_, err := manager.ShareList(context.TODO(), "id", "bob", "VIEWER")
+			Ω(errors.Is(err, client.ErrPermissionDenied)).Should(BeTrue())
+		})
+	})
+
+	Describe("DeleteList", func() {
+		It("should return ErrFailedPrecondition when the list has todos", func() {
+			mock.expectedRequest = &pb.DeleteListRequest{Id: "id"}
+			mock.err = status.Error(codes.FailedPrecondition, "the list still has 2 todos")
+			_, err := manager.DeleteList(context.TODO(), "id")
+			Ω(errors.Is(err, client.ErrFailedPrecondition)).Should(BeTrue())
+		})
+	})
+})
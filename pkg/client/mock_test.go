@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"context"
+	"io"
 
 	. "github.com/onsi/gomega"
 	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
@@ -13,6 +14,14 @@ type mockToDoServiceClient struct {
 
 	response interface{}
 	err      error
+
+	//pages the responses of the successive calls of Search with their expected page tokens
+	pages []mockPage
+}
+
+type mockPage struct {
+	expectedToken string
+	response      *pb.SearchResponse
 }
 
 var _ pb.ToDoServiceClient = &mockToDoServiceClient{}
@@ -50,9 +59,173 @@ func (s *mockToDoServiceClient) Delete(ctx context.Context, r *pb.DeleteRequest,
 }
 
 func (s *mockToDoServiceClient) Search(ctx context.Context, r *pb.SearchRequest, opts ...grpc.CallOption) (*pb.SearchResponse, error) {
+	if len(s.pages) > 0 {
+		page := s.pages[0]
+		s.pages = s.pages[1:]
+		Ω(r.GetPageToken()).Should(Equal(page.expectedToken))
+		return page.response, nil
+	}
 	Ω(r).Should(Equal(s.expectedRequest))
 	if s.response == nil {
 		return nil, s.err
 	}
 	return s.response.(*pb.SearchResponse), s.err
 }
+
+func (s *mockToDoServiceClient) SearchStream(ctx context.Context, r *pb.SearchRequest, opts ...grpc.CallOption) (pb.ToDoService_SearchStreamClient, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*mockSearchStreamClient), nil
+}
+
+// mockSearchStreamClient receives the todos then err or io.EOF
+type mockSearchStreamClient struct {
+	grpc.ClientStream
+	todos []*pb.ToDo
+	err   error
+}
+
+func (s *mockSearchStreamClient) Recv() (*pb.ToDo, error) {
+	if len(s.todos) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	todo := s.todos[0]
+	s.todos = s.todos[1:]
+	return todo, nil
+}
+
+func (s *mockToDoServiceClient) CreateList(ctx context.Context, r *pb.CreateListRequest, opts ...grpc.CallOption) (*pb.CreateListResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.CreateListResponse), s.err
+}
+
+func (s *mockToDoServiceClient) ReadList(ctx context.Context, r *pb.ReadListRequest, opts ...grpc.CallOption) (*pb.ReadListResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.ReadListResponse), s.err
+}
+
+func (s *mockToDoServiceClient) UpdateList(ctx context.Context, r *pb.UpdateListRequest, opts ...grpc.CallOption) (*pb.UpdateListResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.UpdateListResponse), s.err
+}
+
+func (s *mockToDoServiceClient) DeleteList(ctx context.Context, r *pb.DeleteListRequest, opts ...grpc.CallOption) (*pb.DeleteListResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.DeleteListResponse), s.err
+}
+
+func (s *mockToDoServiceClient) SearchLists(ctx context.Context, r *pb.SearchListsRequest, opts ...grpc.CallOption) (*pb.SearchListsResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.SearchListsResponse), s.err
+}
+
+func (s *mockToDoServiceClient) ShareList(ctx context.Context, r *pb.ShareListRequest, opts ...grpc.CallOption) (*pb.ShareListResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.ShareListResponse), s.err
+}
+
+func (s *mockToDoServiceClient) UnshareList(ctx context.Context, r *pb.UnshareListRequest, opts ...grpc.CallOption) (*pb.UnshareListResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.UnshareListResponse), s.err
+}
+
+func (s *mockToDoServiceClient) Watch(ctx context.Context, r *pb.WatchRequest, opts ...grpc.CallOption) (pb.ToDoService_WatchClient, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*mockWatchClient), nil
+}
+
+// mockWatchClient receives the events then err or io.EOF
+type mockWatchClient struct {
+	grpc.ClientStream
+	events []*pb.Event
+	err    error
+}
+
+func (s *mockWatchClient) Recv() (*pb.Event, error) {
+	if len(s.events) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	e := s.events[0]
+	s.events = s.events[1:]
+	return e, nil
+}
+
+func (s *mockToDoServiceClient) GetHistory(ctx context.Context, r *pb.GetHistoryRequest, opts ...grpc.CallOption) (*pb.GetHistoryResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.GetHistoryResponse), s.err
+}
+
+func (s *mockToDoServiceClient) Restore(ctx context.Context, r *pb.RestoreRequest, opts ...grpc.CallOption) (*pb.RestoreResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.RestoreResponse), s.err
+}
+
+func (s *mockToDoServiceClient) Purge(ctx context.Context, r *pb.PurgeRequest, opts ...grpc.CallOption) (*pb.PurgeResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.PurgeResponse), s.err
+}
+
+func (s *mockToDoServiceClient) BatchCreate(ctx context.Context, r *pb.BatchCreateRequest, opts ...grpc.CallOption) (*pb.BatchCreateResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.BatchCreateResponse), s.err
+}
+
+func (s *mockToDoServiceClient) BatchUpdate(ctx context.Context, r *pb.BatchUpdateRequest, opts ...grpc.CallOption) (*pb.BatchUpdateResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.BatchUpdateResponse), s.err
+}
+
+func (s *mockToDoServiceClient) BatchDelete(ctx context.Context, r *pb.BatchDeleteRequest, opts ...grpc.CallOption) (*pb.BatchDeleteResponse, error) {
+	Ω(r).Should(Equal(s.expectedRequest))
+	if s.response == nil {
+		return nil, s.err
+	}
+	return s.response.(*pb.BatchDeleteResponse), s.err
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// Stream calls fn on each todo matching the query as soon as the daemon sends it,
+// the page size of the query is ignored. It stops on the first error of fn and returns it.
+func (m *ToDoManager) Stream(cxt context.Context, q Query, fn func(ToDo) error) error {
+	ctx, cancel := context.WithCancel(cxt)
+	defer cancel()
+
+	stream, err := m.Client.SearchStream(ctx, &pb.SearchRequest{
+		Pattern:     q.Pattern,
+		MatchMode:   toMatchMode(q.MatchMode),
+		Tags:        q.Tags,
+		States:      toState(q.States),
+		OrderBy:     q.OrderBy,
+		Owner:       q.Owner,
+		ListId:      q.ListID,
+		CreatedAt:   toTimeRange(q.Created),
+		UpdatedAt:   toTimeRange(q.Updated),
+		CompletedAt: toTimeRange(q.Completed),
+		Trashed:     q.Trashed,
+		DeletedAt:   toTimeRange(q.Deleted),
+	})
+	if err != nil {
+		return fromStatus(err)
+	}
+	for {
+		todo, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+		if err := fn(newToDo(todo)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// Token the per-RPC credentials which send a bearer token to the daemon
+type Token struct {
+	// Value the API key or the JWT
+	Value string
+	// AllowInsecure sends the token on a connection without TLS
+	AllowInsecure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t Token) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.Value}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+func (t Token) RequireTransportSecurity() bool {
+	return !t.AllowInsecure
+}
+
+var _ credentials.PerRPCCredentials = Token{}
+
+// Tenant the per-RPC credentials which choose the tenant of the calls when the token doesn't have one
+type Tenant string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t Tenant) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"x-tenant": string(t)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials, the tenant isn't a secret
+func (t Tenant) RequireTransportSecurity() bool {
+	return false
+}
+
+var _ credentials.PerRPCCredentials = Tenant("")
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// Event a change on a todo
+type Event struct {
+	//Type CREATED, UPDATED or DELETED
+	Type string
+	//ToDo after the change, the deleted todo on DELETED
+	ToDo ToDo
+	//Previous the todo before the change on UPDATED
+	Previous *ToDo
+}
+
+// Watch calls fn on each change on the todos matching the filters of the query until ctx is done,
+// the order and the page size are ignored. It stops on the first error of fn and returns it.
+func (m *ToDoManager) Watch(cxt context.Context, q Query, fn func(Event) error) error {
+	ctx, cancel := context.WithCancel(cxt)
+	defer cancel()
+
+	stream, err := m.Client.Watch(ctx, &pb.WatchRequest{
+		Pattern:   q.Pattern,
+		MatchMode: toMatchMode(q.MatchMode),
+		Tags:      q.Tags,
+		States:    toState(q.States),
+		Owner:     q.Owner,
+		ListId:    q.ListID,
+	})
+	if err != nil {
+		return fromStatus(err)
+	}
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+		event := Event{Type: e.GetType().String(), ToDo: newToDo(e.GetToDo())}
+		if e.GetPrevious() != nil {
+			previous := newToDo(e.GetPrevious())
+			event.Previous = &previous
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package event publishes the changes on the todos to the subscribers in the process.
+package event
+
+import (
+	"errors"
+	"sync"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// DefaultBuffer the number of events kept for a subscriber which doesn't read them
+const DefaultBuffer = 256
+
+var (
+	// ErrOverflow is returned when the subscriber didn't read the events fast enough.
+	ErrOverflow = errors.New("too many pending events")
+	// ErrClosed is returned when the bus is closed.
+	ErrClosed = errors.New("event bus closed")
+)
+
+// Bus dispatches the events to all the subscribers, it never blocks the publisher:
+// a subscriber whose buffer is full is dropped with ErrOverflow.
+type Bus struct {
+	mu          sync.Mutex
+	buffer      int
+	closed      bool
+	subscribers map[*Subscription]bool
+}
+
+// NewBus creates a bus, each subscriber keeps at most buffer events
+func NewBus(buffer int) *Bus {
+	return &Bus{
+		buffer:      buffer,
+		subscribers: make(map[*Subscription]bool),
+	}
+}
+
+// Subscription receives the events published after its creation
+type Subscription struct {
+	bus    *Bus
+	events chan *pb.Event
+	err    error
+}
+
+// Subscribe returns a new subscription, the caller must close it
+func (b *Bus) Subscribe() *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := &Subscription{bus: b, events: make(chan *pb.Event, b.buffer)}
+	if b.closed {
+		s.err = ErrClosed
+		close(s.events)
+		return s
+	}
+	b.subscribers[s] = true
+	return s
+}
+
+// Publish sends the event to the subscribers
+func (b *Bus) Publish(e *pb.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subscribers {
+		select {
+		case s.events <- e:
+		default:
+			b.drop(s, ErrOverflow)
+		}
+	}
+}
+
+// Subscribed tells if there is at least one subscriber
+func (b *Bus) Subscribed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers) > 0
+}
+
+// Close ends all the subscriptions with ErrClosed
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for s := range b.subscribers {
+		b.drop(s, ErrClosed)
+	}
+}
+
+// drop ends the subscription, the lock must be held
+func (b *Bus) drop(s *Subscription, err error) {
+	delete(b.subscribers, s)
+	s.err = err
+	close(s.events)
+}
+
+// Events the channel of the events, it is closed when the subscription ends
+func (s *Subscription) Events() <-chan *pb.Event {
+	return s.events
+}
+
+// Err the reason of the end of the subscription once Events is closed, nil after Close
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if s.bus.subscribers[s] {
+		s.bus.drop(s, nil)
+	}
+}
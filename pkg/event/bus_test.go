@@ -0,0 +1,88 @@
+package event_test
+
+import (
+	"testing"
+
+	. "github.com/sjeandeaux/todo/pkg/event"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEvent(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Event Suite")
+}
+
+var _ = Describe("Bus", func() {
+
+	var bus *Bus
+
+	BeforeEach(func() {
+		bus = NewBus(2)
+	})
+
+	event := func(id string) *pb.Event {
+		return &pb.Event{Type: pb.Event_CREATED, ToDo: &pb.ToDo{Id: id}}
+	}
+
+	Context("With several subscribers", func() {
+		It("should send the events to each of them", func() {
+			first := bus.Subscribe()
+			defer first.Close()
+			second := bus.Subscribe()
+			defer second.Close()
+
+			bus.Publish(event("1"))
+			Ω(<-first.Events()).Should(Equal(event("1")))
+			Ω(<-second.Events()).Should(Equal(event("1")))
+		})
+	})
+
+	Context("With a closed subscription", func() {
+		It("should not receive the events", func() {
+			s := bus.Subscribe()
+			s.Close()
+			bus.Publish(event("1"))
+
+			_, ok := <-s.Events()
+			Ω(ok).Should(BeFalse())
+			Ω(s.Err()).Should(BeNil())
+		})
+	})
+
+	Context("With a slow subscriber", func() {
+		It("should drop it without blocking the others", func() {
+			slow := bus.Subscribe()
+			fast := bus.Subscribe()
+			defer fast.Close()
+
+			for _, id := range []string{"1", "2", "3"} {
+				bus.Publish(event(id))
+				Ω(<-fast.Events()).Should(Equal(event(id)))
+			}
+
+			Ω(<-slow.Events()).Should(Equal(event("1")))
+			Ω(<-slow.Events()).Should(Equal(event("2")))
+			_, ok := <-slow.Events()
+			Ω(ok).Should(BeFalse())
+			Ω(slow.Err()).Should(Equal(ErrOverflow))
+		})
+	})
+
+	Context("With a closed bus", func() {
+		It("should end the subscriptions", func() {
+			before := bus.Subscribe()
+			bus.Close()
+			after := bus.Subscribe()
+
+			for _, s := range []*Subscription{before, after} {
+				_, ok := <-s.Events()
+				Ω(ok).Should(BeFalse())
+				Ω(s.Err()).Should(Equal(ErrClosed))
+				s.Close()
+			}
+		})
+	})
+})
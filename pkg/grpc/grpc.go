@@ -2,26 +2,48 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/sjeandeaux/todo/pkg/auth"
+	"github.com/sjeandeaux/todo/pkg/ratelimit"
 	"github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/tenant"
 	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
 
 	"google.golang.org/grpc/health/grpc_health_v1"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 
 	"google.golang.org/grpc/reflection"
 )
 
+// replaceGrpcLogger the logger of grpc is global, it can't change while a connection uses it
+var replaceGrpcLogger sync.Once
+
+// Options the security of the grpc server
+type Options struct {
+	// TLS the configuration of TLS, plaintext if nil
+	TLS *tls.Config
+	// Authenticator checks the bearer token of the calls except the health checks, no authentication if nil
+	Authenticator auth.Authenticator
+	// Tenants resolves the tenant of the calls except the health checks, single tenant if nil
+	Tenants *tenant.Resolver
+	// Limiter refuses the calls of the clients which call too often except the health checks, no limit if nil
+	Limiter *ratelimit.Limiter
+}
+
 // RunServer runs the grpc server on port port
-func RunServer(ctx context.Context, host string, port string, server *service.ToDoServiceServer) (int, error) {
+func RunServer(ctx context.Context, host string, port string, server *service.ToDoServiceServer, options Options) (int, error) {
 	const ProtoTCP = "tcp"
 	lis, err := net.Listen(ProtoTCP, net.JoinHostPort(host, port))
 	if err != nil {
@@ -29,19 +51,38 @@ func RunServer(ctx context.Context, host string, port string, server *service.To
 	}
 
 	logrusEntry := log.NewEntry(log.StandardLogger())
-	grpc_logrus.ReplaceGrpcLogger(logrusEntry)
+	replaceGrpcLogger.Do(func() { grpc_logrus.ReplaceGrpcLogger(logrusEntry) })
 
 	ctx, cancel := context.WithCancel(ctx)
-	grpcServer := grpc.NewServer(
-		grpc_middleware.WithUnaryServerChain(
-			grpc_prometheus.UnaryServerInterceptor,
-			grpc_logrus.UnaryServerInterceptor(logrusEntry),
-		),
-		grpc_middleware.WithStreamServerChain(
-			grpc_prometheus.StreamServerInterceptor,
-			grpc_logrus.StreamServerInterceptor(logrusEntry),
-		),
-	)
+	unary := []grpc.UnaryServerInterceptor{
+		grpc_prometheus.UnaryServerInterceptor,
+		grpc_logrus.UnaryServerInterceptor(logrusEntry),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		grpc_prometheus.StreamServerInterceptor,
+		grpc_logrus.StreamServerInterceptor(logrusEntry),
+	}
+	if options.Authenticator != nil {
+		authFunc := auth.AuthFunc(options.Authenticator)
+		unary = append(unary, grpc_auth.UnaryServerInterceptor(authFunc))
+		stream = append(stream, grpc_auth.StreamServerInterceptor(authFunc))
+	}
+	if options.Limiter != nil {
+		unary = append(unary, options.Limiter.UnaryServerInterceptor())
+		stream = append(stream, options.Limiter.StreamServerInterceptor())
+	}
+	if options.Tenants != nil {
+		unary = append(unary, options.Tenants.UnaryServerInterceptor())
+		stream = append(stream, options.Tenants.StreamServerInterceptor())
+	}
+	opts := []grpc.ServerOption{
+		grpc_middleware.WithUnaryServerChain(unary...),
+		grpc_middleware.WithStreamServerChain(stream...),
+	}
+	if options.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(options.TLS)))
+	}
+	grpcServer := grpc.NewServer(opts...)
 
 	grpc_prometheus.Register(grpcServer)
 
@@ -0,0 +1,106 @@
+// docs renders the OpenAPI v2 document of the page without any dependency
+(function () {
+  "use strict";
+
+  var main = document.getElementById("operations");
+
+  // element creates a tag with its class and its text
+  function element(tag, className, text) {
+    var e = document.createElement(tag);
+    if (className) {
+      e.className = className;
+    }
+    if (text) {
+      e.textContent = text;
+    }
+    return e;
+  }
+
+  // definition returns the name of the definition of a $ref
+  function definition(ref) {
+    return ref.replace("#/definitions/", "");
+  }
+
+  // type describes the type of a schema
+  function type(schema) {
+    if (!schema) {
+      return "";
+    }
+    if (schema.$ref) {
+      return definition(schema.$ref);
+    }
+    if (schema.type === "array") {
+      return type(schema.items) + "[]";
+    }
+    if (schema.enum) {
+      return schema.enum.join(" | ");
+    }
+    return schema.type + (schema.format ? " (" + schema.format + ")" : "");
+  }
+
+  // properties renders the fields of a definition
+  function properties(spec, schema) {
+    var table = element("table");
+    var head = table.insertRow();
+    ["Field", "Type", "Description"].forEach(function (title) {
+      head.appendChild(element("th", "", title));
+    });
+    var d = schema && schema.$ref ? spec.definitions[definition(schema.$ref)] : schema;
+    Object.keys((d && d.properties) || {}).forEach(function (name) {
+      var property = d.properties[name];
+      var row = table.insertRow();
+      row.appendChild(element("td")).appendChild(element("code", "", name));
+      row.appendChild(element("td", "", type(property)));
+      row.appendChild(element("td", "", property.title || property.description || ""));
+    });
+    return table;
+  }
+
+  // operation renders a method of a path
+  function operation(spec, path, method, op) {
+    var details = element("details");
+    var summary = details.appendChild(element("summary"));
+    summary.appendChild(element("span", "method " + method, method));
+    summary.appendChild(element("span", "path", path));
+    summary.appendChild(document.createTextNode(" " + (op.summary || "")));
+
+    var body = details.appendChild(element("div", "operation"));
+    body.appendChild(element("p", "", op.description || ""));
+    var parameters = (op.parameters || []).filter(function (p) { return p.in !== "body"; });
+    if (parameters.length > 0) {
+      body.appendChild(element("h4", "", "Parameters"));
+      var table = body.appendChild(element("table"));
+      parameters.forEach(function (p) {
+        var row = table.insertRow();
+        row.appendChild(element("td")).appendChild(element("code", "", p.name));
+        row.appendChild(element("td", "", p.in + (p.required ? ", required" : "")));
+        row.appendChild(element("td", "", type(p.items ? {type: "array", items: p.items} : p)));
+        row.appendChild(element("td", "", p.description || ""));
+      });
+    }
+    (op.parameters || []).filter(function (p) { return p.in === "body"; }).forEach(function (p) {
+      body.appendChild(element("h4", "", "Body: " + type(p.schema)));
+      body.appendChild(properties(spec, p.schema));
+    });
+    var ok = (op.responses || {})["200"];
+    if (ok && ok.schema) {
+      body.appendChild(element("h4", "", "Response: " + type(ok.schema)));
+      body.appendChild(properties(spec, ok.schema));
+    }
+    return details;
+  }
+
+  fetch(main.getAttribute("data-spec")).then(function (response) {
+    return response.json();
+  }).then(function (spec) {
+    document.getElementById("title").textContent = spec.info.title + " " + (spec.info.version || "");
+    document.getElementById("description").textContent = spec.info.description || "";
+    Object.keys(spec.paths).sort().forEach(function (path) {
+      Object.keys(spec.paths[path]).forEach(function (method) {
+        main.appendChild(operation(spec, path, method, spec.paths[path][method]));
+      });
+    });
+  }).catch(function (err) {
+    main.textContent = "The OpenAPI document can't be loaded: " + err;
+  });
+})();
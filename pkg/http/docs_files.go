@@ -0,0 +1,10 @@
+// Code generated by gen_openapi.go. DO NOT EDIT.
+
+package http
+
+// docsFiles the files of the documentation page of pkg/http/docs by name
+var docsFiles = map[string]string{
+	"docs.css":   "body {\n  margin: 0 auto;\n  max-width: 960px;\n  padding: 0 1em 2em;\n  font-family: sans-serif;\n  color: #222;\n}\n\nh1 {\n  margin-bottom: 0.2em;\n}\n\ndetails {\n  margin: 0.5em 0;\n  border: 1px solid #ccc;\n  border-radius: 4px;\n}\n\nsummary {\n  padding: 0.5em;\n  cursor: pointer;\n}\n\n.operation {\n  padding: 0 1em 1em;\n}\n\n.method {\n  display: inline-block;\n  width: 5em;\n  font-weight: bold;\n  text-transform: uppercase;\n}\n\n.get { color: #1a6fb5; }\n.post { color: #2e8540; }\n.put, .patch { color: #b36b00; }\n.delete { color: #b3261e; }\n\n.path {\n  font-family: monospace;\n}\n\ntable {\n  border-collapse: collapse;\n  width: 100%;\n}\n\nth, td {\n  border-bottom: 1px solid #eee;\n  padding: 0.3em;\n  text-align: left;\n  vertical-align: top;\n}\n\ncode {\n  font-family: monospace;\n}\n",
+	"docs.js":    "// docs renders the OpenAPI v2 document of the page without any dependency\n(function () {\n  \"use strict\";\n\n  var main = document.getElementById(\"operations\");\n\n  // element creates a tag with its class and its text\n  function element(tag, className, text) {\n    var e = document.createElement(tag);\n    if (className) {\n      e.className = className;\n    }\n    if (text) {\n      e.textContent = text;\n    }\n    return e;\n  }\n\n  // definition returns the name of the definition of a $ref\n  function definition(ref) {\n    return ref.replace(\"#/definitions/\", \"\");\n  }\n\n  // type describes the type of a schema\n  function type(schema) {\n    if (!schema) {\n      return \"\";\n    }\n    if (schema.$ref) {\n      return definition(schema.$ref);\n    }\n    if (schema.type === \"array\") {\n      return type(schema.items) + \"[]\";\n    }\n    if (schema.enum) {\n      return schema.enum.join(\" | \");\n    }\n    return schema.type + (schema.format ? \" (\" + schema.format + \")\" : \"\");\n  }\n\n  // properties renders the fields of a definition\n  function properties(spec, schema) {\n    var table = element(\"table\");\n    var head = table.insertRow();\n    [\"Field\", \"Type\", \"Description\"].forEach(function (title) {\n      head.appendChild(element(\"th\", \"\", title));\n    });\n    var d = schema && schema.$ref ? spec.definitions[definition(schema.$ref)] : schema;\n    Object.keys((d && d.properties) || {}).forEach(function (name) {\n      var property = d.properties[name];\n      var row = table.insertRow();\n      row.appendChild(element(\"td\")).appendChild(element(\"code\", \"\", name));\n      row.appendChild(element(\"td\", \"\", type(property)));\n      row.appendChild(element(\"td\", \"\", property.title || property.description || \"\"));\n    });\n    return table;\n  }\n\n  // operation renders a method of a path\n  function operation(spec, path, method, op) {\n    var details = element(\"details\");\n    var summary = details.appendChild(element(\"summary\"));\n    summary.appendChild(element(\"span\", \"method \" + method, method));\n    summary.appendChild(element(\"span\", \"path\", path));\n    summary.appendChild(document.createTextNode(\" \" + (op.summary || \"\")));\n\n    var body = details.appendChild(element(\"div\", \"operation\"));\n    body.appendChild(element(\"p\", \"\", op.description || \"\"));\n    var parameters = (op.parameters || []).filter(function (p) { return p.in !== \"body\"; });\n    if (parameters.length > 0) {\n      body.appendChild(element(\"h4\", \"\", \"Parameters\"));\n      var table = body.appendChild(element(\"table\"));\n      parameters.forEach(function (p) {\n        var row = table.insertRow();\n        row.appendChild(element(\"td\")).appendChild(element(\"code\", \"\", p.name));\n        row.appendChild(element(\"td\", \"\", p.in + (p.required ? \", required\" : \"\")));\n        row.appendChild(element(\"td\", \"\", type(p.items ? {type: \"array\", items: p.items} : p)));\n        row.appendChild(element(\"td\", \"\", p.description || \"\"));\n      });\n    }\n    (op.parameters || []).filter(function (p) { return p.in === \"body\"; }).forEach(function (p) {\n      body.appendChild(element(\"h4\", \"\", \"Body: \" + type(p.schema)));\n      body.appendChild(properties(spec, p.schema));\n    });\n    var ok = (op.responses || {})[\"200\"];\n    if (ok && ok.schema) {\n      body.appendChild(element(\"h4\", \"\", \"Response: \" + type(ok.schema)));\n      body.appendChild(properties(spec, ok.schema));\n    }\n    return details;\n  }\n\n  fetch(main.getAttribute(\"data-spec\")).then(function (response) {\n    return response.json();\n  }).then(function (spec) {\n    document.getElementById(\"title\").textContent = spec.info.title + \" \" + (spec.info.version || \"\");\n    document.getElementById(\"description\").textContent = spec.info.description || \"\";\n    Object.keys(spec.paths).sort().forEach(function (path) {\n      Object.keys(spec.paths[path]).forEach(function (method) {\n        main.appendChild(operation(spec, path, method, spec.paths[path][method]));\n      });\n    });\n  }).catch(function (err) {\n    main.textContent = \"The OpenAPI document can't be loaded: \" + err;\n  });\n})();\n",
+	"index.html": "<!DOCTYPE html>\n<html>\n<head>\n  <meta charset=\"utf-8\">\n  <title>ToDo API</title>\n  <link rel=\"stylesheet\" href=\"/docs/docs.css\">\n</head>\n<body>\n  <header>\n    <h1 id=\"title\">ToDo API</h1>\n    <p id=\"description\"></p>\n  </header>\n  <main id=\"operations\" data-spec=\"/openapi.json\"></main>\n  <script src=\"/docs/docs.js\"></script>\n</body>\n</html>\n",
+}
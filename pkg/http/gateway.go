@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/sjeandeaux/todo/pkg/ratelimit"
+	"github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// newGateway returns the REST/JSON handler of the ToDoService served on the grpc endpoint,
+// the routes come from the google.api.http options of todo.proto.
+// It dials in TLS with grpcTLS if it is not nil.
+func newGateway(ctx context.Context, grpcEndpoint string, grpcTLS *tls.Config) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{EmitDefaults: true}),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		// the rate limiter trusts the addresses forwarded by the gateway of the process
+		runtime.WithMetadata(func(context.Context, *http.Request) metadata.MD { return ratelimit.GatewayMetadata() }),
+	)
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if grpcTLS != nil {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(grpcTLS))}
+	}
+	if err := pb.RegisterToDoServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
+
+// headerMatcher forwards the headers X-Tenant and X-Request-Id as is, the tenant interceptor and the service read them in the metadata
+func headerMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, tenant.Header) {
+		return tenant.Header, true
+	}
+	if strings.EqualFold(key, service.RequestIDHeader) {
+		return service.RequestIDHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher returns the ID of the request in the header X-Request-Id, the other metadata are prefixed
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, service.RequestIDHeader) {
+		return "X-Request-Id", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
@@ -0,0 +1,61 @@
+// +build ignore
+
+// gen_openapi embeds the OpenAPI document generated from todo.proto in openapi_json.go
+// and the files of the documentation page of docs in docs_files.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+)
+
+func main() {
+	spec, err := ioutil.ReadFile("../../todo-grpc/todo.swagger.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	code := fmt.Sprintf(`// Code generated by gen_openapi.go. DO NOT EDIT.
+
+package http
+
+// openAPI the OpenAPI v2 document of todo-grpc/todo.swagger.json
+const openAPI = %q
+`, spec)
+	if err := ioutil.WriteFile("openapi_json.go", []byte(code), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	paths, err := filepath.Glob("docs/*")
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(paths)
+	var files bytes.Buffer
+	files.WriteString(`// Code generated by gen_openapi.go. DO NOT EDIT.
+
+package http
+
+// docsFiles the files of the documentation page of pkg/http/docs by name
+var docsFiles = map[string]string{
+`)
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(&files, "\t%q: %q,\n", filepath.Base(path), content)
+	}
+	files.WriteString("}\n")
+	source, err := format.Source(files.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("docs_files.go", source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
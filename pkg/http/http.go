@@ -2,16 +2,20 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"log"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sjeandeaux/todo/pkg/auth"
 )
 
-// RunServer runs the http server with
-func RunServer(ctx context.Context, host, httpPort string) (int, error) {
+// RunServer runs the http server with the metrics, the REST API of the grpc server on grpcEndpoint and its documentation,
+// grpcTLS is the TLS configuration to dial grpcEndpoint, nil in plaintext.
+// With an authenticator the metrics need a bearer token, the REST API forwards the header Authorization to the grpc server which checks it.
+func RunServer(ctx context.Context, host, httpPort string, grpcEndpoint string, grpcTLS *tls.Config, authenticator auth.Authenticator) (int, error) {
 
 	lis, err := net.Listen("tcp", net.JoinHostPort(host, httpPort))
 	if err != nil {
@@ -19,15 +23,30 @@ func RunServer(ctx context.Context, host, httpPort string) (int, error) {
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
+	gateway, err := newGateway(ctx, grpcEndpoint, grpcTLS)
+	if err != nil {
+		cancel()
+		lis.Close()
+		return -1, err
+	}
+
 	go func(list net.Listener, ctx context.Context, cancel context.CancelFunc) {
 
 		mux := http.NewServeMux()
-		mux.Handle("/metrics", promhttp.Handler())
+		metrics := promhttp.Handler()
+		if authenticator != nil {
+			metrics = auth.Handler(authenticator, metrics)
+		}
+		mux.Handle("/metrics", metrics)
+		mux.Handle("/v1/", gateway)
+		mux.HandleFunc("/openapi.json", serveOpenAPI)
+		mux.HandleFunc("/docs", serveDocs)
+		mux.HandleFunc("/docs/", serveDocs)
 
+		// no write timeout, the streams of the REST API stay opened
 		s := &http.Server{
-			ReadTimeout:  1 * time.Second,
-			WriteTimeout: 10 * time.Second,
-			Handler:      mux,
+			ReadTimeout: 1 * time.Second,
+			Handler:     mux,
 		}
 
 		if err := s.Serve(lis); err != nil {
@@ -0,0 +1,422 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sjeandeaux/todo/pkg/auth"
+	grpcserver "github.com/sjeandeaux/todo/pkg/grpc"
+	. "github.com/sjeandeaux/todo/pkg/http"
+	"github.com/sjeandeaux/todo/pkg/ratelimit"
+	"github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"google.golang.org/grpc/codes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestHTTP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HTTP Suite")
+}
+
+var _ = Describe("REST API", func() {
+
+	var (
+		cancel context.CancelFunc
+		url    string
+	)
+
+	BeforeEach(func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		grpcPort, err := grpcserver.RunServer(ctx, "localhost", "0", service.NewToDoServiceServer(store.NewMemory()), grpcserver.Options{})
+		Ω(err).NotTo(HaveOccurred())
+		httpPort, err := RunServer(ctx, "localhost", "0", net.JoinHostPort("localhost", strconv.Itoa(grpcPort)), nil, nil)
+		Ω(err).NotTo(HaveOccurred())
+		url = fmt.Sprintf("http://localhost:%d", httpPort)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	// call returns the status and the JSON body of the response
+	call := func(method string, path string, body string) (int, map[string]interface{}) {
+		request, err := http.NewRequest(method, url+path, strings.NewReader(body))
+		Ω(err).NotTo(HaveOccurred())
+		response, err := http.DefaultClient.Do(request)
+		Ω(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		content, err := ioutil.ReadAll(response.Body)
+		Ω(err).NotTo(HaveOccurred())
+		result := map[string]interface{}{}
+		Ω(json.Unmarshal(content, &result)).Should(Succeed(), string(content))
+		return response.StatusCode, result
+	}
+
+	create := func(body string) string {
+		code, response := call(http.MethodPost, "/v1/todos", body)
+		Ω(code).Should(Equal(http.StatusOK))
+		return response["id"].(string)
+	}
+
+	Context("With a todo", func() {
+		It("should create, read, update, search and delete it", func() {
+			id := create(`{"title":"Challenge - todo","description":"12factor","tags":["Job"],"reminder":"2019-11-06T13:16:20Z"}`)
+
+			code, response := call(http.MethodGet, "/v1/todos/"+id, "")
+			Ω(code).Should(Equal(http.StatusOK))
+			read := response["toDo"].(map[string]interface{})
+			createdAt := read["createdAt"]
+			Ω(createdAt).Should(BeAssignableToTypeOf(""))
+			Ω(read).Should(HaveKeyWithValue("updatedAt", createdAt))
+			Ω(read).Should(HaveKeyWithValue("completedAt", BeNil()))
+			Ω(read).Should(HaveKeyWithValue("deletedAt", BeNil()))
+			delete(read, "createdAt")
+			delete(read, "updatedAt")
+			delete(read, "completedAt")
+			delete(read, "deletedAt")
+			Ω(read).Should(Equal(map[string]interface{}{
+				"id":          id,
+				"title":       "Challenge - todo",
+				"description": "12factor",
+				"tags":        []interface{}{"job"},
+				"reminder":    "2019-11-06T13:16:20Z",
+				"state":       "NOT_STARTED",
+				"owner":       "",
+				"listId":      "",
+				"version":     "1",
+			}))
+
+			By("Update only the fields of the body")
+			code, response = call(http.MethodPatch, "/v1/todos/"+id, `{"state":"DONE"}`)
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["updated"]).Should(Equal("1"))
+			_, response = call(http.MethodGet, "/v1/todos/"+id, "")
+			Ω(response["toDo"]).Should(HaveKeyWithValue("state", "DONE"))
+			Ω(response["toDo"]).Should(HaveKeyWithValue("title", "Challenge - todo"))
+			Ω(response["toDo"]).Should(HaveKeyWithValue("version", "2"))
+			Ω(response["toDo"]).Should(HaveKeyWithValue("completedAt", response["toDo"].(map[string]interface{})["updatedAt"]))
+
+			By("Refuse the update of a todo which has changed since the expected version")
+			code, _ = call(http.MethodPatch, "/v1/todos/"+id+"?expectedVersion=1", `{"state":"IN_PROGRESS"}`)
+			Ω(code).Should(Equal(http.StatusConflict))
+
+			By("Search with the query parameters")
+			code, response = call(http.MethodGet, "/v1/todos?tags=job&states=DONE&orderBy=title", "")
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["toDos"]).Should(HaveLen(1))
+			Ω(response["totalSize"]).Should(Equal("1"))
+			_, response = call(http.MethodGet, "/v1/todos?states=NOT_STARTED", "")
+			Ω(response["toDos"]).Should(BeEmpty())
+			code, response = call(http.MethodGet, "/v1/todos?createdAt.after="+createdAt.(string)+"&orderBy=completed%20desc", "")
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["toDos"]).Should(HaveLen(1))
+			_, response = call(http.MethodGet, "/v1/todos?completedAt.before="+createdAt.(string), "")
+			Ω(response["toDos"]).Should(BeEmpty())
+
+			code, response = call(http.MethodDelete, "/v1/todos/"+id, "")
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["deleted"]).Should(Equal("1"))
+		})
+	})
+
+	Context("With the trash", func() {
+		It("should list, restore and purge the deleted todos", func() {
+			id := create(`{"title":"a"}`)
+			code, _ := call(http.MethodDelete, "/v1/todos/"+id, "")
+			Ω(code).Should(Equal(http.StatusOK))
+			code, _ = call(http.MethodGet, "/v1/todos/"+id, "")
+			Ω(code).Should(Equal(http.StatusNotFound))
+
+			code, response := call(http.MethodGet, "/v1/todos?trashed=true&orderBy=deleted%20desc", "")
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["toDos"]).Should(HaveLen(1))
+			Ω(response["toDos"].([]interface{})[0]).Should(HaveKeyWithValue("deletedAt", BeAssignableToTypeOf("")))
+
+			code, response = call(http.MethodPost, "/v1/todos/"+id+":restore", "{}")
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["restored"]).Should(Equal("1"))
+			_, response = call(http.MethodGet, "/v1/todos/"+id, "")
+			Ω(response["toDo"]).Should(HaveKeyWithValue("deletedAt", BeNil()))
+
+			call(http.MethodDelete, "/v1/todos/"+id, "")
+			code, response = call(http.MethodPost, "/v1/todos/"+id+":purge", "{}")
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["purged"]).Should(Equal("1"))
+			code, _ = call(http.MethodPost, "/v1/todos/"+id+":restore", "{}")
+			Ω(code).Should(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("With a rate limit", func() {
+		It("should throttle the callers which spoof X-Forwarded-For", func() {
+			ctx, stop := context.WithCancel(context.Background())
+			defer stop()
+			limiter := ratelimit.NewLimiter(map[string]ratelimit.Limit{ratelimit.Default: {Rate: 0.01, Burst: 1}})
+			grpcPort, err := grpcserver.RunServer(ctx, "localhost", "0", service.NewToDoServiceServer(store.NewMemory()), grpcserver.Options{Limiter: limiter})
+			Ω(err).NotTo(HaveOccurred())
+			httpPort, err := RunServer(ctx, "localhost", "0", net.JoinHostPort("localhost", strconv.Itoa(grpcPort)), nil, nil)
+			Ω(err).NotTo(HaveOccurred())
+
+			statuses := []int{}
+			for _, spoofed := range []string{"10.0.0.7", "10.0.0.8"} {
+				request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%d/v1/todos", httpPort), nil)
+				Ω(err).NotTo(HaveOccurred())
+				request.Header.Set("X-Forwarded-For", spoofed)
+				response, err := http.DefaultClient.Do(request)
+				Ω(err).NotTo(HaveOccurred())
+				response.Body.Close()
+				statuses = append(statuses, response.StatusCode)
+			}
+			Ω(statuses).Should(Equal([]int{http.StatusOK, http.StatusTooManyRequests}))
+		})
+	})
+
+	Context("With a batch", func() {
+		It("should create and delete the todos with a result by todo", func() {
+			code, response := call(http.MethodPost, "/v1/todos:batchCreate", `{"requests":[{"toDo":{"title":"a"}},{"toDo":{}}]}`)
+			Ω(code).Should(Equal(http.StatusOK))
+			results := response["results"].([]interface{})
+			Ω(results).Should(HaveLen(2))
+			id := results[0].(map[string]interface{})["id"].(string)
+			Ω(results[0]).Should(HaveKeyWithValue("status", BeNil()))
+			Ω(results[1]).Should(HaveKeyWithValue("status", HaveKeyWithValue("code", BeEquivalentTo(codes.InvalidArgument))))
+
+			code, response = call(http.MethodPost, "/v1/todos:batchDelete", `{"requests":[{"id":"`+id+`"}]}`)
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(response["results"]).Should(Equal([]interface{}{map[string]interface{}{"deleted": "1", "status": nil}}))
+			code, _ = call(http.MethodGet, "/v1/todos/"+id, "")
+			Ω(code).Should(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("With a history", func() {
+		It("should return the changes with the ID of their requests", func() {
+			request, err := http.NewRequest(http.MethodPost, url+"/v1/todos", strings.NewReader(`{"title":"a"}`))
+			Ω(err).NotTo(HaveOccurred())
+			request.Header.Set("X-Request-Id", "create")
+			response, err := http.DefaultClient.Do(request)
+			Ω(err).NotTo(HaveOccurred())
+			created := map[string]string{}
+			Ω(json.NewDecoder(response.Body).Decode(&created)).Should(Succeed())
+			response.Body.Close()
+			id := created["id"]
+
+			By("Return the generated ID of a request without header")
+			request, err = http.NewRequest(http.MethodPatch, url+"/v1/todos/"+id, strings.NewReader(`{"title":"b"}`))
+			Ω(err).NotTo(HaveOccurred())
+			response, err = http.DefaultClient.Do(request)
+			Ω(err).NotTo(HaveOccurred())
+			response.Body.Close()
+			generated := response.Header.Get("X-Request-Id")
+			Ω(generated).ShouldNot(BeEmpty())
+
+			code, body := call(http.MethodGet, "/v1/todos/"+id+"/history", "")
+			Ω(code).Should(Equal(http.StatusOK))
+			records := body["records"].([]interface{})
+			Ω(records).Should(HaveLen(2))
+			Ω(records[0]).Should(HaveKeyWithValue("type", "CREATED"))
+			Ω(records[0]).Should(HaveKeyWithValue("requestId", "create"))
+			Ω(records[1]).Should(HaveKeyWithValue("type", "UPDATED"))
+			Ω(records[1]).Should(HaveKeyWithValue("requestId", generated))
+			Ω(records[1]).Should(HaveKeyWithValue("changes", []interface{}{
+				map[string]interface{}{"field": "title", "oldValue": "a", "newValue": "b"},
+			}))
+		})
+	})
+
+	Context("With a stream", func() {
+		It("should answer a JSON object by todo", func() {
+			first := create(`{"title":"b"}`)
+			second := create(`{"title":"a"}`)
+
+			response, err := http.Get(url + "/v1/todos:stream?orderBy=title")
+			Ω(err).NotTo(HaveOccurred())
+			defer response.Body.Close()
+			decoder := json.NewDecoder(response.Body)
+			ids := []string{}
+			for decoder.More() {
+				var line struct {
+					Result map[string]interface{} `json:"result"`
+				}
+				Ω(decoder.Decode(&line)).Should(Succeed())
+				ids = append(ids, line.Result["id"].(string))
+			}
+			Ω(ids).Should(Equal([]string{second, first}))
+		})
+	})
+
+	Context("With the documentation", func() {
+		It("should answer the OpenAPI document and the page", func() {
+			code, spec := call(http.MethodGet, "/openapi.json", "")
+			Ω(code).Should(Equal(http.StatusOK))
+			Ω(spec["swagger"]).Should(Equal("2.0"))
+			Ω(spec["paths"]).Should(HaveKey("/v1/todos/{id}"))
+			Ω(spec["definitions"]).Should(HaveKey("v1ToDo"))
+
+			response, err := http.Get(url + "/docs")
+			Ω(err).NotTo(HaveOccurred())
+			defer response.Body.Close()
+			page, err := ioutil.ReadAll(response.Body)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.Header.Get("Content-Type")).Should(HavePrefix("text/html"))
+			Ω(string(page)).Should(ContainSubstring(`"/openapi.json"`))
+			Ω(string(page)).ShouldNot(ContainSubstring("https://"))
+
+			for _, file := range []string{"/docs/docs.js", "/docs/docs.css"} {
+				response, err := http.Get(url + file)
+				Ω(err).NotTo(HaveOccurred())
+				response.Body.Close()
+				Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			}
+			response, err = http.Get(url + "/docs/missing.js")
+			Ω(err).NotTo(HaveOccurred())
+			response.Body.Close()
+			Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+
+		It("should not allow the other origins", func() {
+			response, err := http.Get(url + "/openapi.json")
+			Ω(err).NotTo(HaveOccurred())
+			response.Body.Close()
+			Ω(response.Header.Get("Access-Control-Allow-Origin")).Should(BeEmpty())
+		})
+	})
+
+	Context("With a todo which doesn't exist", func() {
+		It("should answer 404", func() {
+			code, response := call(http.MethodGet, "/v1/todos/5dc2d3d4aba443c197307ea2", "")
+			Ω(code).Should(Equal(http.StatusNotFound))
+			Ω(response["code"]).Should(Equal(float64(5)))
+		})
+	})
+
+	Context("With an invalid todo", func() {
+		It("should answer 400 with the violations", func() {
+			code, response := call(http.MethodPost, "/v1/todos", `{"title":" "}`)
+			Ω(code).Should(Equal(http.StatusBadRequest))
+			Ω(response["details"]).Should(ConsistOf(HaveKeyWithValue("fieldViolations", ConsistOf(map[string]interface{}{
+				"field":       "toDo.title",
+				"description": "must not be empty",
+			}))))
+		})
+	})
+
+	Context("With an invalid ID", func() {
+		It("should answer 400", func() {
+			code, _ := call(http.MethodDelete, "/v1/todos/nope", "")
+			Ω(code).Should(Equal(http.StatusBadRequest))
+		})
+	})
+})
+
+var _ = Describe("REST API with authentication", func() {
+
+	var (
+		cancel context.CancelFunc
+		url    string
+	)
+
+	BeforeEach(func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		keys := auth.APIKeys{"alice": "secret", "bob": "secret-b"}
+		grpcPort, err := grpcserver.RunServer(ctx, "localhost", "0", service.NewToDoServiceServer(store.NewMemory()), grpcserver.Options{Authenticator: keys})
+		Ω(err).NotTo(HaveOccurred())
+		httpPort, err := RunServer(ctx, "localhost", "0", net.JoinHostPort("localhost", strconv.Itoa(grpcPort)), nil, keys)
+		Ω(err).NotTo(HaveOccurred())
+		url = fmt.Sprintf("http://localhost:%d", httpPort)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	// status returns the status of the response to GET path with the token
+	status := func(path string, token string) int {
+		request, err := http.NewRequest(http.MethodGet, url+path, nil)
+		Ω(err).NotTo(HaveOccurred())
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		response, err := http.DefaultClient.Do(request)
+		Ω(err).NotTo(HaveOccurred())
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	It("should forward the token to the grpc server", func() {
+		Ω(status("/v1/todos", "secret")).Should(Equal(http.StatusOK))
+		Ω(status("/v1/todos", "")).Should(Equal(http.StatusUnauthorized))
+		Ω(status("/v1/todos", "wrong")).Should(Equal(http.StatusUnauthorized))
+	})
+
+	It("should stamp the todos with the subject of the token", func() {
+		request, err := http.NewRequest(http.MethodPost, url+"/v1/todos", strings.NewReader(`{"title":"Challenge - todo"}`))
+		Ω(err).NotTo(HaveOccurred())
+		request.Header.Set("Authorization", "Bearer secret")
+		response, err := http.DefaultClient.Do(request)
+		Ω(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		created := map[string]string{}
+		Ω(json.NewDecoder(response.Body).Decode(&created)).Should(Succeed())
+
+		request, err = http.NewRequest(http.MethodGet, url+"/v1/todos/"+created["id"], nil)
+		Ω(err).NotTo(HaveOccurred())
+		request.Header.Set("Authorization", "Bearer secret")
+		response, err = http.DefaultClient.Do(request)
+		Ω(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		read := map[string]map[string]interface{}{}
+		Ω(json.NewDecoder(response.Body).Decode(&read)).Should(Succeed())
+		Ω(read["toDo"]).Should(HaveKeyWithValue("owner", "alice"))
+	})
+
+	It("should share a list", func() {
+		// call returns the status and the JSON of the response to method path with the token
+		call := func(method string, path string, token string, body string) (int, map[string]interface{}) {
+			request, err := http.NewRequest(method, url+path, strings.NewReader(body))
+			Ω(err).NotTo(HaveOccurred())
+			request.Header.Set("Authorization", "Bearer "+token)
+			response, err := http.DefaultClient.Do(request)
+			Ω(err).NotTo(HaveOccurred())
+			defer response.Body.Close()
+			result := map[string]interface{}{}
+			Ω(json.NewDecoder(response.Body).Decode(&result)).Should(Succeed())
+			return response.StatusCode, result
+		}
+
+		code, created := call(http.MethodPost, "/v1/lists", "secret", `{"name":"Groceries"}`)
+		Ω(code).Should(Equal(http.StatusOK))
+		id := created["id"].(string)
+		Ω(status("/v1/lists/"+id, "secret-b")).Should(Equal(http.StatusNotFound))
+
+		code, shared := call(http.MethodPost, "/v1/lists/"+id+":share", "secret", `{"subject":"bob","role":"VIEWER"}`)
+		Ω(code).Should(Equal(http.StatusOK))
+		Ω(shared["list"]).Should(HaveKeyWithValue("owner", "alice"))
+		Ω(status("/v1/lists/"+id, "secret-b")).Should(Equal(http.StatusOK))
+
+		code, _ = call(http.MethodPost, "/v1/todos", "secret-b", `{"title":"milk","listId":"`+id+`"}`)
+		Ω(code).Should(Equal(http.StatusForbidden))
+	})
+
+	It("should protect the metrics", func() {
+		Ω(status("/metrics", "secret")).Should(Equal(http.StatusOK))
+		Ω(status("/metrics", "")).Should(Equal(http.StatusUnauthorized))
+	})
+
+	It("should let the documentation public", func() {
+		Ω(status("/openapi.json", "")).Should(Equal(http.StatusOK))
+		Ω(status("/docs/docs.js", "")).Should(Equal(http.StatusOK))
+	})
+})
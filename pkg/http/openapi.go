@@ -0,0 +1,38 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+//go:generate go run gen_openapi.go
+
+// serveOpenAPI answers the OpenAPI document generated from todo.proto
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPI))
+}
+
+// serveDocs answers the page of the documentation at /docs and its files embedded from pkg/http/docs at /docs/,
+// the page renders /openapi.json without loading anything from another origin
+func serveDocs(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/docs")
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		name = "index.html"
+	}
+	content, ok := docsFiles[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Security-Policy", "default-src 'self'")
+	w.Write([]byte(content))
+}
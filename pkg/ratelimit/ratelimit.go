@@ -0,0 +1,257 @@
+// Package ratelimit limits the calls of each client with a token bucket by RPC,
+// a client is the subject of its token or else its address.
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sjeandeaux/todo/pkg/auth"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Default the key of the limit of the RPCs without their own limit
+const Default = "default"
+
+// IdleTimeout the duration after which the bucket of a client which doesn't call is forgotten
+const IdleTimeout = 10 * time.Minute
+
+// rejected counts the calls refused by the limiter
+var rejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "todod_ratelimit_rejected_total",
+	Help: "The number of calls refused by the rate limiter by method.",
+}, []string{"method"})
+
+func init() {
+	prometheus.MustRegister(rejected)
+}
+
+// Limit the token bucket of a client on a RPC
+type Limit struct {
+	// Rate the calls per second
+	Rate rate.Limit
+	// Burst the calls a client can make at once
+	Burst int
+}
+
+// ParseLimits parses the limits written method=rate:burst separated by commas,
+// the method is the name of the RPC, ex: Search, or default for the other RPCs.
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		method, value := split(entry, "=")
+		r, b := split(value, ":")
+		if method == "" || r == "" || b == "" {
+			return nil, fmt.Errorf("the limit %q is not method=rate:burst", entry)
+		}
+		perSecond, err := strconv.ParseFloat(r, 64)
+		if err != nil || perSecond <= 0 {
+			return nil, fmt.Errorf("the rate of %q is not a positive number", entry)
+		}
+		burst, err := strconv.Atoi(b)
+		if err != nil || burst <= 0 {
+			return nil, fmt.Errorf("the burst of %q is not a positive integer", entry)
+		}
+		limits[method] = Limit{Rate: rate.Limit(perSecond), Burst: burst}
+	}
+	return limits, nil
+}
+
+// split returns the parts of s before and after the first sep, empty if sep is missing
+func split(s string, sep string) (string, string) {
+	i := strings.Index(s, sep)
+	if i < 0 {
+		return "", ""
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+}
+
+// bucket the token bucket of a client on a RPC
+type bucket struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// Limiter refuses the calls of the clients which exceed the limit of the RPC
+type Limiter struct {
+	limits map[string]Limit
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// NewLimiter creates the limiter of the limits by RPC name, the RPCs without limit nor default are not limited
+func NewLimiter(limits map[string]Limit) *Limiter {
+	return &Limiter{limits: limits, buckets: make(map[string]*bucket)}
+}
+
+// limit returns the limit of the method /package.Service/Name
+func (l *Limiter) limit(method string) (Limit, bool) {
+	if limit, ok := l.limits[method[strings.LastIndex(method, "/")+1:]]; ok {
+		return limit, true
+	}
+	limit, ok := l.limits[Default]
+	return limit, ok
+}
+
+// Allow takes a token of the bucket of the client on the method, else it returns how long to wait
+func (l *Limiter) Allow(client string, method string) (bool, time.Duration) {
+	limit, ok := l.limit(method)
+	if !ok {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.sweep(now)
+	key := client + " " + method
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(limit.Rate, limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.seen = now
+	reservation := b.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return true, 0
+	}
+	// the token is given back, the client will retry
+	reservation.CancelAt(now)
+	return false, delay
+}
+
+// sweep forgets the idle clients, it runs at most once by IdleTimeout
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < IdleTimeout {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.seen) >= IdleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+	l.swept = now
+}
+
+// GatewayHeader the metadata of the token of the REST gateway of the process, the addresses it forwards are trusted
+const GatewayHeader = "x-todo-gateway"
+
+// gatewayToken the token of the gateway of the process, a random value the clients can't guess
+var gatewayToken = newGatewayToken()
+
+func newGatewayToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// GatewayMetadata returns the metadata the REST gateway of the process adds to its calls
+func GatewayMetadata() metadata.MD {
+	return metadata.Pairs(GatewayHeader, gatewayToken)
+}
+
+// Client returns the client of the call: the subject of its token, else its address.
+// The address of the REST API is the last entry of X-Forwarded-For, the one the gateway of the process adds,
+// the header is ignored on the other connections.
+func Client(ctx context.Context) string {
+	if identity, ok := auth.FromContext(ctx); ok {
+		return "subject:" + identity.Subject
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "peer:unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() && fromGateway(ctx) {
+		if forwarded := lastForwarded(ctx); forwarded != "" {
+			host = forwarded
+		}
+	}
+	return "peer:" + host
+}
+
+// fromGateway tells if the call comes from the gateway of the process
+func fromGateway(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(GatewayHeader)
+	return len(tokens) == 1 && subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(gatewayToken)) == 1
+}
+
+// lastForwarded returns the last address of X-Forwarded-For, the caller controls the others
+func lastForwarded(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-forwarded-for")
+	if len(values) == 0 {
+		return ""
+	}
+	entries := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(entries[len(entries)-1])
+}
+
+// exempt the health checks are never limited
+func exempt(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+// check returns ResourceExhausted with the delay before a retry if the client exceeds the limit of the method
+func (l *Limiter) check(ctx context.Context, method string) error {
+	if exempt(method) {
+		return nil
+	}
+	ok, delay := l.Allow(Client(ctx), method)
+	if ok {
+		return nil
+	}
+	rejected.WithLabelValues(method).Inc()
+	s, err := status.New(codes.ResourceExhausted, fmt.Sprintf("too many calls, retry in %s", delay)).
+		WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(delay)})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "too many calls")
+	}
+	return s.Err()
+}
+
+// UnaryServerInterceptor returns the interceptor which limits the unary calls, it must follow the authentication
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns the interceptor which limits the opening of the streams, it must follow the authentication
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.check(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
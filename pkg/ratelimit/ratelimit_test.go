@@ -0,0 +1,153 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sjeandeaux/todo/pkg/auth"
+	. "github.com/sjeandeaux/todo/pkg/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRateLimit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RateLimit Suite")
+}
+
+const search = "/todo.v1.ToDoService/Search"
+
+// from returns a context of a call from the address
+func from(address string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(address), Port: 4242}})
+}
+
+var _ = Describe("RateLimit", func() {
+
+	Describe("ParseLimits", func() {
+		It("should parse the limits by method", func() {
+			limits, err := ParseLimits("default=10:20, Search=0.5:2")
+			Ω(err).NotTo(HaveOccurred())
+			Ω(limits).Should(Equal(map[string]Limit{
+				Default:  {Rate: 10, Burst: 20},
+				"Search": {Rate: 0.5, Burst: 2},
+			}))
+		})
+
+		It("should refuse the bad limits", func() {
+			for _, s := range []string{"Search", "Search=1", "Search=a:1", "Search=1:0", "Search=-1:1"} {
+				_, err := ParseLimits(s)
+				Ω(err).Should(HaveOccurred(), s)
+			}
+		})
+	})
+
+	Describe("Allow", func() {
+		It("should refuse the calls over the burst with the delay before the next token", func() {
+			limiter := NewLimiter(map[string]Limit{"Search": {Rate: 1, Burst: 2}})
+			Ω(limiter.Allow("alice", search)).Should(BeTrue())
+			Ω(limiter.Allow("alice", search)).Should(BeTrue())
+			ok, delay := limiter.Allow("alice", search)
+			Ω(ok).Should(BeFalse())
+			Ω(delay).Should(BeNumerically("~", time.Second, 100*time.Millisecond))
+
+			ok, _ = limiter.Allow("bob", search)
+			Ω(ok).Should(BeTrue(), "each client has its bucket")
+			ok, _ = limiter.Allow("alice", "/todo.v1.ToDoService/Read")
+			Ω(ok).Should(BeTrue(), "the RPCs without limit are not limited")
+		})
+
+		It("should use the default limit", func() {
+			limiter := NewLimiter(map[string]Limit{Default: {Rate: 1, Burst: 1}})
+			Ω(limiter.Allow("alice", "/todo.v1.ToDoService/Read")).Should(BeTrue())
+			ok, _ := limiter.Allow("alice", "/todo.v1.ToDoService/Read")
+			Ω(ok).Should(BeFalse())
+		})
+	})
+
+	Describe("Client", func() {
+		It("should be the subject of the token", func() {
+			ctx := auth.NewContext(from("10.0.0.1"), &auth.Identity{Subject: "alice"})
+			Ω(Client(ctx)).Should(Equal("subject:alice"))
+		})
+
+		It("should be the address of the peer", func() {
+			Ω(Client(from("10.0.0.1"))).Should(Equal("peer:10.0.0.1"))
+		})
+
+		It("should be the address added by the gateway to X-Forwarded-For", func() {
+			gateway := func(peer string, forwarded string) context.Context {
+				return metadata.NewIncomingContext(from(peer), metadata.Join(GatewayMetadata(), metadata.Pairs("x-forwarded-for", forwarded)))
+			}
+			Ω(Client(gateway("127.0.0.1", "10.0.0.2, 10.0.0.3"))).Should(Equal("peer:10.0.0.3"))
+			Ω(Client(gateway("10.0.0.1", "10.0.0.2"))).Should(Equal("peer:10.0.0.1"), "only the loopback is trusted")
+
+			ctx := metadata.NewIncomingContext(from("127.0.0.1"), metadata.Pairs("x-forwarded-for", "10.0.0.2"))
+			Ω(Client(ctx)).Should(Equal("peer:127.0.0.1"), "only the gateway of the process is trusted")
+			ctx = metadata.NewIncomingContext(from("127.0.0.1"), metadata.Pairs("x-forwarded-for", "10.0.0.2", GatewayHeader, "guessed"))
+			Ω(Client(ctx)).Should(Equal("peer:127.0.0.1"), "only the gateway of the process is trusted")
+		})
+
+		It("should throttle the clients which spoof X-Forwarded-For", func() {
+			limiter := NewLimiter(map[string]Limit{Default: {Rate: 1, Burst: 1}})
+			for i, spoofed := range []string{"10.0.0.7", "10.0.0.8"} {
+				md := metadata.Join(GatewayMetadata(), metadata.Pairs("x-forwarded-for", spoofed+", 10.0.0.3"))
+				ok, _ := limiter.Allow(Client(metadata.NewIncomingContext(from("127.0.0.1"), md)), "/v1.ToDoService/Search")
+				Ω(ok).Should(Equal(i == 0))
+			}
+		})
+	})
+
+	Describe("Interceptors", func() {
+		var limiter *Limiter
+
+		BeforeEach(func() {
+			limiter = NewLimiter(map[string]Limit{Default: {Rate: 1, Burst: 1}})
+		})
+
+		It("should refuse the unary calls with ResourceExhausted and the retry info", func() {
+			interceptor := limiter.UnaryServerInterceptor()
+			info := &grpc.UnaryServerInfo{FullMethod: search}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+			resp, err := interceptor(from("10.0.0.1"), nil, info, handler)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(resp).Should(Equal("ok"))
+
+			_, err = interceptor(from("10.0.0.1"), nil, info, handler)
+			Ω(status.Code(err)).Should(Equal(codes.ResourceExhausted))
+			retry := status.Convert(err).Details()[0].(*errdetails.RetryInfo)
+			delay, err := ptypes.Duration(retry.GetRetryDelay())
+			Ω(err).NotTo(HaveOccurred())
+			Ω(delay).Should(BeNumerically(">", 0))
+		})
+
+		It("should refuse the streams", func() {
+			interceptor := limiter.StreamServerInterceptor()
+			info := &grpc.StreamServerInfo{FullMethod: "/todo.v1.ToDoService/Watch"}
+			stream := &mockServerStream{ctx: from("10.0.0.1")}
+			handler := func(srv interface{}, stream grpc.ServerStream) error { return nil }
+			Ω(interceptor(nil, stream, info, handler)).Should(Succeed())
+			Ω(status.Code(interceptor(nil, stream, info, handler))).Should(Equal(codes.ResourceExhausted))
+		})
+
+		It("should not limit the health checks", func() {
+			interceptor := limiter.UnaryServerInterceptor()
+			info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+			for i := 0; i < 3; i++ {
+				_, err := interceptor(from("10.0.0.1"), nil, info, handler)
+				Ω(err).NotTo(HaveOccurred())
+			}
+		})
+	})
+})
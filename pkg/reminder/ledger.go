@@ -0,0 +1,74 @@
+package reminder
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Ledger remembers the delivered reminders in a file, one line "<id> <reminder>" by reminder,
+// so a reminder is delivered once even if the daemon restarts.
+type Ledger struct {
+	mu        sync.Mutex
+	file      *os.File
+	delivered map[key]bool
+}
+
+type key struct {
+	id       string
+	reminder int64
+}
+
+// OpenLedger loads or creates the file path
+func OpenLedger(path string) (*Ledger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l := &Ledger{file: file, delivered: make(map[key]bool)}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var k key
+		if _, err := fmt.Sscanf(scanner.Text(), "%s %d", &k.id, &k.reminder); err != nil {
+			// a line cut by a crash, the reminder is delivered again
+			continue
+		}
+		l.delivered[k] = true
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Delivered tells if the reminder of the todo id has been delivered
+func (l *Ledger) Delivered(id string, reminder int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.delivered[key{id: id, reminder: reminder}]
+}
+
+// Mark records the reminder of the todo id as delivered
+func (l *Ledger) Mark(id string, reminder int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	k := key{id: id, reminder: reminder}
+	if l.delivered[k] {
+		return nil
+	}
+	if _, err := fmt.Fprintf(l.file, "%s %d\n", id, reminder); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.delivered[k] = true
+	return nil
+}
+
+// Close closes the file
+func (l *Ledger) Close() error {
+	return l.file.Close()
+}
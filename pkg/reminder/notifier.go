@@ -0,0 +1,183 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	log "github.com/sirupsen/logrus"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// Notifier delivers the reminder of a todo
+type Notifier interface {
+	Notify(ctx context.Context, todo *pb.ToDo) error
+}
+
+// Log writes the reminders in the logs
+type Log struct{}
+
+// Notify logs the todo
+func (Log) Notify(ctx context.Context, todo *pb.ToDo) error {
+	log.WithField("id", todo.GetId()).WithField("title", todo.GetTitle()).Info("reminder")
+	return nil
+}
+
+// Webhook posts the todo in JSON to URL
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify posts the todo, a status which is not 2xx is an error
+func (w *Webhook) Notify(ctx context.Context, todo *pb.ToDo) error {
+	body, err := (&jsonpb.Marshaler{}).MarshalToString(todo)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, w.URL, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: %s", w.URL, response.Status)
+	}
+	return nil
+}
+
+// SMTP sends a mail by reminder
+type SMTP struct {
+	// Addr host:port of the server
+	Addr string
+	// Auth nil without authentication
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+// Notify sends the mail, the title is the subject and the description the body.
+// The connection is closed when ctx is done.
+func (s *SMTP) Notify(ctx context.Context, todo *pb.ToDo) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&msg, "Subject: Reminder: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(todo.GetTitle()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\nState: %s\r\nTags: %s\r\nID: %s\r\n",
+		todo.GetDescription(), todo.GetState(), strings.Join(todo.GetTags(), ", "), todo.GetId())
+	return s.send(ctx, msg.Bytes())
+}
+
+// send is smtp.SendMail on a connection bound to ctx
+func (s *SMTP) send(ctx context.Context, msg []byte) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	if err := s.talk(conn, host, msg); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("smtp %s: %w: %v", s.Addr, ctx.Err(), err)
+		}
+		return err
+	}
+	return nil
+}
+
+// talk sends the mail on conn like smtp.SendMail
+func (s *SMTP) talk(conn net.Conn, host string, msg []byte) error {
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp %s: server doesn't support AUTH", s.Addr)
+		}
+		if err := c.Auth(s.Auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Exec runs a command by reminder, the todo in JSON is on the standard input
+// and its ID and title are in the variables TODO_ID and TODO_TITLE.
+type Exec struct {
+	Path string
+	Args []string
+}
+
+// Notify runs the command
+func (e *Exec) Notify(ctx context.Context, todo *pb.ToDo) error {
+	body, err := (&jsonpb.Marshaler{}).MarshalToString(todo)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, e.Path, e.Args...)
+	cmd.Stdin = strings.NewReader(body)
+	cmd.Env = append(os.Environ(), "TODO_ID="+todo.GetId(), "TODO_TITLE="+todo.GetTitle())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("exec %s: %w: %s", e.Path, err, output)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package reminder_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/sjeandeaux/todo/pkg/reminder"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Notifier", func() {
+
+	todo := &pb.ToDo{Id: "5dc2d3d4aba443c197307ea2", Title: "Challenge - todo"}
+
+	Describe("Webhook", func() {
+		Context("With a server which accepts", func() {
+			It("should post the todo in JSON", func() {
+				var body string
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					content, _ := ioutil.ReadAll(r.Body)
+					body = string(content)
+					Ω(r.Header.Get("Content-Type")).Should(Equal("application/json"))
+				}))
+				defer server.Close()
+
+				Ω((&Webhook{URL: server.URL}).Notify(context.TODO(), todo)).Should(Succeed())
+				Ω(body).Should(MatchJSON(`{"id":"5dc2d3d4aba443c197307ea2","title":"Challenge - todo"}`))
+			})
+		})
+
+		Context("With a server which fails", func() {
+			It("should fail", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadGateway)
+				}))
+				defer server.Close()
+
+				Ω((&Webhook{URL: server.URL}).Notify(context.TODO(), todo)).ShouldNot(Succeed())
+			})
+		})
+	})
+
+	Describe("SMTP", func() {
+		Context("With a server which never answers", func() {
+			It("should give up when the context is done", func() {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				Ω(err).NotTo(HaveOccurred())
+				defer listener.Close()
+				go func() {
+					for {
+						conn, err := listener.Accept()
+						if err != nil {
+							return
+						}
+						defer conn.Close()
+					}
+				}()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+				err = (&SMTP{Addr: listener.Addr().String(), From: "todo@example.com", To: []string{"alice@example.com"}}).Notify(ctx, todo)
+				Ω(errors.Is(err, context.DeadlineExceeded)).Should(BeTrue())
+			})
+		})
+	})
+
+	Describe("Exec", func() {
+		Context("With a command", func() {
+			It("should give it the todo", func() {
+				dir, err := ioutil.TempDir("", "exec")
+				Ω(err).NotTo(HaveOccurred())
+				defer os.RemoveAll(dir)
+				out := filepath.Join(dir, "out")
+
+				Ω((&Exec{Path: "sh", Args: []string{"-c", `echo "$TODO_ID" > ` + out + ` && cat >> ` + out}}).Notify(context.TODO(), todo)).Should(Succeed())
+				content, err := ioutil.ReadFile(out)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(string(content)).Should(Equal("5dc2d3d4aba443c197307ea2\n" + `{"id":"5dc2d3d4aba443c197307ea2","title":"Challenge - todo"}`))
+			})
+		})
+
+		Context("With a command which fails", func() {
+			It("should fail", func() {
+				Ω((&Exec{Path: "false"}).Notify(context.TODO(), todo)).ShouldNot(Succeed())
+			})
+		})
+	})
+})
@@ -0,0 +1,61 @@
+package reminder_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestReminder(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Reminder Suite")
+}
+
+// recorder keeps the IDs of the notified todos
+type recorder chan string
+
+func (r recorder) Notify(ctx context.Context, todo *pb.ToDo) error {
+	r <- todo.GetId()
+	return nil
+}
+
+// flaky fails the first calls then records the todos like recorder
+type flaky struct {
+	mu    sync.Mutex
+	fails int
+	recorder
+}
+
+func (f *flaky) Notify(ctx context.Context, todo *pb.ToDo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fails > 0 {
+		f.fails--
+		return errors.New("unavailable")
+	}
+	return f.recorder.Notify(ctx, todo)
+}
+
+// slow blocks the reminder of the todo id until release is closed then records the todos like recorder
+type slow struct {
+	id      string
+	release chan struct{}
+	recorder
+}
+
+func (s *slow) Notify(ctx context.Context, todo *pb.ToDo) error {
+	if todo.GetId() == s.id {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return s.recorder.Notify(ctx, todo)
+}
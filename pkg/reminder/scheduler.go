@@ -0,0 +1,291 @@
+// Package reminder delivers the reminders of the todos when they come due.
+package reminder
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/event"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// NotifyTimeout the maximum duration of a notifier
+const NotifyTimeout = 30 * time.Second
+
+// The delays before the retries of a reminder which no notifier delivered, it doubles after each attempt
+const (
+	DefaultRetryBackoff = time.Minute
+	MaxRetryBackoff     = time.Hour
+)
+
+// Scheduler tracks the reminders of the todos which are not done and gives them to the notifiers when they come due.
+//
+// The todos are loaded from the store on start then the changes come from the events,
+// a reminder in the past which has not been delivered is delivered on start.
+// The notifiers run out of the loop of the scheduler, a reminder is delivered when one of them succeeds,
+// else it is retried later.
+type Scheduler struct {
+	// Tenants the tenants of the store, single tenant if empty
+	Tenants []string
+	// RetryBackoff the delay before the first retry of a reminder which is not delivered
+	RetryBackoff time.Duration
+
+	store     store.Store
+	events    *event.Bus
+	ledger    *Ledger
+	notifiers []Notifier
+
+	// scheduled the reminder of each todo, the queue can have outdated entries
+	scheduled map[todoKey]int64
+	queue     queue
+	// sending the reminders given to the notifiers
+	sending map[todoKey]int64
+	sent    chan sent
+}
+
+// sent the outcome of the notifiers of a reminder
+type sent struct {
+	entry
+	delivered bool
+	// retry tells if the reminder which is not delivered must be retried, not if its todo is deleted
+	retry bool
+}
+
+// NewScheduler creates a scheduler
+func NewScheduler(s store.Store, events *event.Bus, ledger *Ledger, notifiers ...Notifier) *Scheduler {
+	return &Scheduler{
+		RetryBackoff: DefaultRetryBackoff,
+		store:        s,
+		events:       events,
+		ledger:       ledger,
+		notifiers:    notifiers,
+	}
+}
+
+// Run schedules the reminders until ctx is done or the bus is closed
+func (s *Scheduler) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.sending = make(map[todoKey]int64)
+	s.sent = make(chan sent)
+	for {
+		// subscribe before loading so no change is lost
+		subscription := s.events.Subscribe()
+		err := s.load(ctx)
+		if err == nil {
+			err = s.loop(ctx, subscription)
+		}
+		subscription.Close()
+		if errors.Is(err, event.ErrOverflow) {
+			log.Warn("the reminders are reloaded after too many changes")
+			continue
+		}
+		if errors.Is(err, event.ErrClosed) || errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+}
+
+// load schedules the reminders of all the todos of all the tenants
+func (s *Scheduler) load(ctx context.Context) error {
+	s.scheduled = make(map[todoKey]int64)
+	s.queue = queue{}
+	tenants := s.Tenants
+	if len(tenants) == 0 {
+		tenants = []string{""}
+	}
+	for _, t := range tenants {
+		err := s.store.Iterate(tenant.NewContext(ctx, t), store.Query{Request: &pb.SearchRequest{}}, func(todo *pb.ToDo) error {
+			s.schedule(t, todo)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schedule tracks the reminder of the todo, it replaces the previous one
+func (s *Scheduler) schedule(t string, todo *pb.ToDo) {
+	k := todoKey{tenant: t, id: todo.GetId()}
+	delete(s.scheduled, k)
+	reminder := todo.GetReminder().GetSeconds()
+	if reminder <= 0 || todo.GetState() == pb.ToDo_DONE || s.ledger.Delivered(k.String(), reminder) {
+		return
+	}
+	s.scheduled[k] = reminder
+	heap.Push(&s.queue, entry{todoKey: k, reminder: reminder, due: time.Unix(reminder, 0)})
+}
+
+func (s *Scheduler) loop(ctx context.Context, subscription *event.Subscription) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		s.deliver(ctx)
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		var due <-chan time.Time
+		if len(s.queue) > 0 {
+			timer.Reset(time.Until(s.queue[0].due))
+			due = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-due:
+		case r := <-s.sent:
+			s.done(r)
+		case e, ok := <-subscription.Events():
+			if !ok {
+				return subscription.Err()
+			}
+			if e.GetType() == pb.Event_DELETED || e.GetType() == pb.Event_PURGED {
+				delete(s.scheduled, todoKey{tenant: e.GetTenant(), id: e.GetToDo().GetId()})
+				continue
+			}
+			s.schedule(e.GetTenant(), e.GetToDo())
+		}
+	}
+}
+
+// deliver gives the reminders which are due to the notifiers
+func (s *Scheduler) deliver(ctx context.Context) {
+	now := time.Now()
+	for len(s.queue) > 0 && !s.queue[0].due.After(now) {
+		e := heap.Pop(&s.queue).(entry)
+		if s.scheduled[e.todoKey] != e.reminder || s.sending[e.todoKey] == e.reminder {
+			// the reminder has changed, the todo is deleted or the reminder is being sent
+			continue
+		}
+		s.sending[e.todoKey] = e.reminder
+		go func() {
+			r := sent{entry: e}
+			r.delivered, r.retry = s.notify(ctx, e)
+			select {
+			case s.sent <- r:
+			case <-ctx.Done():
+			}
+		}()
+	}
+}
+
+// done records the delivered reminder in the ledger, else retries it if it is still scheduled
+func (s *Scheduler) done(r sent) {
+	if s.sending[r.todoKey] == r.reminder {
+		delete(s.sending, r.todoKey)
+	}
+	if s.scheduled[r.todoKey] != r.reminder {
+		// the reminder has changed or the todo is deleted since
+		if r.delivered {
+			s.mark(r.entry)
+		}
+		return
+	}
+	if r.delivered || !r.retry {
+		delete(s.scheduled, r.todoKey)
+		if r.delivered {
+			s.mark(r.entry)
+		}
+		return
+	}
+	backoff := s.RetryBackoff << uint(r.attempt)
+	if backoff <= 0 || backoff > MaxRetryBackoff {
+		backoff = MaxRetryBackoff
+	}
+	r.attempt++
+	r.due = time.Now().Add(backoff)
+	heap.Push(&s.queue, r.entry)
+}
+
+// mark records the reminder in the ledger so it is not delivered again after a restart
+func (s *Scheduler) mark(e entry) {
+	if err := s.ledger.Mark(e.todoKey.String(), e.reminder); err != nil {
+		log.WithError(err).WithField("id", e.id).WithField("reminder", e.reminder).Error("the reminder can be delivered again")
+	}
+}
+
+// notify gives the reminder to the notifiers, it tells if one of them delivered it and else if it must be retried
+func (s *Scheduler) notify(ctx context.Context, e entry) (bool, bool) {
+	logger := log.WithField("id", e.id).WithField("reminder", e.reminder)
+	if e.tenant != "" {
+		logger = logger.WithField("tenant", e.tenant)
+	}
+	ctx = tenant.NewContext(ctx, e.tenant)
+	todo, err := s.store.Read(ctx, e.id)
+	if errors.Is(err, store.ErrNotFound) {
+		logger.Warn("the todo of the reminder is deleted")
+		return false, false
+	}
+	if err != nil {
+		logger.WithError(err).Warn("the todo of the reminder can't be read")
+		return false, true
+	}
+
+	delivered := false
+	for _, notifier := range s.notifiers {
+		ctx, cancel := context.WithTimeout(ctx, NotifyTimeout)
+		if err := notifier.Notify(ctx, todo); err != nil {
+			logger.WithError(err).Error("the reminder is not delivered")
+		} else {
+			delivered = true
+		}
+		cancel()
+	}
+	if !delivered {
+		logger.WithField("attempt", e.attempt+1).Warn("the reminder is retried later")
+		return false, true
+	}
+	logger.Info("the reminder is delivered")
+	return true, false
+}
+
+// todoKey a todo of a tenant
+type todoKey struct {
+	tenant string
+	id     string
+}
+
+// String returns the id of the todo in the ledger, the id alone without tenant
+func (k todoKey) String() string {
+	if k.tenant == "" {
+		return k.id
+	}
+	return k.tenant + "/" + k.id
+}
+
+// entry a reminder in the queue
+type entry struct {
+	todoKey
+	reminder int64
+	// due the date of the reminder or of its next attempt
+	due time.Time
+	// attempt the number of attempts which failed
+	attempt int
+}
+
+// queue the reminders by date, the earliest first
+type queue []entry
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].due.Before(q[j].due) }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(entry)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
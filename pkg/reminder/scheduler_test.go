@@ -0,0 +1,211 @@
+package reminder_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/sjeandeaux/todo/pkg/event"
+	. "github.com/sjeandeaux/todo/pkg/reminder"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduler", func() {
+
+	var (
+		dir      string
+		memory   *store.Memory
+		bus      *event.Bus
+		notified recorder
+		notifier Notifier
+		stop     func()
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "reminder")
+		Ω(err).NotTo(HaveOccurred())
+		memory = store.NewMemory()
+		bus = event.NewBus(event.DefaultBuffer)
+		notified = make(recorder, 10)
+		notifier = notified
+	})
+
+	AfterEach(func() {
+		if stop != nil {
+			stop()
+		}
+		os.RemoveAll(dir)
+	})
+
+	// start runs a scheduler with a ledger in dir until stop
+	start := func() {
+		ledger, err := OpenLedger(filepath.Join(dir, "ledger"))
+		Ω(err).NotTo(HaveOccurred())
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		scheduler := NewScheduler(memory, bus, ledger, notifier)
+		scheduler.RetryBackoff = 10 * time.Millisecond
+		go func() {
+			done <- scheduler.Run(ctx)
+		}()
+		Eventually(bus.Subscribed).Should(BeTrue())
+		stop = func() {
+			cancel()
+			Ω(<-done).Should(BeNil())
+			ledger.Close()
+			stop = nil
+		}
+	}
+
+	create := func(reminder time.Time, state pb.ToDo_State) string {
+		id, err := memory.Create(context.TODO(), &pb.ToDo{Title: "remind me", State: state, Reminder: &timestamp.Timestamp{Seconds: reminder.Unix()}})
+		Ω(err).NotTo(HaveOccurred())
+		return id
+	}
+
+	Context("With a reminder in the past", func() {
+		It("should deliver it once even after a restart", func() {
+			id := create(time.Now().Add(-time.Minute), pb.ToDo_NOT_STARTED)
+			start()
+			Eventually(notified).Should(Receive(Equal(id)))
+
+			stop()
+			start()
+			Consistently(notified, 200*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Context("With notifiers which fail", func() {
+		It("should retry the reminder until it is delivered", func() {
+			notifier = &flaky{fails: 2, recorder: notified}
+			id := create(time.Now().Add(-time.Minute), pb.ToDo_NOT_STARTED)
+			start()
+			Eventually(notified).Should(Receive(Equal(id)))
+
+			stop()
+			start()
+			Consistently(notified, 200*time.Millisecond).ShouldNot(Receive())
+		})
+
+		It("should deliver the reminder after a restart if it never succeeded", func() {
+			notifier = &flaky{fails: 1000, recorder: notified}
+			id := create(time.Now().Add(-time.Minute), pb.ToDo_NOT_STARTED)
+			start()
+			Consistently(notified, 100*time.Millisecond).ShouldNot(Receive())
+
+			stop()
+			notifier = notified
+			start()
+			Eventually(notified).Should(Receive(Equal(id)))
+		})
+	})
+
+	Context("With a slow notifier", func() {
+		It("should deliver the other reminders meanwhile", func() {
+			blocked := create(time.Now().Add(-time.Minute), pb.ToDo_NOT_STARTED)
+			other := create(time.Now().Add(-time.Minute), pb.ToDo_NOT_STARTED)
+			release := make(chan struct{})
+			notifier = &slow{id: blocked, release: release, recorder: notified}
+			start()
+			Eventually(notified).Should(Receive(Equal(other)))
+
+			close(release)
+			Eventually(notified).Should(Receive(Equal(blocked)))
+		})
+	})
+
+	Context("With a reminder in the future", func() {
+		It("should deliver it when it comes due", func() {
+			// the reminders are in seconds, it is due in 1 to 2 seconds
+			id := create(time.Now().Add(2*time.Second), pb.ToDo_NOT_STARTED)
+			start()
+			Consistently(notified, 500*time.Millisecond).ShouldNot(Receive())
+			Eventually(notified, 3*time.Second).Should(Receive(Equal(id)))
+		})
+	})
+
+	Context("With a todo done", func() {
+		It("should not deliver its reminder", func() {
+			create(time.Now().Add(-time.Minute), pb.ToDo_DONE)
+			start()
+			Consistently(notified, 200*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Context("With an updated reminder", func() {
+		It("should deliver the new reminder", func() {
+			id := create(time.Now().Add(time.Hour), pb.ToDo_NOT_STARTED)
+			start()
+
+			todo := &pb.ToDo{Id: id, Reminder: &timestamp.Timestamp{Seconds: time.Now().Add(-time.Second).Unix()}}
+			_, err := memory.Update(context.TODO(), todo, []string{store.FieldReminder}, 0)
+			Ω(err).NotTo(HaveOccurred())
+			updated, err := memory.Read(context.TODO(), id)
+			Ω(err).NotTo(HaveOccurred())
+			bus.Publish(&pb.Event{Type: pb.Event_UPDATED, ToDo: updated})
+
+			Eventually(notified).Should(Receive(Equal(id)))
+		})
+	})
+
+	Context("With a deleted todo", func() {
+		It("should not deliver its reminder", func() {
+			id := create(time.Now().Add(2*time.Second), pb.ToDo_NOT_STARTED)
+			start()
+
+			bus.Publish(&pb.Event{Type: pb.Event_DELETED, ToDo: &pb.ToDo{Id: id}})
+			Consistently(notified, 2500*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Context("With tenants", func() {
+		It("should deliver the reminders of every tenant", func() {
+			router := store.NewRouter(func(string) (store.Store, error) { return store.NewMemory(), nil }, store.Quota{}, nil)
+			defer router.Close()
+			acme := tenant.NewContext(context.Background(), "acme")
+			id, err := router.Create(acme, &pb.ToDo{Title: "remind me", Reminder: &timestamp.Timestamp{Seconds: time.Now().Add(-time.Minute).Unix()}})
+			Ω(err).NotTo(HaveOccurred())
+
+			ledger, err := OpenLedger(filepath.Join(dir, "ledger"))
+			Ω(err).NotTo(HaveOccurred())
+			defer ledger.Close()
+			scheduler := NewScheduler(router, bus, ledger, notified)
+			scheduler.Tenants = []string{"acme", "globex"}
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- scheduler.Run(ctx) }()
+			defer func() {
+				cancel()
+				Ω(<-done).Should(BeNil())
+			}()
+			Eventually(notified).Should(Receive(Equal(id)))
+
+			globex := tenant.NewContext(context.Background(), "globex")
+			id, err = router.Create(globex, &pb.ToDo{Title: "remind me", Reminder: &timestamp.Timestamp{Seconds: time.Now().Add(-time.Second).Unix()}})
+			Ω(err).NotTo(HaveOccurred())
+			created, err := router.Read(globex, id)
+			Ω(err).NotTo(HaveOccurred())
+			bus.Publish(&pb.Event{Type: pb.Event_CREATED, ToDo: created, Tenant: "globex"})
+			Eventually(notified).Should(Receive(Equal(id)))
+		})
+	})
+
+	Context("With a closed bus", func() {
+		It("should stop", func() {
+			ledger, err := OpenLedger(filepath.Join(dir, "ledger"))
+			Ω(err).NotTo(HaveOccurred())
+			defer ledger.Close()
+			bus.Close()
+			Ω(NewScheduler(memory, bus, ledger).Run(context.TODO())).Should(BeNil())
+		})
+	})
+})
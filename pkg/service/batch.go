@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc/status"
+)
+
+// MaxBatchSize the maximum number of requests of a batch
+const MaxBatchSize = 1000
+
+// batch the failures of the items of a batch and the items sent to the store
+type batch struct {
+	atomic bool
+	errs   []error
+	// items the indexes of the items which pass the checks of the service, in the order of the store
+	items []int
+	// ids the IDs of the todos of the batch, a todo is changed at most once by batch
+	ids map[string]bool
+}
+
+// newBatch returns the batch of size items, InvalidArgument if it is empty or too large
+func newBatch(size int, atomic bool) (*batch, error) {
+	if size == 0 || size > MaxBatchSize {
+		return nil, invalidArgument("requests", fmt.Sprintf("the batch must have between 1 and %d requests", MaxBatchSize))
+	}
+	return &batch{atomic: atomic, errs: make([]error, size), ids: make(map[string]bool)}, nil
+}
+
+// unique returns InvalidArgument if the todo id is already in the batch, field is the field of the request with the ID
+func (b *batch) unique(id string, field string) error {
+	if b.ids[id] {
+		return invalidArgument(field, fmt.Sprintf("the todo %s is already in the batch", id))
+	}
+	b.ids[id] = true
+	return nil
+}
+
+// check records the failure of the checks of the item i, else sends it to the store
+func (b *batch) check(i int, err error) bool {
+	if err != nil {
+		b.errs[i] = err
+		return false
+	}
+	b.items = append(b.items, i)
+	return true
+}
+
+// aborted tells if an item of an atomic batch failed the checks, the others fail with Aborted
+func (b *batch) aborted() bool {
+	if !b.atomic || len(b.items) == len(b.errs) {
+		return false
+	}
+	for i, err := range b.errs {
+		if err == nil {
+			b.errs[i] = toStatus(store.ErrBatchAborted, "")
+		}
+	}
+	return true
+}
+
+// stored records the failures of the results of the store, field is the field of the request with the ID.
+// It calls done on the items which succeed.
+func (b *batch) stored(results []store.Result, field string, done func(i int, r store.Result)) {
+	for j, r := range results {
+		i := b.items[j]
+		if r.Err != nil {
+			b.errs[i] = toStatus(r.Err, fmt.Sprintf("requests[%d].%s", i, field))
+			continue
+		}
+		done(i, r)
+	}
+}
+
+// status returns the failure of the item i, nil if it succeeded
+func (b *batch) status(i int) *pb.BatchStatus {
+	if b.errs[i] == nil {
+		return nil
+	}
+	st := status.Convert(b.errs[i]).Proto()
+	return &pb.BatchStatus{Code: st.GetCode(), Message: st.GetMessage(), Details: st.GetDetails()}
+}
+
+// prefix returns the prefix of the fields of the item i in the errors
+func prefix(i int) string {
+	return fmt.Sprintf("requests[%d].", i)
+}
+
+// BatchCreate creates many todos, each one is checked like in Create
+func (s *ToDoServiceServer) BatchCreate(ctx context.Context, r *pb.BatchCreateRequest) (*pb.BatchCreateResponse, error) {
+	b, err := newBatch(len(r.GetRequests()), r.GetAtomic())
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRequestID(ctx)
+	todos := []*pb.ToDo{}
+	for i, request := range r.GetRequests() {
+		todo, err := s.checkCreate(ctx, request, prefix(i))
+		if b.check(i, err) {
+			todos = append(todos, todo)
+		}
+	}
+
+	response := &pb.BatchCreateResponse{Results: make([]*pb.BatchCreateResponse_Result, len(b.errs))}
+	for i := range response.Results {
+		response.Results[i] = &pb.BatchCreateResponse_Result{}
+	}
+	if !b.aborted() {
+		results, err := s.store.CreateMany(ctx, todos, r.GetAtomic())
+		if err != nil {
+			return nil, toStatus(err, "")
+		}
+		b.stored(results, "toDo.id", func(i int, result store.Result) {
+			response.Results[i].Id = result.ID
+			s.changed(ctx, pb.Event_CREATED, result.ID, nil)
+		})
+	}
+	for i, result := range response.Results {
+		result.Status = b.status(i)
+	}
+	return response, nil
+}
+
+// BatchUpdate updates many todos, each one is checked like in Update
+func (s *ToDoServiceServer) BatchUpdate(ctx context.Context, r *pb.BatchUpdateRequest) (*pb.BatchUpdateResponse, error) {
+	b, err := newBatch(len(r.GetRequests()), r.GetAtomic())
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRequestID(ctx)
+	changes := []store.Change{}
+	previous := map[int]*pb.ToDo{}
+	for i, request := range r.GetRequests() {
+		paths, err := s.checkUpdate(ctx, request, prefix(i))
+		if err == nil {
+			err = b.unique(request.GetToDo().GetId(), prefix(i)+"toDo.id")
+		}
+		if b.check(i, err) {
+			changes = append(changes, store.Change{ToDo: request.GetToDo(), Paths: paths, Version: request.GetExpectedVersion()})
+			previous[i] = s.previous(ctx, request.GetToDo().GetId())
+		}
+	}
+
+	response := &pb.BatchUpdateResponse{Results: make([]*pb.BatchUpdateResponse_Result, len(b.errs))}
+	for i := range response.Results {
+		response.Results[i] = &pb.BatchUpdateResponse_Result{}
+	}
+	if !b.aborted() {
+		results, err := s.store.UpdateMany(ctx, changes, r.GetAtomic())
+		if err != nil {
+			return nil, toStatus(err, "")
+		}
+		b.stored(results, "toDo.id", func(i int, result store.Result) {
+			response.Results[i].Updated = result.Count
+			if result.Count > 0 {
+				s.changed(ctx, pb.Event_UPDATED, r.GetRequests()[i].GetToDo().GetId(), previous[i])
+			}
+		})
+	}
+	for i, result := range response.Results {
+		result.Status = b.status(i)
+	}
+	return response, nil
+}
+
+// BatchDelete moves many todos to the trash, each one is checked like in Delete
+func (s *ToDoServiceServer) BatchDelete(ctx context.Context, r *pb.BatchDeleteRequest) (*pb.BatchDeleteResponse, error) {
+	b, err := newBatch(len(r.GetRequests()), r.GetAtomic())
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRequestID(ctx)
+	changes := []store.Change{}
+	previous := map[int]*pb.ToDo{}
+	for i, request := range r.GetRequests() {
+		err := s.authorize(ctx, request.GetId(), prefix(i)+"id")
+		if err == nil {
+			err = b.unique(request.GetId(), prefix(i)+"id")
+		}
+		if b.check(i, err) {
+			changes = append(changes, store.Change{ToDo: &pb.ToDo{Id: request.GetId()}, Version: request.GetExpectedVersion()})
+			previous[i] = s.previous(ctx, request.GetId())
+		}
+	}
+
+	response := &pb.BatchDeleteResponse{Results: make([]*pb.BatchDeleteResponse_Result, len(b.errs))}
+	for i := range response.Results {
+		response.Results[i] = &pb.BatchDeleteResponse_Result{}
+	}
+	if !b.aborted() {
+		results, err := s.store.DeleteMany(ctx, changes, r.GetAtomic())
+		if err != nil {
+			return nil, toStatus(err, "")
+		}
+		b.stored(results, "id", func(i int, result store.Result) {
+			response.Results[i].Deleted = result.Count
+			s.changed(ctx, pb.Event_DELETED, r.GetRequests()[i].GetId(), previous[i])
+		})
+	}
+	for i, result := range response.Results {
+		result.Status = b.status(i)
+	}
+	return response, nil
+}
@@ -0,0 +1,217 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sjeandeaux/todo/pkg/auth"
+	. "github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch", func() {
+
+	var (
+		server     *ToDoServiceServer
+		alice, bob context.Context
+	)
+
+	//helper the code of the status of an item, OK without status
+	code := func(s *pb.BatchStatus) codes.Code {
+		return codes.Code(s.GetCode())
+	}
+
+	//helper create todos of alice in a batch
+	create := func(titles ...string) []string {
+		r := &pb.BatchCreateRequest{}
+		for _, title := range titles {
+			r.Requests = append(r.Requests, &pb.CreateRequest{ToDo: &pb.ToDo{Title: title}})
+		}
+		response, err := server.BatchCreate(alice, r)
+		Ω(err).NotTo(HaveOccurred())
+		ids := []string{}
+		for _, result := range response.GetResults() {
+			Ω(result.GetStatus()).Should(BeNil())
+			ids = append(ids, result.GetId())
+		}
+		return ids
+	}
+
+	//helper the title of the todo id of alice
+	title := func(id string) string {
+		response, err := server.Read(alice, &pb.ReadRequest{Id: id})
+		Ω(err).NotTo(HaveOccurred())
+		return response.GetToDo().GetTitle()
+	}
+
+	BeforeEach(func() {
+		server = NewToDoServiceServer(store.NewMemory())
+		alice = auth.NewContext(context.Background(), &auth.Identity{Subject: "alice"})
+		bob = auth.NewContext(context.Background(), &auth.Identity{Subject: "bob"})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("BatchCreate", func() {
+		It("should create the todos which pass the checks", func() {
+			response, err := server.BatchCreate(alice, &pb.BatchCreateRequest{Requests: []*pb.CreateRequest{
+				{ToDo: &pb.ToDo{Title: "first"}},
+				{},
+				{ToDo: &pb.ToDo{Title: "third"}},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			results := response.GetResults()
+			Ω(results).Should(HaveLen(3))
+			Ω(title(results[0].GetId())).Should(Equal("first"))
+			Ω(title(results[2].GetId())).Should(Equal("third"))
+			Ω(results[1].GetId()).Should(BeEmpty())
+			Ω(code(results[1].GetStatus())).Should(Equal(codes.InvalidArgument))
+
+			badRequest := &errdetails.BadRequest{}
+			Ω(results[1].GetStatus().GetDetails()).Should(HaveLen(1))
+			Ω(ptypes.UnmarshalAny(results[1].GetStatus().GetDetails()[0], badRequest)).Should(Succeed())
+			Ω(badRequest.GetFieldViolations()[0].GetField()).Should(Equal("requests[1].toDo"))
+		})
+
+		It("should create nothing when an item of an atomic batch fails", func() {
+			response, err := server.BatchCreate(alice, &pb.BatchCreateRequest{Atomic: true, Requests: []*pb.CreateRequest{
+				{ToDo: &pb.ToDo{Title: "first"}},
+				{ToDo: &pb.ToDo{Title: "bob's", Owner: "bob"}},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(code(response.GetResults()[0].GetStatus())).Should(Equal(codes.Aborted))
+			Ω(code(response.GetResults()[1].GetStatus())).Should(Equal(codes.PermissionDenied))
+
+			search, err := server.Search(alice, &pb.SearchRequest{})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(search.GetToDos()).Should(BeEmpty())
+		})
+
+		It("should record the creations with the same request ID", func() {
+			ids := create("first", "second")
+			first, err := server.GetHistory(alice, &pb.GetHistoryRequest{Id: ids[0]})
+			Ω(err).NotTo(HaveOccurred())
+			second, err := server.GetHistory(alice, &pb.GetHistoryRequest{Id: ids[1]})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(first.GetRecords()[0].GetRequestId()).ShouldNot(BeEmpty())
+			Ω(first.GetRecords()[0].GetRequestId()).Should(Equal(second.GetRecords()[0].GetRequestId()))
+		})
+
+		It("should refuse the empty and the too large batches", func() {
+			_, err := server.BatchCreate(alice, &pb.BatchCreateRequest{})
+			Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			_, err = server.BatchCreate(alice, &pb.BatchCreateRequest{Requests: make([]*pb.CreateRequest, MaxBatchSize+1)})
+			Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("BatchUpdate", func() {
+		It("should update the todos the caller can change", func() {
+			ids := create("first", "second", "third")
+			response, err := server.BatchUpdate(bob, &pb.BatchUpdateRequest{Requests: []*pb.UpdateRequest{
+				{ToDo: &pb.ToDo{Id: ids[0], Title: "changed"}},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(code(response.GetResults()[0].GetStatus())).Should(Equal(codes.NotFound))
+
+			mask := &field_mask.FieldMask{Paths: []string{store.FieldTitle}}
+			response, err = server.BatchUpdate(alice, &pb.BatchUpdateRequest{Requests: []*pb.UpdateRequest{
+				{ToDo: &pb.ToDo{Id: ids[0], Title: "changed"}, UpdateMask: mask, ExpectedVersion: 1},
+				{ToDo: &pb.ToDo{Id: ids[1], Title: "changed"}, UpdateMask: mask, ExpectedVersion: 2},
+				{ToDo: &pb.ToDo{Id: ids[2], Title: "third"}, UpdateMask: mask},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			results := response.GetResults()
+			Ω(results[0].GetUpdated()).Should(Equal(int64(1)))
+			Ω(results[0].GetStatus()).Should(BeNil())
+			Ω(code(results[1].GetStatus())).Should(Equal(codes.Aborted))
+			Ω(results[2].GetUpdated()).Should(Equal(int64(0)))
+			Ω(results[2].GetStatus()).Should(BeNil())
+			Ω(title(ids[0])).Should(Equal("changed"))
+			Ω(title(ids[1])).Should(Equal("second"))
+
+			history, err := server.GetHistory(alice, &pb.GetHistoryRequest{Id: ids[0]})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(history.GetRecords()).Should(HaveLen(2))
+			Ω(history.GetRecords()[1].GetType()).Should(Equal(pb.Event_UPDATED))
+		})
+
+		It("should update nothing when an item of an atomic batch fails", func() {
+			ids := create("first", "second")
+			response, err := server.BatchUpdate(alice, &pb.BatchUpdateRequest{Atomic: true, Requests: []*pb.UpdateRequest{
+				{ToDo: &pb.ToDo{Id: ids[0], Title: "changed"}},
+				{ToDo: &pb.ToDo{Id: ids[1], Title: "changed"}, ExpectedVersion: 7},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(code(response.GetResults()[0].GetStatus())).Should(Equal(codes.Aborted))
+			Ω(code(response.GetResults()[1].GetStatus())).Should(Equal(codes.Aborted))
+			Ω(title(ids[0])).Should(Equal("first"))
+		})
+
+		It("should refuse a todo which is already in the batch", func() {
+			ids := create("first")
+			mask := &field_mask.FieldMask{Paths: []string{store.FieldTitle}}
+			response, err := server.BatchUpdate(alice, &pb.BatchUpdateRequest{Requests: []*pb.UpdateRequest{
+				{ToDo: &pb.ToDo{Id: ids[0], Title: "changed"}, UpdateMask: mask},
+				{ToDo: &pb.ToDo{Id: ids[0], Title: "changed again"}, UpdateMask: mask},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetResults()[0].GetUpdated()).Should(Equal(int64(1)))
+			Ω(code(response.GetResults()[1].GetStatus())).Should(Equal(codes.InvalidArgument))
+			Ω(title(ids[0])).Should(Equal("changed"))
+
+			history, err := server.GetHistory(alice, &pb.GetHistoryRequest{Id: ids[0]})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(history.GetRecords()).Should(HaveLen(2))
+		})
+	})
+
+	Describe("BatchDelete", func() {
+		It("should move the todos to the trash", func() {
+			ids := create("first", "second")
+			response, err := server.BatchDelete(alice, &pb.BatchDeleteRequest{Requests: []*pb.DeleteRequest{
+				{Id: ids[0]},
+				{Id: ids[1], ExpectedVersion: 1},
+				{Id: "bad id"},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			results := response.GetResults()
+			Ω(results[0].GetDeleted()).Should(Equal(int64(1)))
+			Ω(results[1].GetDeleted()).Should(Equal(int64(1)))
+			Ω(code(results[2].GetStatus())).Should(Equal(codes.InvalidArgument))
+
+			search, err := server.Search(alice, &pb.SearchRequest{Trashed: true})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(search.GetToDos()).Should(HaveLen(2))
+		})
+
+		It("should refuse a todo which is already in the batch", func() {
+			ids := create("first")
+			response, err := server.BatchDelete(alice, &pb.BatchDeleteRequest{Requests: []*pb.DeleteRequest{{Id: ids[0]}, {Id: ids[0]}}})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetResults()[0].GetDeleted()).Should(Equal(int64(1)))
+			Ω(code(response.GetResults()[1].GetStatus())).Should(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Context("A store without transaction", func() {
+		It("should refuse the atomic batches", func() {
+			server = NewToDoServiceServer(&mockStore{err: store.ErrAtomicUnsupported})
+			_, err := server.BatchDelete(context.Background(), &pb.BatchDeleteRequest{
+				Atomic:   true,
+				Requests: []*pb.DeleteRequest{{Id: "5d5fbc4b3f6b7b2c9c8b4567"}},
+			})
+			Ω(status.Code(err)).Should(Equal(codes.Unimplemented))
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/validator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invalidArgument returns an InvalidArgument status with the violation on field
+func invalidArgument(field string, description string) error {
+	return withDetails(codes.InvalidArgument, description, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: description,
+		}},
+	})
+}
+
+// invalidMessage returns an InvalidArgument status with the violations found by the validator,
+// the fields are prefixed by prefix
+func invalidMessage(prefix string, err error) error {
+	e, ok := err.(*validator.Error)
+	if !ok {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       prefix + v.Field,
+			Description: v.Description,
+		})
+	}
+	return withDetails(codes.InvalidArgument, e.Error(), badRequest)
+}
+
+// withDetails returns a status with the details, the status without details if they can't be added
+func withDetails(code codes.Code, message string, details ...proto.Message) error {
+	st := status.New(code, message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// toStatus converts the errors of the store in gRPC status, field is the field of the request with the ID
+func toStatus(err error, field string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, store.ErrNotFound):
+		return withDetails(codes.NotFound, err.Error(), &errdetails.ResourceInfo{
+			ResourceType: "todo",
+			Description:  err.Error(),
+		})
+	case errors.Is(err, store.ErrListNotFound):
+		return withDetails(codes.NotFound, err.Error(), &errdetails.ResourceInfo{
+			ResourceType: "list",
+			Description:  err.Error(),
+		})
+	case errors.Is(err, store.ErrInvalidID):
+		return invalidArgument(field, err.Error())
+	case errors.Is(err, store.ErrInvalidPattern):
+		return invalidArgument("pattern", err.Error())
+	case errors.Is(err, store.ErrQuotaExceeded):
+		return withDetails(codes.ResourceExhausted, err.Error(), &errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     "tenant",
+				Description: err.Error(),
+			}},
+		})
+	case errors.Is(err, store.ErrVersionMismatch):
+		// the client reads the todo again then retries
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, store.ErrDuplicateID):
+		return invalidArgument(field, err.Error())
+	case errors.Is(err, store.ErrBatchAborted):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, store.ErrAtomicUnsupported):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, store.ErrUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	log.WithError(err).Error("unexpected error")
+	return status.Error(codes.Internal, err.Error())
+}
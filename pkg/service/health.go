@@ -30,6 +30,11 @@ func (h *HealthChecker) Check(ctx context.Context, req *grpc_health_v1.HealthChe
 	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
 }
 
+// AuthFuncOverride the health checks don't need authentication, the probes have no token
+func (h *HealthChecker) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
+	return ctx, nil
+}
+
 // Watch not yet implemented
 func (h *HealthChecker) Watch(req *grpc_health_v1.HealthCheckRequest, w grpc_health_v1.Health_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "not implemented")
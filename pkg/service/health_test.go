@@ -61,4 +61,16 @@ var _ = Describe("HealthChecker", func() {
 			})
 		})
 	})
+
+	Describe("AuthFuncOverride", func() {
+		Context("With a call without token", func() {
+			It("should accept it", func() {
+				h := HealthChecker{}
+				ctx := context.TODO()
+				actual, err := h.AuthFuncOverride(ctx, "/grpc.health.v1.Health/Check")
+				Ω(actual).Should(Equal(ctx))
+				Ω(err).Should(BeNil())
+			})
+		})
+	})
 })
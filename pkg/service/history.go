@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/auth"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader the metadata of the ID of a request, the gateway forwards the HTTP header X-Request-Id
+const RequestIDHeader = "x-request-id"
+
+// requestID returns the ID of the request from its metadata, else a new ID sent back in the header of the response
+func requestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+	// the call without transport, ex: in the tests, has no header
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+	return id
+}
+
+// withRequestID returns ctx with the ID of the request in its metadata, the changes of a batch share the ID
+func withRequestID(ctx context.Context) context.Context {
+	id := requestID(ctx)
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(RequestIDHeader, id)
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// changed records the change on the todo id in its history then publishes it, previous is the todo before the change
+func (s *ToDoServiceServer) changed(ctx context.Context, t pb.Event_Type, id string, previous *pb.ToDo) {
+	var current *pb.ToDo
+	var err error
+	switch t {
+	case pb.Event_PURGED:
+	case pb.Event_DELETED:
+		current, err = s.store.ReadTrashed(ctx, id)
+	default:
+		current, err = s.store.Read(ctx, id)
+	}
+	if err != nil {
+		log.WithError(err).WithField("id", id).Error("the change is neither recorded nor published")
+		return
+	}
+	s.record(ctx, t, id, previous, current)
+	s.publish(ctx, t, id, previous, current)
+}
+
+// record appends the change to the history of the todo id, previous is nil on CREATED and current is nil on PURGED.
+// The change is already made, a failure is logged.
+func (s *ToDoServiceServer) record(ctx context.Context, t pb.Event_Type, id string, previous *pb.ToDo, current *pb.ToDo) {
+	r := &pb.HistoryRecord{
+		ToDoId:    id,
+		Type:      t,
+		RequestId: requestID(ctx),
+		Changes:   changes(previous, current),
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		r.Actor = identity.Subject
+	}
+	todo := current
+	if todo == nil {
+		todo = previous
+	}
+	r.Version, r.Owner, r.ListId = todo.GetVersion(), todo.GetOwner(), todo.GetListId()
+	if r.Time = current.GetUpdatedAt(); r.Time == nil {
+		r.Time = ptypes.TimestampNow()
+	}
+	if err := s.store.AppendHistory(ctx, r); err != nil {
+		log.WithError(err).WithField("id", id).WithField("type", t).Error("the change is not recorded")
+	}
+}
+
+// historyFields the fields of a todo kept in the history
+var historyFields = []string{
+	store.FieldTitle, store.FieldDescription, store.FieldTags, store.FieldState, store.FieldReminder, store.FieldListID, "owner", "deletedAt",
+}
+
+// changes returns the fields which differ between previous and current, a missing todo has empty values
+func changes(previous *pb.ToDo, current *pb.ToDo) []*pb.HistoryRecord_Change {
+	result := []*pb.HistoryRecord_Change{}
+	for _, field := range historyFields {
+		oldValue, newValue := historyValue(previous, field), historyValue(current, field)
+		if oldValue != newValue {
+			result = append(result, &pb.HistoryRecord_Change{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return result
+}
+
+// historyValue returns the value of the field of the todo in the history, empty if the todo is nil
+func historyValue(todo *pb.ToDo, field string) string {
+	if todo == nil {
+		return ""
+	}
+	switch field {
+	case store.FieldTitle:
+		return todo.GetTitle()
+	case store.FieldDescription:
+		return todo.GetDescription()
+	case store.FieldTags:
+		return strings.Join(todo.GetTags(), ",")
+	case store.FieldState:
+		return todo.GetState().String()
+	case store.FieldReminder:
+		if todo.GetReminder().GetSeconds() == 0 {
+			return ""
+		}
+		return ptypes.TimestampString(todo.GetReminder())
+	case store.FieldListID:
+		return todo.GetListId()
+	case "deletedAt":
+		if todo.GetDeletedAt() == nil {
+			return ""
+		}
+		return ptypes.TimestampString(todo.GetDeletedAt())
+	default:
+		return todo.GetOwner()
+	}
+}
+
+// GetHistory returns the changes on a todo, deleted or not, to the callers who see it.
+// The access is checked on the owner and the list of the todo at its last change.
+func (s *ToDoServiceServer) GetHistory(ctx context.Context, r *pb.GetHistoryRequest) (*pb.GetHistoryResponse, error) {
+	records, err := s.store.History(ctx, r.GetId())
+	if err != nil {
+		return nil, toStatus(err, "id")
+	}
+	last := &pb.ToDo{}
+	if len(records) > 0 {
+		last.Owner, last.ListId = records[len(records)-1].GetOwner(), records[len(records)-1].GetListId()
+	} else if last, err = s.store.Read(ctx, r.GetId()); err != nil {
+		// a todo created before the history has no record
+		return nil, toStatus(err, "id")
+	}
+	if err := s.access(ctx, last, "id", false); err != nil {
+		return nil, err
+	}
+	return &pb.GetHistoryResponse{Records: records}, nil
+}
@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/sjeandeaux/todo/pkg/auth"
+	. "github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("History", func() {
+
+	var (
+		server     *ToDoServiceServer
+		alice, bob context.Context
+		id         string
+	)
+
+	history := func(ctx context.Context, id string) []*pb.HistoryRecord {
+		response, err := server.GetHistory(ctx, &pb.GetHistoryRequest{Id: id})
+		Ω(err).NotTo(HaveOccurred())
+		return response.GetRecords()
+	}
+
+	BeforeEach(func() {
+		server = NewToDoServiceServer(store.NewMemory())
+		alice = auth.NewContext(context.Background(), &auth.Identity{Subject: "alice"})
+		bob = auth.NewContext(context.Background(), &auth.Identity{Subject: "bob"})
+
+		ctx := metadata.NewIncomingContext(alice, metadata.Pairs(RequestIDHeader, "create"))
+		response, err := server.Create(ctx, &pb.CreateRequest{ToDo: &pb.ToDo{Title: "a", Tags: []string{"x", "y"}}})
+		Ω(err).NotTo(HaveOccurred())
+		id = response.GetId()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should record the creation with the actor, the request ID and the new values", func() {
+		records := history(alice, id)
+		Ω(records).Should(HaveLen(1))
+		Ω(records[0].GetToDoId()).Should(Equal(id))
+		Ω(records[0].GetType()).Should(Equal(pb.Event_CREATED))
+		Ω(records[0].GetActor()).Should(Equal("alice"))
+		Ω(records[0].GetRequestId()).Should(Equal("create"))
+		Ω(records[0].GetVersion()).Should(Equal(int64(1)))
+		Ω(records[0].GetTime()).ShouldNot(BeNil())
+		Ω(records[0].GetChanges()).Should(Equal([]*pb.HistoryRecord_Change{
+			{Field: "title", NewValue: "a"},
+			{Field: "tags", NewValue: "x,y"},
+			{Field: "state", OldValue: "", NewValue: "NOT_STARTED"},
+			{Field: "owner", NewValue: "alice"},
+		}))
+	})
+
+	It("should record the changed fields of an update, the deletion then the purge", func() {
+		_, err := server.Update(alice, &pb.UpdateRequest{
+			ToDo:       &pb.ToDo{Id: id, Title: "b", State: pb.ToDo_DONE},
+			UpdateMask: &field_mask.FieldMask{Paths: []string{"title", "state"}},
+		})
+		Ω(err).NotTo(HaveOccurred())
+		_, err = server.Delete(alice, &pb.DeleteRequest{Id: id})
+		Ω(err).NotTo(HaveOccurred())
+		_, err = server.Purge(alice, &pb.PurgeRequest{Id: id})
+		Ω(err).NotTo(HaveOccurred())
+
+		records := history(alice, id)
+		Ω(records).Should(HaveLen(4))
+		Ω(records[1].GetType()).Should(Equal(pb.Event_UPDATED))
+		Ω(records[1].GetVersion()).Should(Equal(int64(2)))
+		Ω(records[1].GetRequestId()).ShouldNot(BeEmpty())
+		Ω(records[1].GetChanges()).Should(Equal([]*pb.HistoryRecord_Change{
+			{Field: "title", OldValue: "a", NewValue: "b"},
+			{Field: "state", OldValue: "NOT_STARTED", NewValue: "DONE"},
+		}))
+		Ω(records[2].GetType()).Should(Equal(pb.Event_DELETED))
+		Ω(records[2].GetVersion()).Should(Equal(int64(3)))
+		Ω(records[2].GetChanges()).Should(HaveLen(1))
+		Ω(records[2].GetChanges()[0].GetField()).Should(Equal("deletedAt"))
+		Ω(records[2].GetChanges()[0].GetNewValue()).ShouldNot(BeEmpty())
+		Ω(records[3].GetType()).Should(Equal(pb.Event_PURGED))
+		Ω(records[3].GetChanges()).Should(ContainElement(&pb.HistoryRecord_Change{Field: "title", OldValue: "b"}))
+	})
+
+	It("should hide the history of a todo to the callers who don't see it", func() {
+		_, err := server.GetHistory(bob, &pb.GetHistoryRequest{Id: id})
+		Ω(status.Code(err)).Should(Equal(codes.NotFound))
+
+		_, err = server.Delete(alice, &pb.DeleteRequest{Id: id})
+		Ω(err).NotTo(HaveOccurred())
+		_, err = server.GetHistory(bob, &pb.GetHistoryRequest{Id: id})
+		Ω(status.Code(err)).Should(Equal(codes.NotFound))
+	})
+
+	It("should return NotFound on a todo which never existed", func() {
+		_, err := server.GetHistory(alice, &pb.GetHistoryRequest{Id: "5d5fbc4b3f6b7b2c9c8b4567"})
+		Ω(status.Code(err)).Should(Equal(codes.NotFound))
+	})
+})
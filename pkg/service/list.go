@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sjeandeaux/todo/pkg/auth"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/validator"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// role returns the role of subject on the list and if subject is the owner or a member of the list
+func role(list *pb.List, subject string) (pb.List_Role, bool) {
+	if list.GetOwner() == subject {
+		return pb.List_OWNER, true
+	}
+	for _, member := range list.GetMembers() {
+		if member.GetSubject() == subject {
+			return member.GetRole(), true
+		}
+	}
+	return pb.List_VIEWER, false
+}
+
+// checkList reads the list id and checks the caller has at least the role minimum on it,
+// the lists of the others look like they don't exist
+func (s *ToDoServiceServer) checkList(ctx context.Context, id string, field string, minimum pb.List_Role) (*pb.List, error) {
+	list, err := s.store.ReadList(ctx, id)
+	if err != nil {
+		return nil, toStatus(err, field)
+	}
+	o, _ := owner(ctx, "")
+	if o == "" {
+		return list, nil
+	}
+	r, ok := role(list, o)
+	if !ok {
+		return nil, toStatus(store.ErrListNotFound, field)
+	}
+	if r < minimum {
+		return nil, status.Errorf(codes.PermissionDenied, "the role %s is required on the list", minimum)
+	}
+	return list, nil
+}
+
+// CreateList creates a list owned by the caller, the members are added by ShareList
+func (s *ToDoServiceServer) CreateList(ctx context.Context, r *pb.CreateListRequest) (*pb.CreateListResponse, error) {
+	if r.GetList() == nil {
+		return nil, invalidArgument("list", "the list is required")
+	}
+	if err := validator.List(r.GetList()); err != nil {
+		return nil, invalidMessage("list.", err)
+	}
+	list := &pb.List{Name: r.GetList().GetName()}
+	var err error
+	if list.Owner, err = creator(ctx, r.GetList().GetOwner()); err != nil {
+		return nil, err
+	}
+	id, err := s.store.CreateList(ctx, list)
+	if err != nil {
+		return nil, toStatus(err, "list.id")
+	}
+	return &pb.CreateListResponse{Id: id}, nil
+}
+
+// ReadList reads a list of the caller
+func (s *ToDoServiceServer) ReadList(ctx context.Context, r *pb.ReadListRequest) (*pb.ReadListResponse, error) {
+	list, err := s.checkList(ctx, r.GetId(), "id", pb.List_VIEWER)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReadListResponse{List: list}, nil
+}
+
+// UpdateList renames a list
+func (s *ToDoServiceServer) UpdateList(ctx context.Context, r *pb.UpdateListRequest) (*pb.UpdateListResponse, error) {
+	if r.GetList() == nil {
+		return nil, invalidArgument("list", "the list is required")
+	}
+	if err := validator.List(r.GetList()); err != nil {
+		return nil, invalidMessage("list.", err)
+	}
+	list, err := s.checkList(ctx, r.GetList().GetId(), "list.id", pb.List_OWNER)
+	if err != nil {
+		return nil, err
+	}
+	list.Name = r.GetList().GetName()
+	updated, err := s.store.UpdateList(ctx, list)
+	if err != nil {
+		return nil, toStatus(err, "list.id")
+	}
+	return &pb.UpdateListResponse{Updated: updated}, nil
+}
+
+// DeleteList deletes a list without todo
+func (s *ToDoServiceServer) DeleteList(ctx context.Context, r *pb.DeleteListRequest) (*pb.DeleteListResponse, error) {
+	if _, err := s.checkList(ctx, r.GetId(), "id", pb.List_OWNER); err != nil {
+		return nil, err
+	}
+	_, total, err := s.store.Search(ctx, store.Query{Request: &pb.SearchRequest{ListId: r.GetId()}, Limit: 1})
+	if err != nil {
+		return nil, toStatus(err, "id")
+	}
+	if total > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "the list still has %d todos", total)
+	}
+	deleted, err := s.store.DeleteList(ctx, r.GetId())
+	if err != nil {
+		return nil, toStatus(err, "id")
+	}
+	return &pb.DeleteListResponse{Deleted: deleted}, nil
+}
+
+// SearchLists returns the lists owned by or shared with the caller, all the lists for an admin
+func (s *ToDoServiceServer) SearchLists(ctx context.Context, r *pb.SearchListsRequest) (*pb.SearchListsResponse, error) {
+	o, _ := owner(ctx, "")
+	lists, err := s.store.SearchLists(ctx, o)
+	if err != nil {
+		return nil, toStatus(err, "")
+	}
+	return &pb.SearchListsResponse{Lists: lists}, nil
+}
+
+// ShareList gives a role on a list to a user, the previous role of the user is replaced
+func (s *ToDoServiceServer) ShareList(ctx context.Context, r *pb.ShareListRequest) (*pb.ShareListResponse, error) {
+	r = proto.Clone(r).(*pb.ShareListRequest)
+	if err := validator.ShareListRequest(r); err != nil {
+		return nil, invalidMessage("", err)
+	}
+	list, err := s.checkList(ctx, r.GetId(), "id", pb.List_OWNER)
+	if err != nil {
+		return nil, err
+	}
+	if r.GetSubject() == list.GetOwner() {
+		return nil, invalidArgument("subject", "the owner of the list keeps the role OWNER")
+	}
+
+	shared := false
+	for _, member := range list.GetMembers() {
+		if member.GetSubject() == r.GetSubject() {
+			member.Role, shared = r.GetRole(), true
+		}
+	}
+	if !shared {
+		list.Members = append(list.Members, &pb.List_Member{Subject: r.GetSubject(), Role: r.GetRole()})
+	}
+	if _, err := s.store.UpdateList(ctx, list); err != nil {
+		return nil, toStatus(err, "id")
+	}
+	return &pb.ShareListResponse{List: list}, nil
+}
+
+// UnshareList removes a user from the members of a list, the members can leave a list
+func (s *ToDoServiceServer) UnshareList(ctx context.Context, r *pb.UnshareListRequest) (*pb.UnshareListResponse, error) {
+	if r.GetSubject() == "" {
+		return nil, invalidArgument("subject", "must not be empty")
+	}
+	minimum := pb.List_OWNER
+	if identity, ok := auth.FromContext(ctx); ok && identity.Subject == r.GetSubject() {
+		minimum = pb.List_VIEWER
+	}
+	list, err := s.checkList(ctx, r.GetId(), "id", minimum)
+	if err != nil {
+		return nil, err
+	}
+	if r.GetSubject() == list.GetOwner() {
+		return nil, invalidArgument("subject", "the owner of the list can't be removed")
+	}
+
+	members := []*pb.List_Member{}
+	for _, member := range list.GetMembers() {
+		if member.GetSubject() != r.GetSubject() {
+			members = append(members, member)
+		}
+	}
+	if len(members) == len(list.GetMembers()) {
+		return &pb.UnshareListResponse{List: list}, nil
+	}
+	list.Members = members
+	if _, err := s.store.UpdateList(ctx, list); err != nil {
+		return nil, toStatus(err, "id")
+	}
+	return &pb.UnshareListResponse{List: list}, nil
+}
+
+// checkTarget checks the caller can add todos to the list listID, the todos out of a list are always allowed
+func (s *ToDoServiceServer) checkTarget(ctx context.Context, listID string, field string) error {
+	if listID == "" {
+		return nil
+	}
+	_, err := s.checkList(ctx, listID, field, pb.List_EDITOR)
+	return err
+}
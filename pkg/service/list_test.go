@@ -0,0 +1,280 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/sjeandeaux/todo/pkg/auth"
+	. "github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("List", func() {
+
+	var (
+		server                                *ToDoServiceServer
+		alice, bob, carol, dave, admin        context.Context
+		groceries, milk, aliceToDo, carolToDo string
+	)
+
+	createList := func(ctx context.Context, name string) string {
+		response, err := server.CreateList(ctx, &pb.CreateListRequest{List: &pb.List{Name: name}})
+		Ω(err).NotTo(HaveOccurred())
+		return response.GetId()
+	}
+
+	share := func(ctx context.Context, id string, subject string, role pb.List_Role) error {
+		_, err := server.ShareList(ctx, &pb.ShareListRequest{Id: id, Subject: subject, Role: role})
+		return err
+	}
+
+	create := func(ctx context.Context, todo *pb.ToDo) (string, error) {
+		response, err := server.Create(ctx, &pb.CreateRequest{ToDo: todo})
+		return response.GetId(), err
+	}
+
+	update := func(ctx context.Context, id string) error {
+		_, err := server.Update(ctx, &pb.UpdateRequest{
+			ToDo:       &pb.ToDo{Id: id, Title: "changed"},
+			UpdateMask: &field_mask.FieldMask{Paths: []string{"title"}},
+		})
+		return err
+	}
+
+	titles := func(ctx context.Context, r *pb.SearchRequest) ([]string, error) {
+		response, err := server.Search(ctx, r)
+		result := []string{}
+		for _, todo := range response.GetToDos() {
+			result = append(result, todo.GetTitle())
+		}
+		return result, err
+	}
+
+	BeforeEach(func() {
+		server = NewToDoServiceServer(store.NewMemory())
+		alice = auth.NewContext(context.Background(), &auth.Identity{Subject: "alice"})
+		bob = auth.NewContext(context.Background(), &auth.Identity{Subject: "bob"})
+		carol = auth.NewContext(context.Background(), &auth.Identity{Subject: "carol"})
+		dave = auth.NewContext(context.Background(), &auth.Identity{Subject: "dave"})
+		admin = auth.NewContext(context.Background(), &auth.Identity{Subject: "root", Roles: []string{auth.RoleAdmin}})
+
+		// alice owns groceries, bob edits it and carol views it
+		groceries = createList(alice, "Groceries")
+		Ω(share(alice, groceries, "bob", pb.List_EDITOR)).Should(Succeed())
+		Ω(share(alice, groceries, "carol", pb.List_VIEWER)).Should(Succeed())
+
+		var err error
+		milk, err = create(bob, &pb.ToDo{Title: "milk", ListId: groceries})
+		Ω(err).NotTo(HaveOccurred())
+		aliceToDo, err = create(alice, &pb.ToDo{Title: "alice"})
+		Ω(err).NotTo(HaveOccurred())
+		carolToDo, err = create(carol, &pb.ToDo{Title: "carol"})
+		Ω(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("CreateList", func() {
+		It("should stamp the list with the caller and ignore the members", func() {
+			response, err := server.CreateList(bob, &pb.CreateListRequest{List: &pb.List{
+				Name:    " Work ",
+				Members: []*pb.List_Member{{Subject: "dave", Role: pb.List_OWNER}},
+			}})
+			Ω(err).NotTo(HaveOccurred())
+			read, err := server.ReadList(bob, &pb.ReadListRequest{Id: response.GetId()})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(read.GetList()).Should(Equal(&pb.List{Id: response.GetId(), Name: "Work", Owner: "bob"}))
+		})
+
+		It("should refuse an empty name", func() {
+			_, err := server.CreateList(alice, &pb.CreateListRequest{List: &pb.List{Name: " "}})
+			Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("ReadList", func() {
+		It("should return the list to its members", func() {
+			for _, ctx := range []context.Context{alice, bob, carol, admin} {
+				response, err := server.ReadList(ctx, &pb.ReadListRequest{Id: groceries})
+				Ω(err).NotTo(HaveOccurred())
+				Ω(response.GetList().GetMembers()).Should(Equal([]*pb.List_Member{
+					{Subject: "bob", Role: pb.List_EDITOR},
+					{Subject: "carol", Role: pb.List_VIEWER},
+				}))
+			}
+		})
+
+		It("should hide the list from the others", func() {
+			_, err := server.ReadList(dave, &pb.ReadListRequest{Id: groceries})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("SearchLists", func() {
+		It("should return the lists of the caller", func() {
+			work := createList(dave, "Work")
+			response, err := server.SearchLists(carol, &pb.SearchListsRequest{})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetLists()).Should(HaveLen(1))
+			Ω(response.GetLists()[0].GetId()).Should(Equal(groceries))
+
+			response, err = server.SearchLists(admin, &pb.SearchListsRequest{})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetLists()).Should(HaveLen(2))
+			Ω(response.GetLists()[1].GetId()).Should(Equal(work))
+		})
+	})
+
+	Describe("ShareList", func() {
+		It("should replace the role of a member", func() {
+			Ω(share(alice, groceries, "carol", pb.List_EDITOR)).Should(Succeed())
+			Ω(update(carol, milk)).Should(Succeed())
+		})
+
+		It("should let a member with the role OWNER share the list", func() {
+			Ω(share(alice, groceries, "bob", pb.List_OWNER)).Should(Succeed())
+			Ω(share(bob, groceries, "dave", pb.List_VIEWER)).Should(Succeed())
+		})
+
+		It("should deny the sharing without the role OWNER", func() {
+			Ω(status.Code(share(bob, groceries, "dave", pb.List_VIEWER))).Should(Equal(codes.PermissionDenied))
+			Ω(status.Code(share(dave, groceries, "dave", pb.List_OWNER))).Should(Equal(codes.NotFound))
+		})
+
+		It("should refuse to change the role of the owner", func() {
+			Ω(status.Code(share(alice, groceries, "alice", pb.List_VIEWER))).Should(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("UnshareList", func() {
+		It("should remove the member", func() {
+			response, err := server.UnshareList(alice, &pb.UnshareListRequest{Id: groceries, Subject: "carol"})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetList().GetMembers()).Should(Equal([]*pb.List_Member{{Subject: "bob", Role: pb.List_EDITOR}}))
+			_, err = server.Read(carol, &pb.ReadRequest{Id: milk})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+
+		It("should let a member leave the list", func() {
+			_, err := server.UnshareList(carol, &pb.UnshareListRequest{Id: groceries, Subject: "carol"})
+			Ω(err).NotTo(HaveOccurred())
+		})
+
+		It("should deny the removal of another member without the role OWNER", func() {
+			_, err := server.UnshareList(bob, &pb.UnshareListRequest{Id: groceries, Subject: "carol"})
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+	})
+
+	Describe("UpdateList and DeleteList", func() {
+		It("should let the owner rename the list", func() {
+			response, err := server.UpdateList(alice, &pb.UpdateListRequest{List: &pb.List{Id: groceries, Name: "Shopping"}})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetUpdated()).Should(Equal(int64(1)))
+		})
+
+		It("should deny the renaming to an editor", func() {
+			_, err := server.UpdateList(bob, &pb.UpdateListRequest{List: &pb.List{Id: groceries, Name: "Shopping"}})
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+
+		It("should refuse to delete a list with todos", func() {
+			_, err := server.DeleteList(alice, &pb.DeleteListRequest{Id: groceries})
+			Ω(status.Code(err)).Should(Equal(codes.FailedPrecondition))
+
+			_, err = server.Delete(bob, &pb.DeleteRequest{Id: milk})
+			Ω(err).NotTo(HaveOccurred())
+			response, err := server.DeleteList(alice, &pb.DeleteListRequest{Id: groceries})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetDeleted()).Should(Equal(int64(1)))
+		})
+	})
+
+	Describe("ToDos in a list", func() {
+		It("should let the members read the todo", func() {
+			for _, ctx := range []context.Context{alice, bob, carol} {
+				_, err := server.Read(ctx, &pb.ReadRequest{Id: milk})
+				Ω(err).NotTo(HaveOccurred())
+			}
+			_, err := server.Read(dave, &pb.ReadRequest{Id: milk})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+
+		It("should let the editors change the todo", func() {
+			Ω(update(alice, milk)).Should(Succeed())
+			Ω(update(bob, milk)).Should(Succeed())
+			Ω(status.Code(update(carol, milk))).Should(Equal(codes.PermissionDenied))
+			Ω(status.Code(update(dave, milk))).Should(Equal(codes.NotFound))
+			_, err := server.Delete(carol, &pb.DeleteRequest{Id: milk})
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+
+		It("should let the owner change the todo out of the editors of the list", func() {
+			_, err := server.UnshareList(alice, &pb.UnshareListRequest{Id: groceries, Subject: "bob"})
+			Ω(err).NotTo(HaveOccurred())
+			_, err = server.Read(bob, &pb.ReadRequest{Id: milk})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(update(bob, milk)).Should(Succeed())
+
+			Ω(share(alice, groceries, "bob", pb.List_VIEWER)).Should(Succeed())
+			Ω(update(bob, milk)).Should(Succeed())
+			Ω(status.Code(update(carol, milk))).Should(Equal(codes.PermissionDenied))
+			_, err = server.Delete(bob, &pb.DeleteRequest{Id: milk})
+			Ω(err).NotTo(HaveOccurred())
+		})
+
+		It("should let only the editors create a todo in the list", func() {
+			_, err := create(carol, &pb.ToDo{Title: "bread", ListId: groceries})
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+			_, err = create(dave, &pb.ToDo{Title: "bread", ListId: groceries})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+			_, err = create(alice, &pb.ToDo{Title: "bread", ListId: "5dc2d3d4aba443c197307ea2"})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+
+		It("should move a todo to a list only for an editor of the list", func() {
+			move := func(ctx context.Context, id string) error {
+				_, err := server.Update(ctx, &pb.UpdateRequest{
+					ToDo:       &pb.ToDo{Id: id, ListId: groceries},
+					UpdateMask: &field_mask.FieldMask{Paths: []string{"listId"}},
+				})
+				return err
+			}
+			Ω(status.Code(move(carol, carolToDo))).Should(Equal(codes.PermissionDenied))
+			Ω(move(alice, aliceToDo)).Should(Succeed())
+			Ω(titles(bob, &pb.SearchRequest{ListId: groceries})).Should(Equal([]string{"milk", "alice"}))
+		})
+
+		It("should search the todos of the list for the members", func() {
+			Ω(titles(carol, &pb.SearchRequest{ListId: groceries})).Should(Equal([]string{"milk"}))
+			Ω(titles(carol, &pb.SearchRequest{})).Should(Equal([]string{"carol"}))
+			_, err := titles(dave, &pb.SearchRequest{ListId: groceries})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+
+		It("should watch the todos of the list for the members", func() {
+			ctx, cancel := context.WithCancel(carol)
+			defer cancel()
+			stream := &mockWatchStream{ctx: ctx, events: make(chan *pb.Event, 10)}
+			go func(server *ToDoServiceServer, stream *mockWatchStream) {
+				server.Watch(&pb.WatchRequest{ListId: groceries}, stream)
+			}(server, stream)
+			Eventually(server.Events().Subscribed).Should(BeTrue())
+
+			create(alice, &pb.ToDo{Title: "out of the list"})
+			id, err := create(bob, &pb.ToDo{Title: "bread", ListId: groceries})
+			Ω(err).NotTo(HaveOccurred())
+
+			e := <-stream.events
+			Ω(e.GetToDo().GetId()).Should(Equal(id))
+		})
+	})
+})
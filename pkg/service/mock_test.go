@@ -0,0 +1,188 @@
+package service_test
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	. "github.com/onsi/gomega"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+type mockStore struct {
+	expectedToDo  *pb.ToDo
+	expectedID    string
+	expectedPaths []string
+	expectedQuery store.Query
+	expectedList  *pb.List
+	// expectedVersion the version of Update, Delete and Purge
+	expectedVersion int64
+
+	id    string
+	todo  *pb.ToDo
+	todos []*pb.ToDo
+	list  *pb.List
+	lists []*pb.List
+	count int64
+	total int64
+	err   error
+	// history the records appended and returned by History
+	history []*pb.HistoryRecord
+	// results the results of the batches
+	results []store.Result
+}
+
+var _ store.Store = &mockStore{}
+
+func (s *mockStore) Create(ctx context.Context, todo *pb.ToDo) (string, error) {
+	Ω(todo).Should(Equal(s.expectedToDo))
+	return s.id, s.err
+}
+
+func (s *mockStore) CreateMany(ctx context.Context, todos []*pb.ToDo, atomic bool) ([]store.Result, error) {
+	return s.results, s.err
+}
+
+func (s *mockStore) UpdateMany(ctx context.Context, changes []store.Change, atomic bool) ([]store.Result, error) {
+	return s.results, s.err
+}
+
+func (s *mockStore) DeleteMany(ctx context.Context, changes []store.Change, atomic bool) ([]store.Result, error) {
+	return s.results, s.err
+}
+
+// Read checks the id only if expectedID is set, the changes read the todo for the history
+func (s *mockStore) Read(ctx context.Context, id string) (*pb.ToDo, error) {
+	if s.expectedID != "" {
+		Ω(id).Should(Equal(s.expectedID))
+	}
+	return s.todo, s.err
+}
+
+func (s *mockStore) Update(ctx context.Context, todo *pb.ToDo, paths []string, version int64) (int64, error) {
+	Ω(todo).Should(Equal(s.expectedToDo))
+	Ω(paths).Should(Equal(s.expectedPaths))
+	Ω(version).Should(Equal(s.expectedVersion))
+	return s.count, s.err
+}
+
+func (s *mockStore) Delete(ctx context.Context, id string, version int64) (int64, error) {
+	Ω(id).Should(Equal(s.expectedID))
+	Ω(version).Should(Equal(s.expectedVersion))
+	return s.count, s.err
+}
+
+func (s *mockStore) ReadTrashed(ctx context.Context, id string) (*pb.ToDo, error) {
+	Ω(id).Should(Equal(s.expectedID))
+	return s.todo, s.err
+}
+
+func (s *mockStore) Restore(ctx context.Context, id string) (int64, error) {
+	Ω(id).Should(Equal(s.expectedID))
+	return s.count, s.err
+}
+
+func (s *mockStore) Purge(ctx context.Context, id string, version int64) (int64, error) {
+	Ω(id).Should(Equal(s.expectedID))
+	Ω(version).Should(Equal(s.expectedVersion))
+	return s.count, s.err
+}
+
+func (s *mockStore) Search(ctx context.Context, q store.Query) ([]*pb.ToDo, int64, error) {
+	Ω(q).Should(Equal(s.expectedQuery))
+	return s.todos, s.total, s.err
+}
+
+func (s *mockStore) Iterate(ctx context.Context, q store.Query, fn func(*pb.ToDo) error) error {
+	Ω(q).Should(Equal(s.expectedQuery))
+	for _, todo := range s.todos {
+		if err := fn(todo); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+func (s *mockStore) CreateList(ctx context.Context, list *pb.List) (string, error) {
+	Ω(list).Should(Equal(s.expectedList))
+	return s.id, s.err
+}
+
+func (s *mockStore) ReadList(ctx context.Context, id string) (*pb.List, error) {
+	Ω(id).Should(Equal(s.expectedID))
+	return s.list, s.err
+}
+
+func (s *mockStore) UpdateList(ctx context.Context, list *pb.List) (int64, error) {
+	Ω(list).Should(Equal(s.expectedList))
+	return s.count, s.err
+}
+
+func (s *mockStore) DeleteList(ctx context.Context, id string) (int64, error) {
+	Ω(id).Should(Equal(s.expectedID))
+	return s.count, s.err
+}
+
+func (s *mockStore) SearchLists(ctx context.Context, subject string) ([]*pb.List, error) {
+	return s.lists, s.err
+}
+
+func (s *mockStore) AppendHistory(ctx context.Context, record *pb.HistoryRecord) error {
+	s.history = append(s.history, record)
+	return nil
+}
+
+func (s *mockStore) History(ctx context.Context, id string) ([]*pb.HistoryRecord, error) {
+	Ω(id).Should(Equal(s.expectedID))
+	return s.history, s.err
+}
+
+func (s *mockStore) Ping(ctx context.Context) error {
+	return s.err
+}
+
+func (s *mockStore) Close() error {
+	return nil
+}
+
+// recordHook keeps the changes given to the hook
+type recordHook struct {
+	events []*pb.Event
+}
+
+func (h *recordHook) Changed(e *pb.Event) {
+	h.events = append(h.events, e)
+}
+
+// iterateStore calls iterated after the todos are iterated
+type iterateStore struct {
+	store.Store
+	iterated func()
+}
+
+func (s *iterateStore) Iterate(ctx context.Context, q store.Query, fn func(*pb.ToDo) error) error {
+	err := s.Store.Iterate(ctx, q, fn)
+	s.iterated()
+	return err
+}
+
+// mockSearchStream keeps the todos sent by SearchStream
+type mockSearchStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	todos []*pb.ToDo
+	err   error
+}
+
+func (s *mockSearchStream) Context() context.Context {
+	if s.ctx == nil {
+		return context.TODO()
+	}
+	return s.ctx
+}
+
+func (s *mockSearchStream) Send(todo *pb.ToDo) error {
+	s.todos = append(s.todos, todo)
+	return s.err
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sjeandeaux/todo/pkg/auth"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// owner returns the owner of the todos the caller accesses: requested for an admin or without authentication,
+// empty meaning all the owners, else the subject of the caller who can't request another owner.
+func owner(ctx context.Context, requested string) (string, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok || identity.HasRole(auth.RoleAdmin) {
+		return requested, nil
+	}
+	if requested != "" && requested != identity.Subject {
+		return "", status.Error(codes.PermissionDenied, "only an admin can access the todos of another owner")
+	}
+	return identity.Subject, nil
+}
+
+// creator returns the owner of a new todo, the caller if the requested owner is empty
+func creator(ctx context.Context, requested string) (string, error) {
+	o, err := owner(ctx, requested)
+	if err != nil || o != "" {
+		return o, err
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		return identity.Subject, nil
+	}
+	return "", nil
+}
+
+// authorize returns NotFound if the caller can't see the todo id and PermissionDenied if it can't change it
+func (s *ToDoServiceServer) authorize(ctx context.Context, id string, field string) error {
+	if o, _ := owner(ctx, ""); o == "" {
+		return nil
+	}
+	todo, err := s.store.Read(ctx, id)
+	if err != nil {
+		return toStatus(err, field)
+	}
+	return s.access(ctx, todo, field, true)
+}
+
+// access returns NotFound if the caller can't see the todo and PermissionDenied if write is set and it can't change it.
+// The owner of the todo and the members of its list see it, the owner of the todo
+// and the editors of its list change it, even if the owner left the list. The todos of the others look like they don't exist.
+func (s *ToDoServiceServer) access(ctx context.Context, todo *pb.ToDo, field string, write bool) error {
+	o, _ := owner(ctx, "")
+	if o == "" {
+		return nil
+	}
+	inList, member, r := false, false, pb.List_VIEWER
+	if todo.GetListId() != "" {
+		list, err := s.store.ReadList(ctx, todo.GetListId())
+		switch {
+		case err == nil:
+			inList = true
+			r, member = role(list, o)
+		case !errors.Is(err, store.ErrListNotFound):
+			return toStatus(err, field)
+		}
+	}
+	switch {
+	case !member && todo.GetOwner() != o:
+		return toStatus(store.ErrNotFound, field)
+	case !write, !inList, todo.GetOwner() == o, r >= pb.List_EDITOR:
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "the role EDITOR is required on the list of the todo")
+}
+
+// scope returns the owner to filter in a search of the todos of the list listID,
+// the members of a list search all its todos
+func (s *ToDoServiceServer) scope(ctx context.Context, requested string, listID string) (string, error) {
+	if listID == "" {
+		return owner(ctx, requested)
+	}
+	if _, err := s.checkList(ctx, listID, "listId", pb.List_VIEWER); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
@@ -0,0 +1,200 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/sjeandeaux/todo/pkg/auth"
+	. "github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Owner", func() {
+
+	var (
+		server                     *ToDoServiceServer
+		alice, bob, admin, nobody  context.Context
+		aliceToDo, bobToDo, noneID string
+	)
+
+	create := func(ctx context.Context, todo *pb.ToDo) string {
+		response, err := server.Create(ctx, &pb.CreateRequest{ToDo: todo})
+		Ω(err).NotTo(HaveOccurred())
+		return response.GetId()
+	}
+
+	owners := func(ctx context.Context, r *pb.SearchRequest) []string {
+		response, err := server.Search(ctx, r)
+		Ω(err).NotTo(HaveOccurred())
+		result := []string{}
+		for _, todo := range response.GetToDos() {
+			result = append(result, todo.GetOwner())
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		server = NewToDoServiceServer(store.NewMemory())
+		alice = auth.NewContext(context.Background(), &auth.Identity{Subject: "alice"})
+		bob = auth.NewContext(context.Background(), &auth.Identity{Subject: "bob"})
+		admin = auth.NewContext(context.Background(), &auth.Identity{Subject: "root", Roles: []string{auth.RoleAdmin}})
+		nobody = context.Background()
+
+		aliceToDo = create(alice, &pb.ToDo{Title: "alice"})
+		bobToDo = create(bob, &pb.ToDo{Title: "bob"})
+		noneID = create(nobody, &pb.ToDo{Title: "nobody"})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Create", func() {
+		It("should stamp the todo with the caller", func() {
+			response, err := server.Read(admin, &pb.ReadRequest{Id: aliceToDo})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetToDo().GetOwner()).Should(Equal("alice"))
+		})
+
+		It("should keep the owner of the request without authentication", func() {
+			id := create(nobody, &pb.ToDo{Title: "given", Owner: "carol"})
+			response, err := server.Read(nobody, &pb.ReadRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetToDo().GetOwner()).Should(Equal("carol"))
+		})
+
+		It("should let an admin create a todo for another owner", func() {
+			id := create(admin, &pb.ToDo{Title: "for alice", Owner: "alice"})
+			response, err := server.Read(alice, &pb.ReadRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetToDo().GetOwner()).Should(Equal("alice"))
+		})
+
+		It("should deny the creation for another owner", func() {
+			_, err := server.Create(alice, &pb.CreateRequest{ToDo: &pb.ToDo{Title: "for bob", Owner: "bob"}})
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+	})
+
+	Describe("Read", func() {
+		It("should return the todo of the caller", func() {
+			response, err := server.Read(alice, &pb.ReadRequest{Id: aliceToDo})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetToDo().GetTitle()).Should(Equal("alice"))
+		})
+
+		It("should hide the todo of another owner", func() {
+			_, err := server.Read(alice, &pb.ReadRequest{Id: bobToDo})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+			_, err = server.Read(alice, &pb.ReadRequest{Id: noneID})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+
+		It("should return any todo to an admin", func() {
+			_, err := server.Read(admin, &pb.ReadRequest{Id: bobToDo})
+			Ω(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Update", func() {
+		update := func(ctx context.Context, id string) error {
+			_, err := server.Update(ctx, &pb.UpdateRequest{
+				ToDo:       &pb.ToDo{Id: id, Title: "changed"},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"title"}},
+			})
+			return err
+		}
+
+		It("should deny the update of the todo of another owner", func() {
+			Ω(status.Code(update(alice, bobToDo))).Should(Equal(codes.NotFound))
+			response, err := server.Read(bob, &pb.ReadRequest{Id: bobToDo})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetToDo().GetTitle()).Should(Equal("bob"))
+		})
+
+		It("should update the todo of the caller and keep the owner", func() {
+			Ω(update(bob, bobToDo)).Should(Succeed())
+			response, err := server.Read(bob, &pb.ReadRequest{Id: bobToDo})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetToDo().GetTitle()).Should(Equal("changed"))
+			Ω(response.GetToDo().GetOwner()).Should(Equal("bob"))
+		})
+
+		It("should let an admin update any todo", func() {
+			Ω(update(admin, bobToDo)).Should(Succeed())
+		})
+	})
+
+	Describe("Delete", func() {
+		It("should deny the deletion of the todo of another owner", func() {
+			_, err := server.Delete(alice, &pb.DeleteRequest{Id: bobToDo})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+			_, err = server.Read(bob, &pb.ReadRequest{Id: bobToDo})
+			Ω(err).NotTo(HaveOccurred())
+		})
+
+		It("should delete the todo of the caller", func() {
+			_, err := server.Delete(alice, &pb.DeleteRequest{Id: aliceToDo})
+			Ω(err).NotTo(HaveOccurred())
+		})
+
+		It("should let an admin delete any todo", func() {
+			_, err := server.Delete(admin, &pb.DeleteRequest{Id: aliceToDo})
+			Ω(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Search", func() {
+		It("should return only the todos of the caller", func() {
+			Ω(owners(alice, &pb.SearchRequest{})).Should(Equal([]string{"alice"}))
+			Ω(owners(alice, &pb.SearchRequest{Owner: "alice"})).Should(Equal([]string{"alice"}))
+		})
+
+		It("should deny the search of the todos of another owner", func() {
+			_, err := server.Search(alice, &pb.SearchRequest{Owner: "bob"})
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+
+		It("should return all the todos to an admin or filter them", func() {
+			Ω(owners(admin, &pb.SearchRequest{})).Should(Equal([]string{"alice", "bob", ""}))
+			Ω(owners(admin, &pb.SearchRequest{Owner: "bob"})).Should(Equal([]string{"bob"}))
+		})
+
+		It("should stream only the todos of the caller", func() {
+			stream := &mockSearchStream{ctx: bob}
+			Ω(server.SearchStream(&pb.SearchRequest{}, stream)).Should(Succeed())
+			Ω(stream.todos).Should(HaveLen(1))
+			Ω(stream.todos[0].GetOwner()).Should(Equal("bob"))
+		})
+	})
+
+	Describe("Watch", func() {
+		It("should send only the changes on the todos of the caller", func() {
+			ctx, cancel := context.WithCancel(alice)
+			defer cancel()
+			stream := &mockWatchStream{ctx: ctx, events: make(chan *pb.Event, 10)}
+			go func(server *ToDoServiceServer, stream *mockWatchStream) {
+				server.Watch(&pb.WatchRequest{}, stream)
+			}(server, stream)
+			Eventually(server.Events().Subscribed).Should(BeTrue())
+
+			create(bob, &pb.ToDo{Title: "bob again"})
+			id := create(alice, &pb.ToDo{Title: "alice again"})
+
+			e := <-stream.events
+			Ω(e.GetToDo().GetId()).Should(Equal(id))
+		})
+
+		It("should deny the watch of the todos of another owner", func() {
+			stream := &mockWatchStream{ctx: alice, events: make(chan *pb.Event, 10)}
+			err := server.Watch(&pb.WatchRequest{Owner: "bob"}, stream)
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+	})
+})
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// The size of the pages
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+var orders = map[string]bool{
+	store.OrderCreated:   true,
+	store.OrderUpdated:   true,
+	store.OrderCompleted: true,
+	store.OrderDeleted:   true,
+	store.OrderReminder:  true,
+	store.OrderTitle:     true,
+	store.OrderState:     true,
+}
+
+// pageToken the content of the opaque token, the fingerprint binds it to the search.
+// The pages are offsets, they are unstable under writes: a todo created, deleted or moved in the order
+// before the offset shifts the next pages, so a todo is skipped or returned twice.
+type pageToken struct {
+	Offset      int64  `json:"o"`
+	Fingerprint uint64 `json:"f"`
+}
+
+// fingerprint hashes the filters and the order of the request
+func fingerprint(r *pb.SearchRequest) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%q|%v|%q|%v|%q|%q|%q|%v", r.GetPattern(), r.GetMatchMode(), r.GetTags(), r.GetStates(), r.GetOrderBy(), r.GetOwner(), r.GetListId(), r.GetTrashed())
+	for _, timeRange := range []*pb.TimeRange{r.GetCreatedAt(), r.GetUpdatedAt(), r.GetCompletedAt(), r.GetDeletedAt()} {
+		fmt.Fprintf(h, "|%s", proto.CompactTextString(timeRange))
+	}
+	return h.Sum64()
+}
+
+func encodePageToken(r *pb.SearchRequest, offset int64) string {
+	value, _ := json.Marshal(pageToken{Offset: offset, Fingerprint: fingerprint(r)})
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func decodePageToken(r *pb.SearchRequest) (int64, error) {
+	if r.GetPageToken() == "" {
+		return 0, nil
+	}
+	value, err := base64.RawURLEncoding.DecodeString(r.GetPageToken())
+	if err != nil {
+		return 0, invalidArgument("pageToken", "malformed token")
+	}
+	var token pageToken
+	if err := json.Unmarshal(value, &token); err != nil || token.Offset < 0 {
+		return 0, invalidArgument("pageToken", "malformed token")
+	}
+	if token.Fingerprint != fingerprint(r) {
+		return 0, invalidArgument("pageToken", "the token belongs to another search")
+	}
+	return token.Offset, nil
+}
+
+// newQuery converts the request in a query of the store
+func newQuery(r *pb.SearchRequest) (store.Query, error) {
+	q := store.Query{
+		Request: r,
+		OrderBy: store.OrderCreated,
+		Limit:   DefaultPageSize,
+	}
+
+	if orderBy := strings.Fields(r.GetOrderBy()); len(orderBy) > 0 {
+		if !orders[orderBy[0]] || len(orderBy) > 2 || (len(orderBy) == 2 && orderBy[1] != "desc" && orderBy[1] != "asc") {
+			return q, invalidArgument("orderBy", fmt.Sprintf("unknown order %q", r.GetOrderBy()))
+		}
+		q.OrderBy = orderBy[0]
+		q.Descending = len(orderBy) == 2 && orderBy[1] == "desc"
+	}
+
+	switch size := r.GetPageSize(); {
+	case size < 0:
+		return q, invalidArgument("pageSize", "must not be negative")
+	case size > MaxPageSize:
+		q.Limit = MaxPageSize
+	case size > 0:
+		q.Limit = int64(size)
+	}
+
+	var err error
+	q.Offset, err = decodePageToken(r)
+	return q, err
+}
@@ -6,111 +6,49 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/golang/protobuf/ptypes/timestamp"
-	log "github.com/sirupsen/logrus"
+	"github.com/golang/protobuf/proto"
+	"github.com/sjeandeaux/todo/pkg/event"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/validator"
 	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-const (
-	database   = "challenge"
-	collection = "todo"
 )
 
-func getMongoClient(url string) (*mongo.Client, error) {
-	return mongo.Connect(context.Background(), options.Client().ApplyURI(url))
-}
-
 // ToDoServiceServer manages the todos list
 type ToDoServiceServer struct {
-	client         *mongo.Client
-	todoCollection *mongo.Collection
-	ctx            context.Context
-}
-
-const (
-	keyID          = "_id"
-	keyTitle       = "title"
-	keyDescription = "description"
-	keyTags        = "tags"
-	keyState       = "state"
-	keyReminder    = "reminder"
-)
-
-//Use in create
-type todoInMongo struct {
-	Title       string
-	Description string
-	Tags        []string
-	Reminder    int64
-	State       string
-}
-
-//Use in read
-type todoInMongoWithID struct {
-	ID   primitive.ObjectID `bson:"_id"`
-	Todo todoInMongo        `bson:"inline"`
-}
-
-func newTodo(r *pb.ToDo) (t todoInMongo) {
-	t.Title = r.GetTitle()
-	t.Description = r.GetDescription()
-	t.Tags = r.GetTags()
-	if reminder := r.GetReminder(); reminder != nil {
-		t.Reminder = reminder.GetSeconds()
-	}
-	t.State = r.GetState().String()
-	return
-}
-
-func (t *todoInMongoWithID) todo() *pb.ToDo {
-	return &pb.ToDo{
-		Id:          t.ID.Hex(),
-		Title:       t.Todo.Title,
-		Description: t.Todo.Description,
-		Tags:        t.Todo.Tags,
-		State:       pb.ToDo_State(pb.ToDo_State_value[t.Todo.State]),
-		Reminder:    &timestamp.Timestamp{Seconds: t.Todo.Reminder},
-	}
+	store  store.Store
+	events *event.Bus
+	hooks  []Hook
 }
 
 // validate the implementation
 var _ pb.ToDoServiceServer = &ToDoServiceServer{}
 
-// NewToDoServiceServer TODO
-func NewToDoServiceServer(ctx context.Context, url string) (*ToDoServiceServer, error) {
-	client, err := getMongoClient(url)
-	if err != nil {
-		return nil, err
-	}
-	database := client.Database(database)
+// NewToDoServiceServer creates the service on top of the store
+func NewToDoServiceServer(s store.Store) *ToDoServiceServer {
 	return &ToDoServiceServer{
-		client:         client,
-		todoCollection: database.Collection(collection),
-	}, nil
+		store:  s,
+		events: event.NewBus(event.DefaultBuffer),
+	}
 }
 
-// Close TODO
+// Close ends the watches and closes the store
 func (s *ToDoServiceServer) Close() {
-	if s.client != nil {
-		s.client.Disconnect(s.ctx)
+	s.events.Close()
+	if s.store != nil {
+		s.store.Close()
 	}
 }
 
 // HealthChecher the checker
 func (s *ToDoServiceServer) HealthChecher() func() error {
 	return func() error {
-		if s.client == nil {
-			return errors.New("mongo client is nil")
+		if s.store == nil {
+			return errors.New("store is nil")
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
 
-		if err := s.client.Ping(ctx, nil); err != nil {
+		if err := s.store.Ping(ctx); err != nil {
 			return fmt.Errorf("ping failure %v", err)
 		}
 		return nil
@@ -119,139 +57,190 @@ func (s *ToDoServiceServer) HealthChecher() func() error {
 
 // Create a todo
 func (s *ToDoServiceServer) Create(ctx context.Context, r *pb.CreateRequest) (*pb.CreateResponse, error) {
-	todo := newTodo(r.ToDo)
-	result, err := s.todoCollection.InsertOne(ctx, todo)
+	todo, err := s.checkCreate(ctx, r, "")
 	if err != nil {
 		return nil, err
 	}
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		return &pb.CreateResponse{
-			Id: oid.Hex(),
-		}, nil
+	id, err := s.store.Create(ctx, todo)
+	if err != nil {
+		return nil, toStatus(err, "toDo.id")
+	}
+	s.changed(ctx, pb.Event_CREATED, id, nil)
+	return &pb.CreateResponse{
+		Id: id,
+	}, nil
+}
+
+// checkCreate validates the creation and returns the todo to store, the fields of the errors are prefixed by prefix
+func (s *ToDoServiceServer) checkCreate(ctx context.Context, r *pb.CreateRequest, prefix string) (*pb.ToDo, error) {
+	if r.GetToDo() == nil {
+		return nil, invalidArgument(prefix+"toDo", "the todo is required")
+	}
+	if err := validator.ToDo(r.GetToDo(), nil); err != nil {
+		return nil, invalidMessage(prefix+"toDo.", err)
+	}
+	todo := proto.Clone(r.GetToDo()).(*pb.ToDo)
+	var err error
+	if todo.Owner, err = creator(ctx, todo.GetOwner()); err != nil {
+		return nil, err
+	}
+	if err := s.checkTarget(ctx, todo.GetListId(), prefix+"toDo.listId"); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("unexpected error")
+	return todo, nil
 }
 
 // Read a todo
 func (s *ToDoServiceServer) Read(ctx context.Context, r *pb.ReadRequest) (*pb.ReadResponse, error) {
-	id, err := primitive.ObjectIDFromHex(r.GetId())
+	todo, err := s.store.Read(ctx, r.GetId())
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err, "id")
 	}
-	result := s.todoCollection.FindOne(ctx, bson.M{keyID: id})
-
-	objMongo := &todoInMongoWithID{}
-
-	if err := result.Decode(objMongo); err != nil {
-		if err == mongo.ErrNoDocuments {
-			return &pb.ReadResponse{}, nil
-		}
+	if err := s.access(ctx, todo, "id", false); err != nil {
 		return nil, err
 	}
 	return &pb.ReadResponse{
-		ToDo: objMongo.todo(),
+		ToDo: todo,
 	}, nil
 }
 
-// Update a todo
+// Update a todo, only the fields in the mask are updated and only if it has the expected version
 func (s *ToDoServiceServer) Update(ctx context.Context, r *pb.UpdateRequest) (*pb.UpdateResponse, error) {
-
-	id, err := primitive.ObjectIDFromHex(r.ToDo.GetId())
+	paths, err := s.checkUpdate(ctx, r, "")
 	if err != nil {
 		return nil, err
 	}
-	filter := bson.M{keyID: bson.M{"$eq": id}}
-	update := bson.D{
-		primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: keyTags, Value: r.GetToDo().GetTags()}}},
-		primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: keyState, Value: r.GetToDo().GetState().String()}}},
+	previous := s.previous(ctx, r.GetToDo().GetId())
+	updated, err := s.store.Update(ctx, r.GetToDo(), paths, r.GetExpectedVersion())
+	if err != nil {
+		return nil, toStatus(err, "toDo.id")
 	}
-
-	if title := r.GetToDo().GetTitle(); title != "" {
-		update = append(update, primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: keyTitle, Value: r.GetToDo().GetTitle()}}})
+	if updated > 0 {
+		s.changed(ctx, pb.Event_UPDATED, r.GetToDo().GetId(), previous)
 	}
+	return &pb.UpdateResponse{Updated: updated}, nil
+}
 
-	if description := r.GetToDo().GetDescription(); description != "" {
-		update = append(update, primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: keyDescription, Value: description}}})
+// checkUpdate validates the update, checks the caller can make it and returns the fields to update,
+// the fields of the errors are prefixed by prefix
+func (s *ToDoServiceServer) checkUpdate(ctx context.Context, r *pb.UpdateRequest, prefix string) ([]string, error) {
+	if r.GetToDo() == nil {
+		return nil, invalidArgument(prefix+"toDo", "the todo is required")
+	}
+	paths := updatedFields(r.GetToDo())
+	if mask := r.GetUpdateMask(); mask != nil && len(mask.GetPaths()) > 0 {
+		paths = mask.GetPaths()
+		for _, path := range paths {
+			if !updatableFields[path] {
+				return nil, invalidArgument(prefix+"updateMask", fmt.Sprintf("unknown field %q", path))
+			}
+		}
 	}
 
-	if reminder := r.GetToDo().GetReminder(); reminder != nil {
-		update = append(update, primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: keyReminder, Value: reminder.GetSeconds()}}})
+	if err := validator.ToDo(r.GetToDo(), paths); err != nil {
+		return nil, invalidMessage(prefix+"toDo.", err)
 	}
 
-	result, err := s.todoCollection.UpdateOne(
-		ctx,
-		filter,
-		update,
-	)
-
-	if err != nil {
+	if err := s.authorize(ctx, r.GetToDo().GetId(), prefix+"toDo.id"); err != nil {
 		return nil, err
 	}
-	return &pb.UpdateResponse{Updated: result.ModifiedCount}, nil
+	for _, path := range paths {
+		if path == store.FieldListID {
+			if err := s.checkTarget(ctx, r.GetToDo().GetListId(), prefix+"toDo.listId"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return paths, nil
+}
 
+// updatableFields the fields allowed in the update mask
+var updatableFields = map[string]bool{
+	store.FieldTitle:       true,
+	store.FieldDescription: true,
+	store.FieldTags:        true,
+	store.FieldState:       true,
+	store.FieldReminder:    true,
+	store.FieldListID:      true,
 }
 
-// Delete a todo
+// updatedFields without mask the tags and the state are always updated, the other fields only when they are set,
+// the todo leaves its list only with the mask.
+func updatedFields(todo *pb.ToDo) []string {
+	paths := []string{store.FieldTags, store.FieldState}
+	if todo.GetTitle() != "" {
+		paths = append(paths, store.FieldTitle)
+	}
+	if todo.GetDescription() != "" {
+		paths = append(paths, store.FieldDescription)
+	}
+	if todo.GetReminder() != nil {
+		paths = append(paths, store.FieldReminder)
+	}
+	if todo.GetListId() != "" {
+		paths = append(paths, store.FieldListID)
+	}
+	return paths
+}
+
+// Delete a todo if it has the expected version
 func (s *ToDoServiceServer) Delete(ctx context.Context, r *pb.DeleteRequest) (*pb.DeleteResponse, error) {
-	id, err := primitive.ObjectIDFromHex(r.GetId())
-	if err != nil {
+	if err := s.authorize(ctx, r.GetId(), "id"); err != nil {
 		return nil, err
 	}
-	result, err := s.todoCollection.DeleteOne(ctx, bson.M{"_id": id})
+	previous := s.previous(ctx, r.GetId())
+	deleted, err := s.store.Delete(ctx, r.GetId(), r.GetExpectedVersion())
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err, "id")
 	}
+	s.changed(ctx, pb.Event_DELETED, r.GetId(), previous)
 	return &pb.DeleteResponse{
-		Deleted: result.DeletedCount,
+		Deleted: deleted,
 	}, nil
 }
 
-// Search todos
+// Search todos page by page
 func (s *ToDoServiceServer) Search(ctx context.Context, r *pb.SearchRequest) (*pb.SearchResponse, error) {
-
-	filter := bson.D{}
-
-	if pattern := r.GetPattern(); pattern != "" {
-		filter = append(filter, bson.E{Key: keyDescription, Value: primitive.Regex{Pattern: pattern}})
-	}
-
-	if states := r.GetStates(); len(states) > 0 {
-		filter = append(filter, bson.E{Key: keyState, Value: bson.D{{Key: "$in", Value: toString(states)}}})
+	if err := validator.SearchRequest(r); err != nil {
+		return nil, invalidMessage("", err)
 	}
-
-	if tags := r.GetTags(); len(tags) > 0 {
-		filter = append(filter, bson.E{Key: keyTags, Value: bson.D{{Key: "$all", Value: tags}}})
+	r = proto.Clone(r).(*pb.SearchRequest)
+	var err error
+	if r.Owner, err = s.scope(ctx, r.GetOwner(), r.GetListId()); err != nil {
+		return nil, err
 	}
-	log.Info(filter)
-	cur, err := s.todoCollection.Find(ctx, filter)
-
+	q, err := newQuery(r)
 	if err != nil {
 		return nil, err
 	}
-
-	defer cur.Close(ctx)
-	elements := []*pb.ToDo{}
-	for cur.Next(ctx) {
-		var result todoInMongoWithID
-		err := cur.Decode(&result)
-		if err != nil {
-			return nil, err
-		}
-		log.Debug(result)
-		elements = append(elements, result.todo())
-	}
-	if err := cur.Err(); err != nil {
-		return nil, err
+	elements, total, err := s.store.Search(ctx, q)
+	if err != nil {
+		return nil, toStatus(err, "")
 	}
 
-	return &pb.SearchResponse{ToDos: elements}, nil
-
+	response := &pb.SearchResponse{ToDos: elements, TotalSize: total}
+	if next := q.Offset + int64(len(elements)); next < total {
+		response.NextPageToken = encodePageToken(r, next)
+	}
+	return response, nil
 }
 
-func toString(values []pb.ToDo_State) []string {
-	result := make([]string, len(values))
-	for i, value := range values {
-		result[i] = value.String()
+// SearchStream sends the todos one by one, the page of the request is ignored
+func (s *ToDoServiceServer) SearchStream(r *pb.SearchRequest, stream pb.ToDoService_SearchStreamServer) error {
+	if err := validator.SearchRequest(r); err != nil {
+		return invalidMessage("", err)
 	}
-	return result
+	r = proto.Clone(r).(*pb.SearchRequest)
+	r.PageSize, r.PageToken = 0, ""
+	var err error
+	if r.Owner, err = s.scope(stream.Context(), r.GetOwner(), r.GetListId()); err != nil {
+		return err
+	}
+	q, err := newQuery(r)
+	if err != nil {
+		return err
+	}
+	q.Limit = 0
+
+	return toStatus(s.store.Iterate(stream.Context(), q, stream.Send), "")
 }
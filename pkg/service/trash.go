@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// PurgeInterval the maximum duration between two purges of the trash
+const PurgeInterval = time.Hour
+
+// trashed reads the todo id in the trash, NotFound if the caller can't see it and PermissionDenied if it can't delete it
+func (s *ToDoServiceServer) trashed(ctx context.Context, id string) (*pb.ToDo, error) {
+	todo, err := s.store.ReadTrashed(ctx, id)
+	if err != nil {
+		return nil, toStatus(err, "id")
+	}
+	if err := s.access(ctx, todo, "id", true); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// Restore takes a todo out of the trash
+func (s *ToDoServiceServer) Restore(ctx context.Context, r *pb.RestoreRequest) (*pb.RestoreResponse, error) {
+	previous, err := s.trashed(ctx, r.GetId())
+	if err != nil {
+		return nil, err
+	}
+	restored, err := s.store.Restore(ctx, r.GetId())
+	if err != nil {
+		return nil, toStatus(err, "id")
+	}
+	s.changed(ctx, pb.Event_RESTORED, r.GetId(), previous)
+	return &pb.RestoreResponse{Restored: restored}, nil
+}
+
+// Purge removes a todo from the trash for good, its history is kept
+func (s *ToDoServiceServer) Purge(ctx context.Context, r *pb.PurgeRequest) (*pb.PurgeResponse, error) {
+	previous, err := s.trashed(ctx, r.GetId())
+	if err != nil {
+		return nil, err
+	}
+	purged, err := s.store.Purge(ctx, r.GetId(), 0)
+	if err != nil {
+		return nil, toStatus(err, "id")
+	}
+	s.changed(ctx, pb.Event_PURGED, r.GetId(), previous)
+	return &pb.PurgeResponse{Purged: purged}, nil
+}
+
+// Purger purges the todos which have been in the trash for longer than the retention
+type Purger struct {
+	// Tenants the tenants of the store, single tenant if empty
+	Tenants []string
+	// Interval the duration between two purges
+	Interval time.Duration
+
+	service   *ToDoServiceServer
+	retention time.Duration
+}
+
+// NewPurger creates a purger of the trash of the service, the retention must be positive.
+// It purges every PurgeInterval or every retention if it is shorter.
+func NewPurger(s *ToDoServiceServer, retention time.Duration) *Purger {
+	interval := PurgeInterval
+	if retention < interval {
+		interval = retention
+	}
+	return &Purger{
+		Interval:  interval,
+		service:   s,
+		retention: retention,
+	}
+}
+
+// Run purges the trash on start then every interval until ctx is done
+func (p *Purger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		p.Purge(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes the todos moved to the trash before the retention from the trash of all the tenants,
+// it returns the number of purged todos. The failures are logged, the next purge retries them.
+func (p *Purger) Purge(ctx context.Context) int64 {
+	tenants := p.Tenants
+	if len(tenants) == 0 {
+		tenants = []string{""}
+	}
+	var purged int64
+	for _, t := range tenants {
+		purged += p.purge(tenant.NewContext(ctx, t))
+	}
+	return purged
+}
+
+// purge removes the expired todos from the trash of the tenant of ctx
+func (p *Purger) purge(ctx context.Context) int64 {
+	logger := log.WithField("tenant", tenant.FromContext(ctx))
+	before, _ := ptypes.TimestampProto(time.Now().Add(-p.retention))
+	q := store.Query{Request: &pb.SearchRequest{Trashed: true, DeletedAt: &pb.TimeRange{Before: before}}}
+	// the todos are read before being purged so the store isn't changed while it is iterated
+	expired := []*pb.ToDo{}
+	err := p.service.store.Iterate(ctx, q, func(todo *pb.ToDo) error {
+		expired = append(expired, todo)
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("the trash is not purged")
+		return 0
+	}
+
+	var purged int64
+	for _, todo := range expired {
+		// the version read tells if the todo has been restored and deleted again since
+		n, err := p.service.store.Purge(ctx, todo.GetId(), todo.GetVersion())
+		if errors.Is(err, store.ErrNotFound) || errors.Is(err, store.ErrVersionMismatch) {
+			// restored, purged or deleted again since
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).WithField("id", todo.GetId()).Error("the todo is not purged")
+			continue
+		}
+		p.service.changed(ctx, pb.Event_PURGED, todo.GetId(), todo)
+		purged += n
+	}
+	if purged > 0 {
+		logger.WithField("purged", purged).Info("the trash is purged")
+	}
+	return purged
+}
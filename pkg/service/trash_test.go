@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/sjeandeaux/todo/pkg/auth"
+	. "github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trash", func() {
+
+	var (
+		server     *ToDoServiceServer
+		alice, bob context.Context
+		id         string
+	)
+
+	trash := func(ctx context.Context) []string {
+		response, err := server.Search(ctx, &pb.SearchRequest{Trashed: true})
+		Ω(err).NotTo(HaveOccurred())
+		result := []string{}
+		for _, todo := range response.GetToDos() {
+			result = append(result, todo.GetId())
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		server = NewToDoServiceServer(store.NewMemory())
+		alice = auth.NewContext(context.Background(), &auth.Identity{Subject: "alice"})
+		bob = auth.NewContext(context.Background(), &auth.Identity{Subject: "bob"})
+
+		response, err := server.Create(alice, &pb.CreateRequest{ToDo: &pb.ToDo{Title: "a"}})
+		Ω(err).NotTo(HaveOccurred())
+		id = response.GetId()
+		_, err = server.Delete(alice, &pb.DeleteRequest{Id: id})
+		Ω(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Delete", func() {
+		It("should move the todo to the trash of its owner", func() {
+			_, err := server.Read(alice, &pb.ReadRequest{Id: id})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+			Ω(trash(alice)).Should(Equal([]string{id}))
+			Ω(trash(bob)).Should(BeEmpty())
+		})
+	})
+
+	Describe("Restore", func() {
+		It("should take the todo out of the trash", func() {
+			response, err := server.Restore(alice, &pb.RestoreRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetRestored()).Should(Equal(int64(1)))
+
+			read, err := server.Read(alice, &pb.ReadRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(read.GetToDo().GetTitle()).Should(Equal("a"))
+			Ω(trash(alice)).Should(BeEmpty())
+		})
+
+		It("should hide the trash of the others", func() {
+			_, err := server.Restore(bob, &pb.RestoreRequest{Id: id})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+
+		It("should return NotFound on a todo out of the trash", func() {
+			_, err := server.Restore(alice, &pb.RestoreRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			_, err = server.Restore(alice, &pb.RestoreRequest{Id: id})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("Purge", func() {
+		It("should remove the todo for good", func() {
+			_, err := server.Purge(bob, &pb.PurgeRequest{Id: id})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+
+			response, err := server.Purge(alice, &pb.PurgeRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(response.GetPurged()).Should(Equal(int64(1)))
+			Ω(trash(alice)).Should(BeEmpty())
+			_, err = server.Restore(alice, &pb.RestoreRequest{Id: id})
+			Ω(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("Purger", func() {
+		It("should purge only the todos in the trash for longer than the retention", func() {
+			Ω(NewPurger(server, time.Hour).Purge(context.Background())).Should(Equal(int64(0)))
+			Ω(trash(alice)).Should(Equal([]string{id}))
+
+			Ω(NewPurger(server, time.Nanosecond).Purge(context.Background())).Should(Equal(int64(1)))
+			Ω(trash(alice)).Should(BeEmpty())
+		})
+
+		It("should not purge the todos deleted again since they were read", func() {
+			memory := store.NewMemory()
+			server.Close()
+			server = NewToDoServiceServer(&iterateStore{Store: memory, iterated: func() {
+				Ω(memory.Restore(context.Background(), id)).Should(Equal(int64(1)))
+				Ω(memory.Delete(context.Background(), id, 0)).Should(Equal(int64(1)))
+			}})
+			created, err := memory.Create(context.Background(), &pb.ToDo{Title: "a", Owner: "alice"})
+			Ω(err).NotTo(HaveOccurred())
+			id = created
+			Ω(memory.Delete(context.Background(), id, 0)).Should(Equal(int64(1)))
+
+			Ω(NewPurger(server, time.Nanosecond).Purge(context.Background())).Should(Equal(int64(0)))
+			Ω(trash(alice)).Should(Equal([]string{id}))
+		})
+
+		It("should purge the trash of each tenant", func() {
+			server.Close()
+			server = NewToDoServiceServer(store.NewRouter(func(string) (store.Store, error) { return store.NewMemory(), nil }, store.Quota{}, nil))
+			acme, globex := tenant.NewContext(alice, "acme"), tenant.NewContext(alice, "globex")
+			for _, ctx := range []context.Context{acme, globex} {
+				response, err := server.Create(ctx, &pb.CreateRequest{ToDo: &pb.ToDo{Title: "b"}})
+				Ω(err).NotTo(HaveOccurred())
+				_, err = server.Delete(ctx, &pb.DeleteRequest{Id: response.GetId()})
+				Ω(err).NotTo(HaveOccurred())
+			}
+
+			purger := NewPurger(server, time.Nanosecond)
+			purger.Tenants = []string{"acme"}
+			Ω(purger.Purge(context.Background())).Should(Equal(int64(1)))
+			Ω(trash(acme)).Should(BeEmpty())
+			Ω(trash(globex)).Should(HaveLen(1))
+		})
+
+		It("should purge on start then every interval until ctx is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- NewPurger(server, time.Nanosecond).Run(ctx) }()
+
+			Eventually(func() []string { return trash(alice) }).Should(BeEmpty())
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+})
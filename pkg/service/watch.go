@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/sjeandeaux/todo/pkg/event"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	"github.com/sjeandeaux/todo/pkg/validator"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Hook receives the changes made through the service when the handlers make them, it must not block
+type Hook interface {
+	Changed(e *pb.Event)
+}
+
+// AddHook adds a hook of the changes, it is called before the service serves
+func (s *ToDoServiceServer) AddHook(h Hook) {
+	s.hooks = append(s.hooks, h)
+}
+
+// Events the bus of the changes made through the service
+func (s *ToDoServiceServer) Events() *event.Bus {
+	return s.events
+}
+
+// previous reads the todo before a change for its history and its event
+func (s *ToDoServiceServer) previous(ctx context.Context, id string) *pb.ToDo {
+	todo, err := s.store.Read(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return todo
+}
+
+// publish gives the change on the todo id to the hooks and the bus, previous is the todo before the change and current after it
+func (s *ToDoServiceServer) publish(ctx context.Context, t pb.Event_Type, id string, previous *pb.ToDo, current *pb.ToDo) {
+	if len(s.hooks) == 0 && !s.events.Subscribed() {
+		return
+	}
+	e := &pb.Event{Type: t, Tenant: tenant.FromContext(ctx)}
+	switch t {
+	case pb.Event_DELETED, pb.Event_PURGED:
+		e.ToDo = previous
+		if e.ToDo == nil {
+			e.ToDo = &pb.ToDo{Id: id}
+		}
+	default:
+		e.ToDo = current
+		if t == pb.Event_UPDATED {
+			e.Previous = previous
+		}
+	}
+	for _, h := range s.hooks {
+		h.Changed(e)
+	}
+	if s.events.Subscribed() {
+		s.events.Publish(e)
+	}
+}
+
+// Watch sends the changes on the todos of the tenant matching the filters until the client leaves
+func (s *ToDoServiceServer) Watch(r *pb.WatchRequest, stream pb.ToDoService_WatchServer) error {
+	filters := &pb.SearchRequest{Pattern: r.GetPattern(), MatchMode: r.GetMatchMode(), Tags: r.GetTags(), States: r.GetStates(), ListId: r.GetListId()}
+	if err := validator.SearchRequest(filters); err != nil {
+		return invalidMessage("", err)
+	}
+	var err error
+	if filters.Owner, err = s.scope(stream.Context(), r.GetOwner(), r.GetListId()); err != nil {
+		return err
+	}
+	match, err := store.NewMatcher(filters)
+	if err != nil {
+		return toStatus(err, "")
+	}
+
+	subscription := s.events.Subscribe()
+	defer subscription.Close()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return toStatus(stream.Context().Err(), "")
+		case e, ok := <-subscription.Events():
+			if !ok {
+				return subscriptionStatus(subscription.Err())
+			}
+			if e.GetTenant() != tenant.FromContext(stream.Context()) {
+				continue
+			}
+			if !matchEvent(match, e) {
+				continue
+			}
+			if err := stream.Send(proto.Clone(e).(*pb.Event)); err != nil {
+				return toStatus(err, "")
+			}
+		}
+	}
+}
+
+// matchEvent tells if the todo of the event matches before or after the change,
+// the todo of DELETED and PURGED is matched as it was before it went to the trash
+func matchEvent(match func(*pb.ToDo) bool, e *pb.Event) bool {
+	todo := e.GetToDo()
+	if t := e.GetType(); (t == pb.Event_DELETED || t == pb.Event_PURGED) && todo.GetDeletedAt() != nil {
+		todo = proto.Clone(todo).(*pb.ToDo)
+		todo.DeletedAt = nil
+	}
+	return match(todo) || (e.GetPrevious() != nil && match(e.GetPrevious()))
+}
+
+func subscriptionStatus(err error) error {
+	switch {
+	case errors.Is(err, event.ErrOverflow):
+		return status.Error(codes.ResourceExhausted, "the watch is too slow to read the events")
+	case errors.Is(err, event.ErrClosed):
+		return status.Error(codes.Unavailable, "the service is shutting down")
+	}
+	return toStatus(err, "")
+}
@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+
+	. "github.com/sjeandeaux/todo/pkg/service"
+	"github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// mockWatchStream forwards the events sent by Watch
+type mockWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *pb.Event
+}
+
+func (s *mockWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockWatchStream) Send(e *pb.Event) error {
+	s.events <- e
+	return nil
+}
+
+var _ = Describe("Watch", func() {
+
+	var (
+		server *ToDoServiceServer
+		stream *mockWatchStream
+		cancel context.CancelFunc
+		done   chan error
+	)
+
+	BeforeEach(func() {
+		server = NewToDoServiceServer(store.NewMemory())
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		stream = &mockWatchStream{ctx: ctx, events: make(chan *pb.Event, 10)}
+		done = make(chan error, 1)
+	})
+
+	AfterEach(func() {
+		cancel()
+		server.Close()
+	})
+
+	// watch starts the watch and waits for its subscription
+	watch := func(r *pb.WatchRequest) {
+		go func(server *ToDoServiceServer, stream *mockWatchStream, done chan error) {
+			done <- server.Watch(r, stream)
+		}(server, stream, done)
+		Eventually(server.Events().Subscribed).Should(BeTrue())
+	}
+
+	create := func(todo *pb.ToDo) string {
+		response, err := server.Create(context.TODO(), &pb.CreateRequest{ToDo: todo})
+		Ω(err).NotTo(HaveOccurred())
+		return response.GetId()
+	}
+
+	Context("With changes on the todos", func() {
+		It("should send the created, updated and deleted todos", func() {
+			watch(&pb.WatchRequest{})
+
+			id := create(&pb.ToDo{Title: "a"})
+			e := <-stream.events
+			Ω(e.GetType()).Should(Equal(pb.Event_CREATED))
+			Ω(e.GetToDo().GetId()).Should(Equal(id))
+
+			_, err := server.Update(context.TODO(), &pb.UpdateRequest{ToDo: &pb.ToDo{Id: id, Title: "b"}, UpdateMask: &field_mask.FieldMask{Paths: []string{"title"}}})
+			Ω(err).NotTo(HaveOccurred())
+			e = <-stream.events
+			Ω(e.GetType()).Should(Equal(pb.Event_UPDATED))
+			Ω(e.GetToDo().GetTitle()).Should(Equal("b"))
+			Ω(e.GetPrevious().GetTitle()).Should(Equal("a"))
+
+			_, err = server.Delete(context.TODO(), &pb.DeleteRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			e = <-stream.events
+			Ω(e.GetType()).Should(Equal(pb.Event_DELETED))
+			Ω(e.GetToDo().GetTitle()).Should(Equal("b"))
+		})
+
+		It("should send the restored and purged todos", func() {
+			watch(&pb.WatchRequest{Tags: []string{"job"}})
+
+			id := create(&pb.ToDo{Title: "a", Tags: []string{"job"}})
+			Ω((<-stream.events).GetType()).Should(Equal(pb.Event_CREATED))
+			_, err := server.Delete(context.TODO(), &pb.DeleteRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω((<-stream.events).GetType()).Should(Equal(pb.Event_DELETED))
+
+			_, err = server.Restore(context.TODO(), &pb.RestoreRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω((<-stream.events).GetType()).Should(Equal(pb.Event_RESTORED))
+
+			_, err = server.Delete(context.TODO(), &pb.DeleteRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			Ω((<-stream.events).GetType()).Should(Equal(pb.Event_DELETED))
+			_, err = server.Purge(context.TODO(), &pb.PurgeRequest{Id: id})
+			Ω(err).NotTo(HaveOccurred())
+			var e *pb.Event
+			Eventually(stream.events).Should(Receive(&e))
+			Ω(e.GetType()).Should(Equal(pb.Event_PURGED))
+			Ω(e.GetToDo().GetId()).Should(Equal(id))
+			Ω(e.GetToDo().GetDeletedAt()).ShouldNot(BeNil())
+		})
+	})
+
+	Context("With filters", func() {
+		It("should send the changes on the matching todos before or after the change", func() {
+			watch(&pb.WatchRequest{Tags: []string{"Job"}, States: []pb.ToDo_State{pb.ToDo_NOT_STARTED}})
+
+			create(&pb.ToDo{Title: "other", Tags: []string{"home"}})
+			id := create(&pb.ToDo{Title: "job", Tags: []string{"job"}})
+			e := <-stream.events
+			Ω(e.GetToDo().GetId()).Should(Equal(id))
+
+			_, err := server.Update(context.TODO(), &pb.UpdateRequest{ToDo: &pb.ToDo{Id: id, State: pb.ToDo_DONE}, UpdateMask: &field_mask.FieldMask{Paths: []string{"state"}}})
+			Ω(err).NotTo(HaveOccurred())
+			e = <-stream.events
+			Ω(e.GetType()).Should(Equal(pb.Event_UPDATED))
+			Ω(e.GetToDo().GetState()).Should(Equal(pb.ToDo_DONE))
+		})
+	})
+
+	Context("With tenants", func() {
+		It("should send the changes on the todos of the tenant", func() {
+			stream.ctx = tenant.NewContext(stream.ctx, "acme")
+			watch(&pb.WatchRequest{})
+
+			_, err := server.Create(tenant.NewContext(context.TODO(), "globex"), &pb.CreateRequest{ToDo: &pb.ToDo{Title: "globex"}})
+			Ω(err).NotTo(HaveOccurred())
+			_, err = server.Create(tenant.NewContext(context.TODO(), "acme"), &pb.CreateRequest{ToDo: &pb.ToDo{Title: "acme"}})
+			Ω(err).NotTo(HaveOccurred())
+			e := <-stream.events
+			Ω(e.GetToDo().GetTitle()).Should(Equal("acme"))
+			Ω(e.GetTenant()).Should(Equal("acme"))
+		})
+	})
+
+	Context("With a bad pattern", func() {
+		It("should fail with InvalidArgument", func() {
+			err := server.Watch(&pb.WatchRequest{Pattern: "([", MatchMode: pb.SearchRequest_REGEX}, stream)
+			Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Context("With a client which leaves", func() {
+		It("should end the watch", func() {
+			watch(&pb.WatchRequest{})
+			cancel()
+			Ω(status.Code(<-done)).Should(Equal(codes.Canceled))
+			Ω(server.Events().Subscribed()).Should(BeFalse())
+		})
+	})
+
+	Context("With a server which stops", func() {
+		It("should end the watch with Unavailable", func() {
+			watch(&pb.WatchRequest{})
+			server.Close()
+			Ω(status.Code(<-done)).Should(Equal(codes.Unavailable))
+		})
+	})
+})
+
+var _ = Describe("Hook", func() {
+	It("should receive each change when the handler makes it, with the tenant", func() {
+		server := NewToDoServiceServer(store.NewRouter(func(string) (store.Store, error) { return store.NewMemory(), nil }, store.Quota{}, nil))
+		defer server.Close()
+		hook := &recordHook{}
+		server.AddHook(hook)
+		acme := tenant.NewContext(context.Background(), "acme")
+
+		response, err := server.Create(acme, &pb.CreateRequest{ToDo: &pb.ToDo{Title: "a"}})
+		Ω(err).NotTo(HaveOccurred())
+		Ω(hook.events).Should(HaveLen(1))
+		_, err = server.Delete(acme, &pb.DeleteRequest{Id: response.GetId()})
+		Ω(err).NotTo(HaveOccurred())
+
+		Ω(hook.events).Should(HaveLen(2))
+		Ω(hook.events[0].GetType()).Should(Equal(pb.Event_CREATED))
+		Ω(hook.events[1].GetType()).Should(Equal(pb.Event_DELETED))
+		Ω(hook.events[1].GetTenant()).Should(Equal("acme"))
+		Ω(hook.events[1].GetToDo().GetTitle()).Should(Equal("a"))
+		Ω(server.Events().Subscribed()).Should(BeFalse())
+	})
+})
@@ -0,0 +1,601 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	bucketToDos   = []byte("todos")
+	bucketStates  = []byte("states")
+	bucketTags    = []byte("tags")
+	bucketLists   = []byte("lists")
+	bucketHistory = []byte("history")
+)
+
+// Bolt stores the todos in a single file, the states and the tags are indexed.
+//
+// The bucket todos contains the todos by ID, the buckets states and tags contain
+// one bucket by state or tag with the IDs of the todos. The bucket lists contains the lists by ID.
+// The bucket history contains one bucket by todo ID with its records by sequence.
+// The todos in the trash stay in the buckets with their deletedAt until they are purged.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// validate the implementation
+var _ Store = &Bolt{}
+
+// NewBolt opens or creates the file path
+func NewBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketToDos, bucketStates, bucketTags, bucketLists, bucketHistory} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Bolt{db: db}, nil
+}
+
+// BoltFile returns the file of the tenant next to path, ex: todo.acme.db for todo.db, path without tenant
+func BoltFile(path string, tenant string) string {
+	if tenant == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + tenant + ext
+}
+
+// getToDo returns the todo in the trash if trashed is set or else out of it
+func getToDo(tx *bolt.Tx, id string, trashed bool) (*pb.ToDo, error) {
+	value := tx.Bucket(bucketToDos).Get([]byte(id))
+	if value == nil {
+		return nil, ErrNotFound
+	}
+	todo := &pb.ToDo{}
+	if err := proto.Unmarshal(value, todo); err != nil {
+		return nil, err
+	}
+	if err := checkTrash(todo, trashed); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+func putToDo(tx *bolt.Tx, todo *pb.ToDo) error {
+	value, err := proto.Marshal(todo)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(bucketToDos).Put([]byte(todo.GetId()), value); err != nil {
+		return err
+	}
+	return index(tx, todo, func(b *bolt.Bucket, id []byte) error { return b.Put(id, nil) })
+}
+
+func deleteToDo(tx *bolt.Tx, todo *pb.ToDo) error {
+	if err := tx.Bucket(bucketToDos).Delete([]byte(todo.GetId())); err != nil {
+		return err
+	}
+	return index(tx, todo, func(b *bolt.Bucket, id []byte) error { return b.Delete(id) })
+}
+
+func getList(tx *bolt.Tx, id string) (*pb.List, error) {
+	value := tx.Bucket(bucketLists).Get([]byte(id))
+	if value == nil {
+		return nil, ErrListNotFound
+	}
+	list := &pb.List{}
+	if err := proto.Unmarshal(value, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func putList(tx *bolt.Tx, list *pb.List) error {
+	value, err := proto.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(bucketLists).Put([]byte(list.GetId()), value)
+}
+
+// index calls apply on the index buckets of the state and the tags of todo
+func index(tx *bolt.Tx, todo *pb.ToDo, apply func(b *bolt.Bucket, id []byte) error) error {
+	id := []byte(todo.GetId())
+	keys := map[string][]string{
+		string(bucketStates): {todo.GetState().String()},
+		string(bucketTags):   todo.GetTags(),
+	}
+	for parent, names := range keys {
+		for _, name := range names {
+			if name == "" {
+				// bolt refuses empty bucket names, the filter checks them anyway
+				continue
+			}
+			b, err := tx.Bucket([]byte(parent)).CreateBucketIfNotExists([]byte(name))
+			if err != nil {
+				return err
+			}
+			if err := apply(b, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ids returns the IDs in the index bucket parent/name
+func ids(tx *bolt.Tx, parent []byte, name string) map[string]bool {
+	result := map[string]bool{}
+	b := tx.Bucket(parent).Bucket([]byte(name))
+	if b == nil {
+		return result
+	}
+	b.ForEach(func(k, v []byte) error {
+		result[string(k)] = true
+		return nil
+	})
+	return result
+}
+
+// candidates returns the IDs which can match the request, nil means all the todos
+func candidates(tx *bolt.Tx, r *pb.SearchRequest) map[string]bool {
+	var result map[string]bool
+	intersect := func(values map[string]bool) {
+		if result == nil {
+			result = values
+			return
+		}
+		for id := range result {
+			if !values[id] {
+				delete(result, id)
+			}
+		}
+	}
+
+	if states := r.GetStates(); len(states) > 0 {
+		union := map[string]bool{}
+		for _, state := range states {
+			for id := range ids(tx, bucketStates, state.String()) {
+				union[id] = true
+			}
+		}
+		intersect(union)
+	}
+	for _, tag := range r.GetTags() {
+		if tag != "" {
+			intersect(ids(tx, bucketTags, tag))
+		}
+	}
+	return result
+}
+
+// Create a todo
+func (b *Bolt) Create(ctx context.Context, todo *pb.ToDo) (string, error) {
+	stored := newToDo(primitive.NewObjectID().Hex(), todo, now())
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return putToDo(tx, stored)
+	})
+	if err != nil {
+		return "", err
+	}
+	return stored.Id, nil
+}
+
+// CreateMany creates todos in one transaction so the batch is always atomic
+func (b *Bolt) CreateMany(ctx context.Context, todos []*pb.ToDo, atomic bool) ([]Result, error) {
+	t := now()
+	results := make([]Result, len(todos))
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		for i, todo := range todos {
+			stored := newToDo(primitive.NewObjectID().Hex(), todo, t)
+			if err := putToDo(tx, stored); err != nil {
+				return err
+			}
+			results[i] = Result{ID: stored.Id, Count: 1}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Read a todo
+func (b *Bolt) Read(ctx context.Context, id string) (*pb.ToDo, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	var todo *pb.ToDo
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		todo, err = getToDo(tx, id, false)
+		return err
+	})
+	return todo, err
+}
+
+// Update a todo
+func (b *Bolt) Update(ctx context.Context, todo *pb.ToDo, paths []string, version int64) (int64, error) {
+	if err := checkID(todo.GetId()); err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := getToDo(tx, todo.GetId(), false)
+		if err != nil {
+			return err
+		}
+		next, err := applyUpdate(current, Change{ToDo: todo, Paths: paths, Version: version}, now())
+		if err != nil || next == nil {
+			return err
+		}
+		if err := deleteToDo(tx, current); err != nil {
+			return err
+		}
+		updated = 1
+		return putToDo(tx, next)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// UpdateMany updates todos
+func (b *Bolt) UpdateMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	return b.changeMany(changes, atomic, applyUpdate)
+}
+
+// Delete moves a todo to the trash
+func (b *Bolt) Delete(ctx context.Context, id string, version int64) (int64, error) {
+	if err := checkID(id); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := getToDo(tx, id, false)
+		if err != nil {
+			return err
+		}
+		trashed, err := applyTrash(current, Change{ToDo: current, Version: version}, now())
+		if err != nil {
+			return err
+		}
+		deleted = 1
+		return putToDo(tx, trashed)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// DeleteMany moves todos to the trash
+func (b *Bolt) DeleteMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	return b.changeMany(changes, atomic, applyTrash)
+}
+
+// changeMany applies the changes in one transaction, the indexes of the todos are replaced
+func (b *Bolt) changeMany(changes []Change, atomic bool, apply func(*pb.ToDo, Change, *timestamp.Timestamp) (*pb.ToDo, error)) ([]Result, error) {
+	var results []Result
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		stored := map[string]*pb.ToDo{}
+		get := func(id string) (*pb.ToDo, error) {
+			if err := checkID(id); err != nil {
+				return nil, err
+			}
+			todo, err := getToDo(tx, id, false)
+			stored[id] = todo
+			return todo, err
+		}
+		var changed map[string]*pb.ToDo
+		results, changed = applyMany(changes, atomic, get, apply)
+		for id, todo := range changed {
+			if err := deleteToDo(tx, stored[id]); err != nil {
+				return err
+			}
+			if err := putToDo(tx, todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ReadTrashed reads a todo in the trash
+func (b *Bolt) ReadTrashed(ctx context.Context, id string) (*pb.ToDo, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	var todo *pb.ToDo
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		todo, err = getToDo(tx, id, true)
+		return err
+	})
+	return todo, err
+}
+
+// Restore takes a todo out of the trash
+func (b *Bolt) Restore(ctx context.Context, id string) (int64, error) {
+	if err := checkID(id); err != nil {
+		return 0, err
+	}
+
+	var restored int64
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := getToDo(tx, id, true)
+		if err != nil {
+			return err
+		}
+		next := proto.Clone(current).(*pb.ToDo)
+		stampTrashed(current, next, false, now())
+		restored = 1
+		return putToDo(tx, next)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return restored, nil
+}
+
+// Purge removes a todo from the trash
+func (b *Bolt) Purge(ctx context.Context, id string, version int64) (int64, error) {
+	if err := checkID(id); err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := getToDo(tx, id, true)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(current, version); err != nil {
+			return err
+		}
+		purged = 1
+		return deleteToDo(tx, current)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// Search todos, the indexes restrict the todos to read
+func (b *Bolt) Search(ctx context.Context, q Query) ([]*pb.ToDo, int64, error) {
+	f, err := newFilter(q.Request)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	elements := []*pb.ToDo{}
+	err = b.db.View(func(tx *bolt.Tx) error {
+		keep := func(value []byte) error {
+			todo := &pb.ToDo{}
+			if err := proto.Unmarshal(value, todo); err != nil {
+				return err
+			}
+			if f.match(todo) {
+				elements = append(elements, todo)
+			}
+			return nil
+		}
+
+		todos := tx.Bucket(bucketToDos)
+		selected := candidates(tx, q.Request)
+		if selected == nil {
+			return todos.ForEach(func(k, v []byte) error { return keep(v) })
+		}
+
+		for id := range selected {
+			if value := todos.Get([]byte(id)); value != nil {
+				if err := keep(value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return page(elements, q), int64(len(elements)), nil
+}
+
+// Iterate todos, the todos are read before calling fn to release the transaction
+func (b *Bolt) Iterate(ctx context.Context, q Query, fn func(*pb.ToDo) error) error {
+	todos, _, err := b.Search(ctx, q)
+	if err != nil {
+		return err
+	}
+	return each(ctx, todos, fn)
+}
+
+// CreateList creates a list
+func (b *Bolt) CreateList(ctx context.Context, list *pb.List) (string, error) {
+	stored := newList(primitive.NewObjectID().Hex(), list)
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return putList(tx, stored)
+	})
+	if err != nil {
+		return "", err
+	}
+	return stored.Id, nil
+}
+
+// ReadList reads a list
+func (b *Bolt) ReadList(ctx context.Context, id string) (*pb.List, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	var list *pb.List
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		list, err = getList(tx, id)
+		return err
+	})
+	return list, err
+}
+
+// UpdateList updates a list
+func (b *Bolt) UpdateList(ctx context.Context, list *pb.List) (int64, error) {
+	if err := checkID(list.GetId()); err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := getList(tx, list.GetId())
+		if err != nil {
+			return err
+		}
+
+		next := newList(current.Id, list)
+		next.Owner = current.Owner
+		if proto.Equal(current, next) {
+			return nil
+		}
+		updated = 1
+		return putList(tx, next)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// DeleteList deletes a list
+func (b *Bolt) DeleteList(ctx context.Context, id string) (int64, error) {
+	if err := checkID(id); err != nil {
+		return 0, err
+	}
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		if _, err := getList(tx, id); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketLists).Delete([]byte(id))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// SearchLists searches the lists of a subject, the keys are in the order of creation
+func (b *Bolt) SearchLists(ctx context.Context, subject string) ([]*pb.List, error) {
+	lists := []*pb.List{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketLists).ForEach(func(k, v []byte) error {
+			list := &pb.List{}
+			if err := proto.Unmarshal(v, list); err != nil {
+				return err
+			}
+			if sharedWith(list, subject) {
+				lists = append(lists, list)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// AppendHistory appends a record to the history of its todo
+func (b *Bolt) AppendHistory(ctx context.Context, record *pb.HistoryRecord) error {
+	if err := checkID(record.GetToDoId()); err != nil {
+		return err
+	}
+	value, err := proto.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		history, err := tx.Bucket(bucketHistory).CreateBucketIfNotExists([]byte(record.GetToDoId()))
+		if err != nil {
+			return err
+		}
+		seq, err := history.NextSequence()
+		if err != nil {
+			return err
+		}
+		// the big endian keys keep the order of the sequence
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return history.Put(key, value)
+	})
+}
+
+// History returns the records of a todo
+func (b *Bolt) History(ctx context.Context, id string) ([]*pb.HistoryRecord, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	records := []*pb.HistoryRecord{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		history := tx.Bucket(bucketHistory).Bucket([]byte(id))
+		if history == nil {
+			return nil
+		}
+		return history.ForEach(func(k, v []byte) error {
+			record := &pb.HistoryRecord{}
+			if err := proto.Unmarshal(v, record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Ping checks the file is still opened
+func (b *Bolt) Ping(ctx context.Context) error {
+	return b.db.View(func(tx *bolt.Tx) error { return nil })
+}
+
+// Close closes the file
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,84 @@
+package store_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	. "github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bolt", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "todo-bolt")
+		Ω(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	open := func() *Bolt {
+		store, err := NewBolt(filepath.Join(dir, "todo.db"))
+		Ω(err).NotTo(HaveOccurred())
+		return store
+	}
+
+	Context("As a store", func() {
+		contract(func() Store { return open() })
+	})
+
+	Describe("Restart", func() {
+		It("should keep the todos", func() {
+			store := open()
+			id, err := store.Create(context.TODO(), &pb.ToDo{Title: "persisted", Tags: []string{"golang"}, State: pb.ToDo_DONE})
+			Ω(err).NotTo(HaveOccurred())
+			expected, err := store.Read(context.TODO(), id)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(store.Close()).Should(Succeed())
+
+			store = open()
+			defer store.Close()
+			Ω(unstamped(proto.Clone(expected).(*pb.ToDo))).Should(Equal(&pb.ToDo{
+				Id:       id,
+				Title:    "persisted",
+				Tags:     []string{"golang"},
+				State:    pb.ToDo_DONE,
+				Reminder: &timestamp.Timestamp{},
+				Version:  1,
+			}))
+			Ω(store.Read(context.TODO(), id)).Should(Equal(expected))
+			Ω(search(store, &pb.SearchRequest{Tags: []string{"golang"}, States: []pb.ToDo_State{pb.ToDo_DONE}})).Should(Equal([]*pb.ToDo{expected}))
+		})
+	})
+
+	Describe("Indexes", func() {
+		It("should follow the updates and the deletes", func() {
+			store := open()
+			defer store.Close()
+			id, err := store.Create(context.TODO(), &pb.ToDo{Tags: []string{"old"}, State: pb.ToDo_NOT_STARTED})
+			Ω(err).NotTo(HaveOccurred())
+
+			_, err = store.Update(context.TODO(), &pb.ToDo{Id: id, Tags: []string{"new"}, State: pb.ToDo_DONE}, []string{FieldTags, FieldState}, 0)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(search(store, &pb.SearchRequest{Tags: []string{"old"}})).Should(BeEmpty())
+			Ω(search(store, &pb.SearchRequest{States: []pb.ToDo_State{pb.ToDo_NOT_STARTED}})).Should(BeEmpty())
+			Ω(search(store, &pb.SearchRequest{Tags: []string{"new"}, States: []pb.ToDo_State{pb.ToDo_DONE}})).Should(HaveLen(1))
+
+			_, err = store.Delete(context.TODO(), id, 0)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(search(store, &pb.SearchRequest{Tags: []string{"new"}})).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,882 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	. "github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// contract the behaviour expected from every store apart mongo which needs a database
+func contract(newStore func() Store) {
+
+	var store Store
+
+	BeforeEach(func() {
+		store = newStore()
+	})
+
+	AfterEach(func() {
+		store.Close()
+	})
+
+	//helper create a todo
+	create := func(todo *pb.ToDo) string {
+		id, err := store.Create(context.TODO(), todo)
+		Ω(err).NotTo(HaveOccurred())
+		return id
+	}
+
+	//helper read a todo without its timestamps
+	read := func(id string) *pb.ToDo {
+		todo, err := store.Read(context.TODO(), id)
+		Ω(err).NotTo(HaveOccurred())
+		return unstamped(todo)
+	}
+
+	Describe("Create and Read", func() {
+		Context("With a todo", func() {
+			It("should read it", func() {
+				id := create(&pb.ToDo{
+					Id:          "ignored",
+					Title:       "Challenge - todo",
+					Description: "Should create a micro service with 12factor",
+					Tags:        []string{"golang", "12factor", "k8s"},
+					Reminder:    &timestamp.Timestamp{Seconds: 1573046180},
+					State:       pb.ToDo_IN_PROGRESS,
+					Version:     7,
+				})
+				Ω(id).ShouldNot(Equal("ignored"))
+
+				Ω(read(id)).Should(Equal(&pb.ToDo{
+					Id:          id,
+					Title:       "Challenge - todo",
+					Description: "Should create a micro service with 12factor",
+					Tags:        []string{"golang", "12factor", "k8s"},
+					Reminder:    &timestamp.Timestamp{Seconds: 1573046180},
+					State:       pb.ToDo_IN_PROGRESS,
+					Version:     1,
+				}))
+			})
+		})
+
+		Context("With a deletedAt", func() {
+			It("should create it out of the trash", func() {
+				deletedAt := &timestamp.Timestamp{Seconds: 1573046180}
+				id := create(&pb.ToDo{Title: "Challenge - todo", DeletedAt: deletedAt})
+				Ω(read(id).GetDeletedAt()).Should(BeNil())
+
+				results, err := store.CreateMany(context.TODO(), []*pb.ToDo{{Title: "Challenge - todo", DeletedAt: deletedAt}}, false)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(read(results[0].ID).GetDeletedAt()).Should(BeNil())
+				Ω(search(store, &pb.SearchRequest{Trashed: true})).Should(BeEmpty())
+			})
+		})
+
+		Context("With an owner", func() {
+			It("should keep it", func() {
+				id := create(&pb.ToDo{Title: "Challenge - todo", Owner: "alice"})
+				todo, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(todo.GetOwner()).Should(Equal("alice"))
+			})
+		})
+
+		Context("With a bad id", func() {
+			It("should fail", func() {
+				_, err := store.Read(context.TODO(), "nope")
+				Ω(err).Should(Equal(ErrInvalidID))
+			})
+		})
+
+		Context("An non existing todo", func() {
+			It("should return not found", func() {
+				_, err := store.Read(context.TODO(), "5dc2d3d4aba443c197307ea2")
+				Ω(err).Should(Equal(ErrNotFound))
+			})
+		})
+	})
+
+	Describe("Update", func() {
+		Context("An existing todo", func() {
+			It("should update only the fields", func() {
+				id := create(&pb.ToDo{
+					Title:       "Should be updated",
+					Description: "Should be kept",
+					Tags:        []string{"golang"},
+					Reminder:    &timestamp.Timestamp{Seconds: 1573046240},
+				})
+
+				todo := &pb.ToDo{
+					Id:          id,
+					Title:       "New Challenge - todo",
+					Description: "ignored",
+					Tags:        []string{"golang", "ci/cd"},
+					State:       pb.ToDo_DONE,
+				}
+				Ω(store.Update(context.TODO(), todo, []string{FieldTitle, FieldTags, FieldState}, 0)).Should(Equal(int64(1)))
+
+				Ω(read(id)).Should(Equal(&pb.ToDo{
+					Id:          id,
+					Title:       "New Challenge - todo",
+					Description: "Should be kept",
+					Tags:        []string{"golang", "ci/cd"},
+					Reminder:    &timestamp.Timestamp{Seconds: 1573046240},
+					State:       pb.ToDo_DONE,
+					Version:     2,
+				}))
+			})
+		})
+
+		Context("With empty fields", func() {
+			It("should clear them", func() {
+				id := create(&pb.ToDo{
+					Title:       "Should be kept",
+					Description: "Should be cleared",
+					Tags:        []string{"golang"},
+					Reminder:    &timestamp.Timestamp{Seconds: 1573046240},
+				})
+
+				Ω(store.Update(context.TODO(), &pb.ToDo{Id: id}, []string{FieldDescription, FieldTags, FieldReminder}, 0)).Should(Equal(int64(1)))
+
+				Ω(read(id)).Should(Equal(&pb.ToDo{
+					Id:       id,
+					Title:    "Should be kept",
+					Reminder: &timestamp.Timestamp{},
+					Version:  2,
+				}))
+			})
+		})
+
+		Context("With the list", func() {
+			It("should move the todo", func() {
+				id := create(&pb.ToDo{Title: "title", ListId: "5dc2d3d4aba443c197307ea2"})
+				Ω(store.Update(context.TODO(), &pb.ToDo{Id: id, ListId: "5dc2d3d4aba443c197307ea3"}, []string{FieldListID}, 0)).Should(Equal(int64(1)))
+				todo, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(todo.GetListId()).Should(Equal("5dc2d3d4aba443c197307ea3"))
+			})
+		})
+
+		Context("With the same values", func() {
+			It("should modify nothing", func() {
+				id := create(&pb.ToDo{Title: "title"})
+				Ω(store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "title"}, []string{FieldTitle}, 0)).Should(Equal(int64(0)))
+				todo, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(todo.GetVersion()).Should(Equal(int64(1)))
+			})
+		})
+
+		Context("With the expected version", func() {
+			It("should update the todo and increment its version", func() {
+				id := create(&pb.ToDo{Title: "title"})
+				Ω(store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "first"}, []string{FieldTitle}, 1)).Should(Equal(int64(1)))
+				Ω(store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "second"}, []string{FieldTitle}, 2)).Should(Equal(int64(1)))
+				todo, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(todo.GetTitle()).Should(Equal("second"))
+				Ω(todo.GetVersion()).Should(Equal(int64(3)))
+			})
+		})
+
+		Context("With another version", func() {
+			It("should fail and keep the todo", func() {
+				id := create(&pb.ToDo{Title: "title"})
+				Ω(store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "first"}, []string{FieldTitle}, 1)).Should(Equal(int64(1)))
+				_, err := store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "lost"}, []string{FieldTitle}, 1)
+				Ω(err).Should(Equal(ErrVersionMismatch))
+				todo, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(todo.GetTitle()).Should(Equal("first"))
+			})
+		})
+
+		Context("With a bad id", func() {
+			It("should fail", func() {
+				_, err := store.Update(context.TODO(), &pb.ToDo{Id: "bad id"}, []string{FieldTitle}, 0)
+				Ω(err).Should(Equal(ErrInvalidID))
+			})
+		})
+
+		Context("With a not existing id", func() {
+			It("should return not found", func() {
+				_, err := store.Update(context.TODO(), &pb.ToDo{Id: "5dc2d3d4aba443c197307ea2", Title: "title"}, []string{FieldTitle}, 0)
+				Ω(err).Should(Equal(ErrNotFound))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		Context("An existing todo", func() {
+			It("should delete it", func() {
+				id := create(&pb.ToDo{Title: "Should be deleted"})
+
+				Ω(store.Delete(context.TODO(), id, 0)).Should(Equal(int64(1)))
+
+				_, err := store.Read(context.TODO(), id)
+				Ω(err).Should(Equal(ErrNotFound))
+			})
+		})
+
+		Context("With a bad id", func() {
+			It("should fail", func() {
+				_, err := store.Delete(context.TODO(), "bad id", 0)
+				Ω(err).Should(Equal(ErrInvalidID))
+			})
+		})
+
+		Context("With a not existing id", func() {
+			It("should return not found", func() {
+				_, err := store.Delete(context.TODO(), "5dc2d3d4aba443c197307ea2", 0)
+				Ω(err).Should(Equal(ErrNotFound))
+			})
+		})
+
+		Context("With a version", func() {
+			It("should delete the todo only if it has the version", func() {
+				id := create(&pb.ToDo{Title: "Should be deleted"})
+				_, err := store.Delete(context.TODO(), id, 2)
+				Ω(err).Should(Equal(ErrVersionMismatch))
+				Ω(store.Delete(context.TODO(), id, 1)).Should(Equal(int64(1)))
+			})
+		})
+	})
+
+	Describe("Trash", func() {
+		var id string
+
+		BeforeEach(func() {
+			id = create(&pb.ToDo{Title: "In the trash", State: pb.ToDo_DONE})
+			Ω(store.Delete(context.TODO(), id, 1)).Should(Equal(int64(1)))
+		})
+
+		Context("A deleted todo", func() {
+			It("should be in the trash with a new version", func() {
+				trashed, err := store.ReadTrashed(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(trashed.GetTitle()).Should(Equal("In the trash"))
+				Ω(trashed.GetVersion()).Should(Equal(int64(2)))
+				Ω(trashed.GetDeletedAt()).ShouldNot(BeNil())
+				Ω(trashed.GetUpdatedAt()).Should(Equal(trashed.GetDeletedAt()))
+				Ω(trashed.GetCompletedAt()).ShouldNot(BeNil())
+			})
+
+			It("should be hidden from the other calls", func() {
+				_, err := store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "changed"}, []string{FieldTitle}, 0)
+				Ω(err).Should(Equal(ErrNotFound))
+				_, err = store.Delete(context.TODO(), id, 0)
+				Ω(err).Should(Equal(ErrNotFound))
+				Ω(search(store, &pb.SearchRequest{})).Should(BeEmpty())
+			})
+
+			It("should be found in the trash", func() {
+				todos := search(store, &pb.SearchRequest{Trashed: true})
+				Ω(todos).Should(HaveLen(1))
+				Ω(todos[0].GetId()).Should(Equal(id))
+
+				deletedAt := todos[0].GetDeletedAt()
+				Ω(search(store, &pb.SearchRequest{Trashed: true, DeletedAt: &pb.TimeRange{Before: deletedAt}})).Should(BeEmpty())
+				Ω(search(store, &pb.SearchRequest{Trashed: true, DeletedAt: &pb.TimeRange{After: deletedAt}})).Should(HaveLen(1))
+				Ω(search(store, &pb.SearchRequest{DeletedAt: &pb.TimeRange{After: deletedAt}})).Should(BeEmpty())
+			})
+
+			It("should be restored with a new version", func() {
+				Ω(store.Restore(context.TODO(), id)).Should(Equal(int64(1)))
+
+				todo, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(todo.GetVersion()).Should(Equal(int64(3)))
+				Ω(todo.GetDeletedAt()).Should(BeNil())
+				Ω(todo.GetCompletedAt()).ShouldNot(BeNil())
+				_, err = store.ReadTrashed(context.TODO(), id)
+				Ω(err).Should(Equal(ErrNotFound))
+				_, err = store.Restore(context.TODO(), id)
+				Ω(err).Should(Equal(ErrNotFound))
+			})
+
+			It("should be purged for good", func() {
+				Ω(store.Purge(context.TODO(), id, 0)).Should(Equal(int64(1)))
+
+				_, err := store.ReadTrashed(context.TODO(), id)
+				Ω(err).Should(Equal(ErrNotFound))
+				_, err = store.Restore(context.TODO(), id)
+				Ω(err).Should(Equal(ErrNotFound))
+				Ω(search(store, &pb.SearchRequest{Trashed: true})).Should(BeEmpty())
+			})
+
+			It("should be purged only with its version", func() {
+				_, err := store.Purge(context.TODO(), id, 1)
+				Ω(err).Should(Equal(ErrVersionMismatch))
+				Ω(store.Purge(context.TODO(), id, 2)).Should(Equal(int64(1)))
+			})
+		})
+
+		Context("A todo out of the trash", func() {
+			It("should be neither restored nor purged", func() {
+				other := create(&pb.ToDo{Title: "Not in the trash"})
+				_, err := store.ReadTrashed(context.TODO(), other)
+				Ω(err).Should(Equal(ErrNotFound))
+				_, err = store.Restore(context.TODO(), other)
+				Ω(err).Should(Equal(ErrNotFound))
+				_, err = store.Purge(context.TODO(), other, 0)
+				Ω(err).Should(Equal(ErrNotFound))
+				Ω(read(other).GetTitle()).Should(Equal("Not in the trash"))
+			})
+		})
+
+		Context("With a bad id", func() {
+			It("should fail", func() {
+				_, err := store.ReadTrashed(context.TODO(), "bad id")
+				Ω(err).Should(Equal(ErrInvalidID))
+				_, err = store.Restore(context.TODO(), "bad id")
+				Ω(err).Should(Equal(ErrInvalidID))
+				_, err = store.Purge(context.TODO(), "bad id", 0)
+				Ω(err).Should(Equal(ErrInvalidID))
+			})
+		})
+	})
+
+	Describe("Batch", func() {
+		var ids []string
+
+		BeforeEach(func() {
+			results, err := store.CreateMany(context.TODO(), []*pb.ToDo{{Title: "first"}, {Title: "second", State: pb.ToDo_DONE}}, false)
+			Ω(err).NotTo(HaveOccurred())
+			Ω(results).Should(HaveLen(2))
+			ids = []string{results[0].ID, results[1].ID}
+			for _, r := range results {
+				Ω(r.Err).NotTo(HaveOccurred())
+				Ω(r.Count).Should(Equal(int64(1)))
+			}
+		})
+
+		Context("A created batch", func() {
+			It("should create the todos in order", func() {
+				Ω(read(ids[0]).GetTitle()).Should(Equal("first"))
+				Ω(read(ids[1]).GetTitle()).Should(Equal("second"))
+				Ω(read(ids[1]).GetVersion()).Should(Equal(int64(1)))
+			})
+		})
+
+		Context("An updated batch", func() {
+			It("should apply the updates which succeed", func() {
+				results, err := store.UpdateMany(context.TODO(), []Change{
+					{ToDo: &pb.ToDo{Id: ids[0], Title: "changed"}, Paths: []string{FieldTitle}, Version: 1},
+					{ToDo: &pb.ToDo{Id: ids[1], Title: "changed"}, Paths: []string{FieldTitle}, Version: 3},
+					{ToDo: &pb.ToDo{Id: "5d5fbc4b3f6b7b2c9c8b4567"}, Paths: []string{FieldTitle}},
+					{ToDo: &pb.ToDo{Id: "bad id"}, Paths: []string{FieldTitle}},
+					{ToDo: &pb.ToDo{Id: ids[0], Description: "twice"}, Paths: []string{FieldDescription}, Version: 2},
+				}, false)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(results).Should(Equal([]Result{{Count: 1}, {Err: ErrVersionMismatch}, {Err: ErrNotFound}, {Err: ErrInvalidID}, {Err: ErrDuplicateID}}))
+
+				todo := read(ids[0])
+				Ω(todo.GetTitle()).Should(Equal("changed"))
+				Ω(todo.GetDescription()).Should(BeEmpty())
+				Ω(todo.GetVersion()).Should(Equal(int64(2)))
+				Ω(read(ids[1]).GetTitle()).Should(Equal("second"))
+			})
+
+			It("should apply nothing in an atomic batch with a todo twice", func() {
+				results, err := store.UpdateMany(context.TODO(), []Change{
+					{ToDo: &pb.ToDo{Id: ids[0], Title: "changed"}, Paths: []string{FieldTitle}},
+					{ToDo: &pb.ToDo{Id: ids[0], Description: "twice"}, Paths: []string{FieldDescription}},
+				}, true)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(results).Should(Equal([]Result{{Err: ErrBatchAborted}, {Err: ErrDuplicateID}}))
+				Ω(read(ids[0]).GetTitle()).Should(Equal("first"))
+				Ω(read(ids[0]).GetVersion()).Should(Equal(int64(1)))
+			})
+
+			It("should apply nothing in an atomic batch which fails", func() {
+				results, err := store.UpdateMany(context.TODO(), []Change{
+					{ToDo: &pb.ToDo{Id: ids[0], Title: "changed"}, Paths: []string{FieldTitle}},
+					{ToDo: &pb.ToDo{Id: ids[1], Title: "changed"}, Paths: []string{FieldTitle}, Version: 3},
+				}, true)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(results).Should(Equal([]Result{{Err: ErrBatchAborted}, {Err: ErrVersionMismatch}}))
+				Ω(read(ids[0]).GetTitle()).Should(Equal("first"))
+			})
+
+			It("should count the todos which don't change", func() {
+				results, err := store.UpdateMany(context.TODO(), []Change{
+					{ToDo: &pb.ToDo{Id: ids[0], Title: "first"}, Paths: []string{FieldTitle}},
+				}, true)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(results).Should(Equal([]Result{{}}))
+				Ω(read(ids[0]).GetVersion()).Should(Equal(int64(1)))
+			})
+		})
+
+		Context("A deleted batch", func() {
+			It("should move the todos to the trash", func() {
+				results, err := store.DeleteMany(context.TODO(), []Change{
+					{ToDo: &pb.ToDo{Id: ids[0]}, Version: 1},
+					{ToDo: &pb.ToDo{Id: ids[0]}},
+					{ToDo: &pb.ToDo{Id: ids[1]}, Version: 2},
+				}, false)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(results).Should(Equal([]Result{{Count: 1}, {Err: ErrDuplicateID}, {Err: ErrVersionMismatch}}))
+
+				trashed, err := store.ReadTrashed(context.TODO(), ids[0])
+				Ω(err).NotTo(HaveOccurred())
+				Ω(trashed.GetVersion()).Should(Equal(int64(2)))
+				Ω(read(ids[1]).GetDeletedAt()).Should(BeNil())
+			})
+
+			It("should move all the todos of an atomic batch", func() {
+				results, err := store.DeleteMany(context.TODO(), []Change{{ToDo: &pb.ToDo{Id: ids[0]}}, {ToDo: &pb.ToDo{Id: ids[1]}}}, true)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(results).Should(Equal([]Result{{Count: 1}, {Count: 1}}))
+				Ω(search(store, &pb.SearchRequest{})).Should(BeEmpty())
+				Ω(search(store, &pb.SearchRequest{Trashed: true})).Should(HaveLen(2))
+			})
+		})
+	})
+
+	Describe("Timestamps", func() {
+		//helper the time of a timestamp
+		at := func(t *timestamp.Timestamp) time.Time {
+			value, err := ptypes.Timestamp(t)
+			Ω(err).NotTo(HaveOccurred())
+			return value
+		}
+
+		Context("A new todo", func() {
+			It("should be created and updated now, and completed only if DONE", func() {
+				before := time.Now().Add(-time.Millisecond)
+				id := create(&pb.ToDo{Title: "title", CreatedAt: &timestamp.Timestamp{Seconds: 1}, CompletedAt: &timestamp.Timestamp{Seconds: 1}})
+				todo, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(at(todo.GetCreatedAt())).Should(BeTemporally(">=", before))
+				Ω(at(todo.GetCreatedAt())).Should(BeTemporally("<=", time.Now()))
+				Ω(todo.GetUpdatedAt()).Should(Equal(todo.GetCreatedAt()))
+				Ω(todo.GetCompletedAt()).Should(BeNil())
+
+				id = create(&pb.ToDo{Title: "title", State: pb.ToDo_DONE})
+				todo, err = store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(todo.GetCompletedAt()).Should(Equal(todo.GetCreatedAt()))
+			})
+		})
+
+		Context("An updated todo", func() {
+			It("should be completed when it moves to DONE and no longer when it moves back", func() {
+				id := create(&pb.ToDo{Title: "title"})
+				created, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				time.Sleep(2 * time.Millisecond)
+
+				_, err = store.Update(context.TODO(), &pb.ToDo{Id: id, State: pb.ToDo_DONE, CreatedAt: &timestamp.Timestamp{Seconds: 1}}, []string{FieldState}, 0)
+				Ω(err).NotTo(HaveOccurred())
+				done, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(done.GetCreatedAt()).Should(Equal(created.GetCreatedAt()))
+				Ω(at(done.GetUpdatedAt())).Should(BeTemporally(">", at(created.GetUpdatedAt())))
+				Ω(done.GetCompletedAt()).Should(Equal(done.GetUpdatedAt()))
+				time.Sleep(2 * time.Millisecond)
+
+				By("Keep the completion while the todo stays DONE")
+				_, err = store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "new"}, []string{FieldTitle}, 0)
+				Ω(err).NotTo(HaveOccurred())
+				renamed, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(at(renamed.GetUpdatedAt())).Should(BeTemporally(">", at(done.GetUpdatedAt())))
+				Ω(renamed.GetCompletedAt()).Should(Equal(done.GetCompletedAt()))
+
+				By("Clear the completion when the todo moves back")
+				_, err = store.Update(context.TODO(), &pb.ToDo{Id: id, State: pb.ToDo_IN_PROGRESS}, []string{FieldState}, 0)
+				Ω(err).NotTo(HaveOccurred())
+				reopened, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				Ω(reopened.GetCompletedAt()).Should(BeNil())
+			})
+		})
+
+		Context("A todo updated with the same values", func() {
+			It("should keep its timestamps", func() {
+				id := create(&pb.ToDo{Title: "title"})
+				created, err := store.Read(context.TODO(), id)
+				Ω(err).NotTo(HaveOccurred())
+				time.Sleep(2 * time.Millisecond)
+				Ω(store.Update(context.TODO(), &pb.ToDo{Id: id, Title: "title"}, []string{FieldTitle}, 0)).Should(Equal(int64(0)))
+				Ω(store.Read(context.TODO(), id)).Should(Equal(created))
+			})
+		})
+	})
+
+	Describe("Search", func() {
+		var read, write, done string
+
+		BeforeEach(func() {
+			read = create(&pb.ToDo{Description: "Read - micro service", Tags: []string{"golang", "12factor", "k8s"}, State: pb.ToDo_DONE})
+			write = create(&pb.ToDo{Description: "Write - micro service", Tags: []string{"golang"}, State: pb.ToDo_IN_PROGRESS})
+			done = create(&pb.ToDo{Description: "Done", Tags: []string{"k8s"}, State: pb.ToDo_DONE})
+		})
+
+		ids := func(r *pb.SearchRequest) []string {
+			todos := search(store, r)
+			result := []string{}
+			for _, todo := range todos {
+				result = append(result, todo.GetId())
+			}
+			return result
+		}
+
+		Context("Without filter", func() {
+			It("should return all the todos in order of creation", func() {
+				Ω(ids(&pb.SearchRequest{})).Should(Equal([]string{read, write, done}))
+			})
+		})
+
+		Context("With a literal pattern", func() {
+			It("should match the descriptions which contain it", func() {
+				Ω(ids(&pb.SearchRequest{Pattern: "micro"})).Should(Equal([]string{read, write}))
+				Ω(ids(&pb.SearchRequest{Pattern: ".*micro.*"})).Should(BeEmpty())
+			})
+		})
+
+		Context("With a prefix", func() {
+			It("should match the descriptions which start with it", func() {
+				Ω(ids(&pb.SearchRequest{Pattern: "Write", MatchMode: pb.SearchRequest_PREFIX})).Should(Equal([]string{write}))
+				Ω(ids(&pb.SearchRequest{Pattern: "micro", MatchMode: pb.SearchRequest_PREFIX})).Should(BeEmpty())
+			})
+		})
+
+		Context("With a regex", func() {
+			It("should match the description", func() {
+				Ω(ids(&pb.SearchRequest{Pattern: ".*micro.*", MatchMode: pb.SearchRequest_REGEX})).Should(Equal([]string{read, write}))
+				Ω(ids(&pb.SearchRequest{Pattern: "^Write", MatchMode: pb.SearchRequest_REGEX})).Should(Equal([]string{write}))
+			})
+		})
+
+		Context("With a full text pattern", func() {
+			It("should match the todos which have all the words whatever the case", func() {
+				Ω(ids(&pb.SearchRequest{Pattern: "SERVICE read", MatchMode: pb.SearchRequest_FULLTEXT})).Should(Equal([]string{read}))
+				Ω(ids(&pb.SearchRequest{Pattern: "micro", MatchMode: pb.SearchRequest_FULLTEXT})).Should(Equal([]string{read, write}))
+				Ω(ids(&pb.SearchRequest{Pattern: "serv", MatchMode: pb.SearchRequest_FULLTEXT})).Should(BeEmpty())
+			})
+		})
+
+		Context("With states", func() {
+			It("should match one of them", func() {
+				Ω(ids(&pb.SearchRequest{States: []pb.ToDo_State{pb.ToDo_DONE}})).Should(Equal([]string{read, done}))
+				Ω(ids(&pb.SearchRequest{States: []pb.ToDo_State{pb.ToDo_DONE, pb.ToDo_IN_PROGRESS}})).Should(Equal([]string{read, write, done}))
+			})
+		})
+
+		Context("With tags", func() {
+			It("should match all of them", func() {
+				Ω(ids(&pb.SearchRequest{Tags: []string{"golang"}})).Should(Equal([]string{read, write}))
+				Ω(ids(&pb.SearchRequest{Tags: []string{"golang", "k8s"}})).Should(Equal([]string{read}))
+			})
+		})
+
+		Context("With an owner", func() {
+			It("should match only the todos of the owner", func() {
+				alice := create(&pb.ToDo{Description: "Alice - micro service", Owner: "alice"})
+				create(&pb.ToDo{Description: "Bob - micro service", Owner: "bob"})
+				Ω(ids(&pb.SearchRequest{Owner: "alice"})).Should(Equal([]string{alice}))
+				Ω(ids(&pb.SearchRequest{Owner: "alice", Pattern: "Bob", MatchMode: pb.SearchRequest_PREFIX})).Should(BeEmpty())
+			})
+		})
+
+		Context("With a list", func() {
+			It("should match only the todos of the list", func() {
+				groceries := create(&pb.ToDo{Description: "Groceries - milk", ListId: "5dc2d3d4aba443c197307ea2"})
+				create(&pb.ToDo{Description: "Work - micro service", ListId: "5dc2d3d4aba443c197307ea3"})
+				Ω(ids(&pb.SearchRequest{ListId: "5dc2d3d4aba443c197307ea2"})).Should(Equal([]string{groceries}))
+			})
+		})
+
+		Context("With time ranges", func() {
+			It("should match the todos from after to before", func() {
+				first, err := store.Read(context.TODO(), read)
+				Ω(err).NotTo(HaveOccurred())
+				time.Sleep(2 * time.Millisecond)
+				_, err = store.Update(context.TODO(), &pb.ToDo{Id: write, Title: "updated"}, []string{FieldTitle}, 0)
+				Ω(err).NotTo(HaveOccurred())
+				updated, err := store.Read(context.TODO(), write)
+				Ω(err).NotTo(HaveOccurred())
+
+				Ω(ids(&pb.SearchRequest{CreatedAt: &pb.TimeRange{After: first.GetCreatedAt()}})).Should(Equal([]string{read, write, done}))
+				Ω(ids(&pb.SearchRequest{CreatedAt: &pb.TimeRange{Before: first.GetCreatedAt()}})).Should(BeEmpty())
+				Ω(ids(&pb.SearchRequest{UpdatedAt: &pb.TimeRange{After: updated.GetUpdatedAt()}})).Should(Equal([]string{write}))
+				Ω(ids(&pb.SearchRequest{UpdatedAt: &pb.TimeRange{Before: updated.GetUpdatedAt()}})).Should(Equal([]string{read, done}))
+				Ω(ids(&pb.SearchRequest{CompletedAt: &pb.TimeRange{After: &timestamp.Timestamp{}}})).Should(Equal([]string{read, done}))
+				Ω(ids(&pb.SearchRequest{CompletedAt: &pb.TimeRange{}})).Should(Equal([]string{read, write, done}))
+			})
+		})
+
+		Context("With a pattern, tags and state which doesn't match", func() {
+			It("should return nothing", func() {
+				Ω(ids(&pb.SearchRequest{Pattern: "Write", MatchMode: pb.SearchRequest_PREFIX, States: []pb.ToDo_State{pb.ToDo_DONE}, Tags: []string{"golang"}})).Should(BeEmpty())
+			})
+		})
+
+		Context("With an order", func() {
+			It("should sort the todos", func() {
+				_, err := store.Update(context.TODO(), &pb.ToDo{Id: read, Title: "b", Reminder: &timestamp.Timestamp{Seconds: 3}}, []string{FieldTitle, FieldReminder}, 0)
+				Ω(err).NotTo(HaveOccurred())
+				_, err = store.Update(context.TODO(), &pb.ToDo{Id: write, Title: "c", Reminder: &timestamp.Timestamp{Seconds: 1}}, []string{FieldTitle, FieldReminder}, 0)
+				Ω(err).NotTo(HaveOccurred())
+				_, err = store.Update(context.TODO(), &pb.ToDo{Id: done, Title: "a", Reminder: &timestamp.Timestamp{Seconds: 2}}, []string{FieldTitle, FieldReminder}, 0)
+				Ω(err).NotTo(HaveOccurred())
+
+				order := func(orderBy string, descending bool) []string {
+					todos, _, err := store.Search(context.TODO(), Query{Request: &pb.SearchRequest{}, OrderBy: orderBy, Descending: descending})
+					Ω(err).NotTo(HaveOccurred())
+					result := []string{}
+					for _, todo := range todos {
+						result = append(result, todo.GetId())
+					}
+					return result
+				}
+				Ω(order(OrderCreated, true)).Should(Equal([]string{done, write, read}))
+				Ω(order(OrderTitle, false)).Should(Equal([]string{done, read, write}))
+				Ω(order(OrderReminder, false)).Should(Equal([]string{write, done, read}))
+				Ω(order(OrderState, false)).Should(Equal([]string{read, done, write}))
+				Ω(order(OrderState, true)).Should(Equal([]string{write, done, read}))
+				Ω(order(OrderUpdated, false)).Should(Equal([]string{read, write, done}))
+				Ω(order(OrderCompleted, false)).Should(Equal([]string{write, read, done}))
+				Ω(order(OrderCompleted, true)).Should(Equal([]string{done, read, write}))
+			})
+		})
+
+		Context("With a page", func() {
+			It("should return the page and the total", func() {
+				todos, total, err := store.Search(context.TODO(), Query{Request: &pb.SearchRequest{}, Offset: 1, Limit: 1})
+				Ω(err).NotTo(HaveOccurred())
+				Ω(total).Should(Equal(int64(3)))
+				Ω(todos).Should(HaveLen(1))
+				Ω(todos[0].GetId()).Should(Equal(write))
+
+				todos, total, err = store.Search(context.TODO(), Query{Request: &pb.SearchRequest{}, Offset: 3, Limit: 1})
+				Ω(err).NotTo(HaveOccurred())
+				Ω(total).Should(Equal(int64(3)))
+				Ω(todos).Should(BeEmpty())
+			})
+		})
+
+		Context("With a bad pattern", func() {
+			It("should fail", func() {
+				_, _, err := store.Search(context.TODO(), Query{Request: &pb.SearchRequest{Pattern: "([", MatchMode: pb.SearchRequest_REGEX}})
+				Ω(errors.Is(err, ErrInvalidPattern)).Should(BeTrue())
+			})
+		})
+	})
+
+	Describe("Iterate", func() {
+		var first, second string
+
+		BeforeEach(func() {
+			first = create(&pb.ToDo{Title: "b", State: pb.ToDo_DONE})
+			second = create(&pb.ToDo{Title: "a", State: pb.ToDo_DONE})
+			create(&pb.ToDo{Title: "c", State: pb.ToDo_NOT_STARTED})
+		})
+
+		Context("With a filter and an order", func() {
+			It("should call fn on each todo in the order", func() {
+				result := []string{}
+				err := store.Iterate(context.TODO(), Query{Request: &pb.SearchRequest{States: []pb.ToDo_State{pb.ToDo_DONE}}, OrderBy: OrderTitle}, func(todo *pb.ToDo) error {
+					result = append(result, todo.GetId())
+					return nil
+				})
+				Ω(err).NotTo(HaveOccurred())
+				Ω(result).Should(Equal([]string{second, first}))
+			})
+		})
+
+		Context("With an error in fn", func() {
+			It("should stop and return it", func() {
+				stop := errors.New("stop")
+				calls := 0
+				err := store.Iterate(context.TODO(), Query{Request: &pb.SearchRequest{}}, func(todo *pb.ToDo) error {
+					calls++
+					return stop
+				})
+				Ω(err).Should(Equal(stop))
+				Ω(calls).Should(Equal(1))
+			})
+		})
+
+		Context("With a bad pattern", func() {
+			It("should fail", func() {
+				err := store.Iterate(context.TODO(), Query{Request: &pb.SearchRequest{Pattern: "([", MatchMode: pb.SearchRequest_REGEX}}, func(todo *pb.ToDo) error { return nil })
+				Ω(errors.Is(err, ErrInvalidPattern)).Should(BeTrue())
+			})
+		})
+	})
+
+	Describe("Lists", func() {
+		createList := func(list *pb.List) string {
+			id, err := store.CreateList(context.TODO(), list)
+			Ω(err).NotTo(HaveOccurred())
+			return id
+		}
+
+		Context("A new list", func() {
+			It("should read it", func() {
+				id := createList(&pb.List{
+					Id:      "ignored",
+					Name:    "Groceries",
+					Owner:   "alice",
+					Members: []*pb.List_Member{{Subject: "bob", Role: pb.List_EDITOR}},
+				})
+				Ω(id).ShouldNot(Equal("ignored"))
+				Ω(store.ReadList(context.TODO(), id)).Should(Equal(&pb.List{
+					Id:      id,
+					Name:    "Groceries",
+					Owner:   "alice",
+					Members: []*pb.List_Member{{Subject: "bob", Role: pb.List_EDITOR}},
+				}))
+			})
+		})
+
+		Context("An updated list", func() {
+			It("should replace the name and the members and keep the owner", func() {
+				id := createList(&pb.List{Name: "Groceries", Owner: "alice"})
+				list := &pb.List{
+					Id:      id,
+					Name:    "Shopping",
+					Owner:   "ignored",
+					Members: []*pb.List_Member{{Subject: "bob", Role: pb.List_VIEWER}},
+				}
+				Ω(store.UpdateList(context.TODO(), list)).Should(Equal(int64(1)))
+				Ω(store.UpdateList(context.TODO(), list)).Should(Equal(int64(0)))
+				Ω(store.ReadList(context.TODO(), id)).Should(Equal(&pb.List{
+					Id:      id,
+					Name:    "Shopping",
+					Owner:   "alice",
+					Members: []*pb.List_Member{{Subject: "bob", Role: pb.List_VIEWER}},
+				}))
+			})
+		})
+
+		Context("A deleted list", func() {
+			It("should not be found", func() {
+				id := createList(&pb.List{Name: "Groceries"})
+				Ω(store.DeleteList(context.TODO(), id)).Should(Equal(int64(1)))
+				_, err := store.ReadList(context.TODO(), id)
+				Ω(err).Should(Equal(ErrListNotFound))
+				_, err = store.DeleteList(context.TODO(), id)
+				Ω(err).Should(Equal(ErrListNotFound))
+			})
+		})
+
+		Context("A non existing list", func() {
+			It("should return not found", func() {
+				_, err := store.ReadList(context.TODO(), "5dc2d3d4aba443c197307ea2")
+				Ω(err).Should(Equal(ErrListNotFound))
+				_, err = store.UpdateList(context.TODO(), &pb.List{Id: "5dc2d3d4aba443c197307ea2"})
+				Ω(err).Should(Equal(ErrListNotFound))
+				_, err = store.ReadList(context.TODO(), "nope")
+				Ω(err).Should(Equal(ErrInvalidID))
+			})
+		})
+
+		Context("A search", func() {
+			It("should return the lists owned by or shared with the subject in the order of creation", func() {
+				groceries := createList(&pb.List{Name: "Groceries", Owner: "alice"})
+				work := createList(&pb.List{Name: "Work", Owner: "bob", Members: []*pb.List_Member{{Subject: "alice", Role: pb.List_VIEWER}}})
+				other := createList(&pb.List{Name: "Other", Owner: "bob"})
+
+				listIDs := func(subject string) []string {
+					lists, err := store.SearchLists(context.TODO(), subject)
+					Ω(err).NotTo(HaveOccurred())
+					result := []string{}
+					for _, list := range lists {
+						result = append(result, list.GetId())
+					}
+					return result
+				}
+				Ω(listIDs("alice")).Should(Equal([]string{groceries, work}))
+				Ω(listIDs("bob")).Should(Equal([]string{work, other}))
+				Ω(listIDs("carol")).Should(BeEmpty())
+				Ω(listIDs("")).Should(Equal([]string{groceries, work, other}))
+			})
+		})
+	})
+
+	Describe("History", func() {
+		Context("A todo with changes", func() {
+			It("should return its records in order, even after its deletion", func() {
+				id := create(&pb.ToDo{Title: "title"})
+				created := &pb.HistoryRecord{
+					ToDoId:    id,
+					Type:      pb.Event_CREATED,
+					Actor:     "alice",
+					Time:      &timestamp.Timestamp{Seconds: 1573046180},
+					RequestId: "request-1",
+					Version:   1,
+					Changes:   []*pb.HistoryRecord_Change{{Field: FieldTitle, NewValue: "title"}},
+					Owner:     "alice",
+				}
+				deleted := &pb.HistoryRecord{
+					ToDoId:    id,
+					Type:      pb.Event_DELETED,
+					Actor:     "bob",
+					Time:      &timestamp.Timestamp{Seconds: 1573046240},
+					RequestId: "request-2",
+					Version:   1,
+					Changes:   []*pb.HistoryRecord_Change{{Field: FieldTitle, OldValue: "title"}},
+					Owner:     "alice",
+					ListId:    "5dc2d3d4aba443c197307ea2",
+				}
+				// the stores may change the internal fields of the records they append
+				Ω(store.AppendHistory(context.TODO(), proto.Clone(created).(*pb.HistoryRecord))).Should(Succeed())
+				Ω(store.AppendHistory(context.TODO(), proto.Clone(deleted).(*pb.HistoryRecord))).Should(Succeed())
+				Ω(store.AppendHistory(context.TODO(), &pb.HistoryRecord{ToDoId: "5dc2d3d4aba443c197307ea3"})).Should(Succeed())
+				_, err := store.Delete(context.TODO(), id, 0)
+				Ω(err).NotTo(HaveOccurred())
+
+				Ω(store.History(context.TODO(), id)).Should(Equal([]*pb.HistoryRecord{created, deleted}))
+			})
+		})
+
+		Context("A todo without changes", func() {
+			It("should return no record", func() {
+				Ω(store.History(context.TODO(), "5dc2d3d4aba443c197307ea2")).Should(BeEmpty())
+			})
+		})
+
+		Context("With a bad id", func() {
+			It("should fail", func() {
+				_, err := store.History(context.TODO(), "nope")
+				Ω(err).Should(Equal(ErrInvalidID))
+				Ω(store.AppendHistory(context.TODO(), &pb.HistoryRecord{ToDoId: "nope"})).Should(Equal(ErrInvalidID))
+			})
+		})
+	})
+
+	Describe("Concurrent calls", func() {
+		It("should keep all the todos", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					id := create(&pb.ToDo{Title: fmt.Sprintf("todo %d", i)})
+					_, err := store.Update(context.TODO(), &pb.ToDo{Id: id, State: pb.ToDo_DONE}, []string{FieldState}, 0)
+					Ω(err).NotTo(HaveOccurred())
+					_, _, err = store.Search(context.TODO(), Query{Request: &pb.SearchRequest{}})
+					Ω(err).NotTo(HaveOccurred())
+				}(i)
+			}
+			wg.Wait()
+			Ω(search(store, &pb.SearchRequest{States: []pb.ToDo_State{pb.ToDo_DONE}})).Should(HaveLen(50))
+		})
+	})
+}
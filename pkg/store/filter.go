@@ -0,0 +1,146 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// filter checks a todo against a search request the same way the mongo query does:
+// pattern on description or $text, $in on states, $all on tags, equality on owner and list, ranges on the timestamps
+// and the todos in the trash or the others.
+type filter struct {
+	trashed   bool
+	owner     string
+	listID    string
+	pattern   func(*pb.ToDo) bool
+	states    map[pb.ToDo_State]bool
+	tags      []string
+	created   *pb.TimeRange
+	updated   *pb.TimeRange
+	completed *pb.TimeRange
+	deleted   *pb.TimeRange
+}
+
+func newFilter(r *pb.SearchRequest) (*filter, error) {
+	f := &filter{
+		trashed:   r.GetTrashed(),
+		owner:     r.GetOwner(),
+		listID:    r.GetListId(),
+		tags:      r.GetTags(),
+		created:   r.GetCreatedAt(),
+		updated:   r.GetUpdatedAt(),
+		completed: r.GetCompletedAt(),
+		deleted:   r.GetDeletedAt(),
+	}
+	if pattern := r.GetPattern(); pattern != "" {
+		var err error
+		if f.pattern, err = newPattern(r.GetMatchMode(), pattern); err != nil {
+			return nil, err
+		}
+	}
+	if states := r.GetStates(); len(states) > 0 {
+		f.states = make(map[pb.ToDo_State]bool, len(states))
+		for _, state := range states {
+			f.states[state] = true
+		}
+	}
+	return f, nil
+}
+
+// NewMatcher returns a function which tells if a todo matches the filters of r like Search does
+func NewMatcher(r *pb.SearchRequest) (func(*pb.ToDo) bool, error) {
+	f, err := newFilter(r)
+	if err != nil {
+		return nil, err
+	}
+	return f.match, nil
+}
+
+func (f *filter) match(todo *pb.ToDo) bool {
+	if checkTrash(todo, f.trashed) != nil {
+		return false
+	}
+	if f.owner != "" && todo.GetOwner() != f.owner {
+		return false
+	}
+	if f.listID != "" && todo.GetListId() != f.listID {
+		return false
+	}
+	if f.pattern != nil && !f.pattern(todo) {
+		return false
+	}
+	if f.states != nil && !f.states[todo.GetState()] {
+		return false
+	}
+	if !inRange(todo.GetCreatedAt(), f.created) || !inRange(todo.GetUpdatedAt(), f.updated) || !inRange(todo.GetCompletedAt(), f.completed) || !inRange(todo.GetDeletedAt(), f.deleted) {
+		return false
+	}
+	return containsAll(todo.GetTags(), f.tags)
+}
+
+// newPattern returns the function which tells if a todo matches the pattern in the mode
+func newPattern(mode pb.SearchRequest_MatchMode, pattern string) (func(*pb.ToDo) bool, error) {
+	switch mode {
+	case pb.SearchRequest_LITERAL:
+		return func(todo *pb.ToDo) bool { return strings.Contains(todo.GetDescription(), pattern) }, nil
+	case pb.SearchRequest_PREFIX:
+		return func(todo *pb.ToDo) bool { return strings.HasPrefix(todo.GetDescription(), pattern) }, nil
+	case pb.SearchRequest_REGEX:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+		}
+		return func(todo *pb.ToDo) bool { return re.MatchString(todo.GetDescription()) }, nil
+	case pb.SearchRequest_FULLTEXT:
+		expected := Words(pattern)
+		return func(todo *pb.ToDo) bool {
+			words := Words(todo.GetTitle() + " " + todo.GetDescription())
+			return containsAll(words, expected)
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown match mode %d", ErrInvalidPattern, mode)
+	}
+}
+
+// Words returns the lowercase words of s for the full text search, the words are split on the characters which are not letters or digits
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+// inRange tells if t is in the range like $gte and $lt do, a missing t is in no range with a bound
+func inRange(t *timestamp.Timestamp, r *pb.TimeRange) bool {
+	if r.GetAfter() == nil && r.GetBefore() == nil {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	if after := r.GetAfter(); after != nil && compareTimestamp(t, after) < 0 {
+		return false
+	}
+	if before := r.GetBefore(); before != nil && compareTimestamp(t, before) >= 0 {
+		return false
+	}
+	return true
+}
+
+func containsAll(values []string, expected []string) bool {
+	for _, e := range expected {
+		found := false
+		for _, v := range values {
+			if v == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,321 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Memory stores the todos, the lists and the histories in memory, it is safe for concurrent use.
+type Memory struct {
+	mu        sync.RWMutex
+	todos     map[string]*pb.ToDo
+	lists     map[string]*pb.List
+	histories map[string][]*pb.HistoryRecord
+}
+
+// validate the implementation
+var _ Store = &Memory{}
+
+// NewMemory creates an empty store
+func NewMemory() *Memory {
+	return &Memory{
+		todos:     make(map[string]*pb.ToDo),
+		lists:     make(map[string]*pb.List),
+		histories: make(map[string][]*pb.HistoryRecord),
+	}
+}
+
+// checkID the IDs have the same format as in mongo
+func checkID(id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return ErrInvalidID
+	}
+	return nil
+}
+
+// Create a todo
+func (m *Memory) Create(ctx context.Context, todo *pb.ToDo) (string, error) {
+	stored := newToDo(primitive.NewObjectID().Hex(), todo, now())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.todos[stored.Id] = stored
+	return stored.Id, nil
+}
+
+// CreateMany creates todos, they are created under the lock so the batch is always atomic
+func (m *Memory) CreateMany(ctx context.Context, todos []*pb.ToDo, atomic bool) ([]Result, error) {
+	t := now()
+	results := make([]Result, len(todos))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, todo := range todos {
+		stored := newToDo(primitive.NewObjectID().Hex(), todo, t)
+		m.todos[stored.Id] = stored
+		results[i] = Result{ID: stored.Id, Count: 1}
+	}
+	return results, nil
+}
+
+// get returns the todo in the trash if trashed is set or else out of it, the caller holds the lock
+func (m *Memory) get(id string, trashed bool) (*pb.ToDo, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	todo, ok := m.todos[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if err := checkTrash(todo, trashed); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// Read a todo
+func (m *Memory) Read(ctx context.Context, id string) (*pb.ToDo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	todo, err := m.get(id, false)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(todo).(*pb.ToDo), nil
+}
+
+// Update a todo
+func (m *Memory) Update(ctx context.Context, todo *pb.ToDo, paths []string, version int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, err := m.get(todo.GetId(), false)
+	if err != nil {
+		return 0, err
+	}
+	updated, err := applyUpdate(current, Change{ToDo: todo, Paths: paths, Version: version}, now())
+	if err != nil || updated == nil {
+		return 0, err
+	}
+	m.todos[updated.Id] = updated
+	return 1, nil
+}
+
+// UpdateMany updates todos
+func (m *Memory) UpdateMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	return m.changeMany(changes, atomic, applyUpdate)
+}
+
+// Delete moves a todo to the trash
+func (m *Memory) Delete(ctx context.Context, id string, version int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, err := m.get(id, false)
+	if err != nil {
+		return 0, err
+	}
+	trashed, err := applyTrash(current, Change{ToDo: current, Version: version}, now())
+	if err != nil {
+		return 0, err
+	}
+	m.todos[id] = trashed
+	return 1, nil
+}
+
+// DeleteMany moves todos to the trash
+func (m *Memory) DeleteMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	return m.changeMany(changes, atomic, applyTrash)
+}
+
+// changeMany applies the changes under the lock
+func (m *Memory) changeMany(changes []Change, atomic bool, apply func(*pb.ToDo, Change, *timestamp.Timestamp) (*pb.ToDo, error)) ([]Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results, changed := applyMany(changes, atomic, func(id string) (*pb.ToDo, error) { return m.get(id, false) }, apply)
+	for id, todo := range changed {
+		m.todos[id] = todo
+	}
+	return results, nil
+}
+
+// ReadTrashed reads a todo in the trash
+func (m *Memory) ReadTrashed(ctx context.Context, id string) (*pb.ToDo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	todo, err := m.get(id, true)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(todo).(*pb.ToDo), nil
+}
+
+// Restore takes a todo out of the trash
+func (m *Memory) Restore(ctx context.Context, id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, err := m.get(id, true)
+	if err != nil {
+		return 0, err
+	}
+	restored := proto.Clone(current).(*pb.ToDo)
+	stampTrashed(current, restored, false, now())
+	m.todos[id] = restored
+	return 1, nil
+}
+
+// Purge removes a todo from the trash
+func (m *Memory) Purge(ctx context.Context, id string, version int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, err := m.get(id, true)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkVersion(current, version); err != nil {
+		return 0, err
+	}
+	delete(m.todos, id)
+	return 1, nil
+}
+
+// Search todos
+func (m *Memory) Search(ctx context.Context, q Query) ([]*pb.ToDo, int64, error) {
+	f, err := newFilter(q.Request)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	elements := []*pb.ToDo{}
+	for _, todo := range m.todos {
+		if f.match(todo) {
+			elements = append(elements, proto.Clone(todo).(*pb.ToDo))
+		}
+	}
+	return page(elements, q), int64(len(elements)), nil
+}
+
+// Iterate todos, the todos are copied before calling fn so it can call the store
+func (m *Memory) Iterate(ctx context.Context, q Query, fn func(*pb.ToDo) error) error {
+	todos, _, err := m.Search(ctx, q)
+	if err != nil {
+		return err
+	}
+	return each(ctx, todos, fn)
+}
+
+// CreateList creates a list
+func (m *Memory) CreateList(ctx context.Context, list *pb.List) (string, error) {
+	stored := newList(primitive.NewObjectID().Hex(), list)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lists[stored.Id] = stored
+	return stored.Id, nil
+}
+
+// ReadList reads a list
+func (m *Memory) ReadList(ctx context.Context, id string) (*pb.List, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list, ok := m.lists[id]
+	if !ok {
+		return nil, ErrListNotFound
+	}
+	return proto.Clone(list).(*pb.List), nil
+}
+
+// UpdateList updates a list
+func (m *Memory) UpdateList(ctx context.Context, list *pb.List) (int64, error) {
+	if err := checkID(list.GetId()); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.lists[list.GetId()]
+	if !ok {
+		return 0, ErrListNotFound
+	}
+	updated := newList(current.Id, list)
+	updated.Owner = current.Owner
+	if proto.Equal(current, updated) {
+		return 0, nil
+	}
+	m.lists[updated.Id] = updated
+	return 1, nil
+}
+
+// DeleteList deletes a list
+func (m *Memory) DeleteList(ctx context.Context, id string) (int64, error) {
+	if err := checkID(id); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.lists[id]; !ok {
+		return 0, ErrListNotFound
+	}
+	delete(m.lists, id)
+	return 1, nil
+}
+
+// SearchLists searches the lists of a subject
+func (m *Memory) SearchLists(ctx context.Context, subject string) ([]*pb.List, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	lists := []*pb.List{}
+	for _, list := range m.lists {
+		if sharedWith(list, subject) {
+			lists = append(lists, proto.Clone(list).(*pb.List))
+		}
+	}
+	return sortLists(lists), nil
+}
+
+// AppendHistory appends a copy of the record to the history of its todo
+func (m *Memory) AppendHistory(ctx context.Context, record *pb.HistoryRecord) error {
+	if err := checkID(record.GetToDoId()); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.histories[record.GetToDoId()] = append(m.histories[record.GetToDoId()], proto.Clone(record).(*pb.HistoryRecord))
+	return nil
+}
+
+// History returns copies of the records of a todo
+func (m *Memory) History(ctx context.Context, id string) ([]*pb.HistoryRecord, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	records := []*pb.HistoryRecord{}
+	for _, record := range m.histories[id] {
+		records = append(records, proto.Clone(record).(*pb.HistoryRecord))
+	}
+	return records, nil
+}
+
+// Ping always succeeds
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing, the todos are kept
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,11 @@
+package store_test
+
+import (
+	. "github.com/sjeandeaux/todo/pkg/store"
+
+	. "github.com/onsi/ginkgo"
+)
+
+var _ = Describe("Memory", func() {
+	contract(func() Store { return NewMemory() })
+})
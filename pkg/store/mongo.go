@@ -0,0 +1,1033 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	database          = "challenge"
+	collection        = "todo"
+	listCollection    = "list"
+	historyCollection = "history"
+)
+
+const (
+	keyID          = "_id"
+	keyTitle       = "title"
+	keyDescription = "description"
+	keyTags        = "tags"
+	keyState       = "state"
+	keyReminder    = "reminder"
+	keyOwner       = "owner"
+	keyListID      = "listid"
+	keyVersion     = "version"
+	keyCreatedAt   = "createdat"
+	keyUpdatedAt   = "updatedat"
+	keyCompletedAt = "completedat"
+	keyDeletedAt   = "deletedat"
+	keyName        = "name"
+	keyMembers     = "members"
+	keySubject     = "members.subject"
+	keyToDoID      = "todoid"
+)
+
+// Use in create
+type todoInMongo struct {
+	Title       string
+	Description string
+	Tags        []string
+	Reminder    int64
+	State       string
+	Owner       string
+	ListID      string
+	Version     int64
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+	CompletedAt *time.Time
+	DeletedAt   *time.Time
+}
+
+// Use in read
+type todoInMongoWithID struct {
+	ID   primitive.ObjectID `bson:"_id"`
+	Todo todoInMongo        `bson:"inline"`
+}
+
+func newTodo(r *pb.ToDo) (t todoInMongo) {
+	t.Title = r.GetTitle()
+	t.Description = r.GetDescription()
+	t.Tags = r.GetTags()
+	if reminder := r.GetReminder(); reminder != nil {
+		t.Reminder = reminder.GetSeconds()
+	}
+	t.State = r.GetState().String()
+	t.Owner = r.GetOwner()
+	t.ListID = r.GetListId()
+	t.Version = r.GetVersion()
+	t.CreatedAt = toTime(r.GetCreatedAt())
+	t.UpdatedAt = toTime(r.GetUpdatedAt())
+	t.CompletedAt = toTime(r.GetCompletedAt())
+	t.DeletedAt = toTime(r.GetDeletedAt())
+	return
+}
+
+func (t *todoInMongoWithID) todo() *pb.ToDo {
+	return &pb.ToDo{
+		Id:          t.ID.Hex(),
+		Title:       t.Todo.Title,
+		Description: t.Todo.Description,
+		Tags:        t.Todo.Tags,
+		State:       pb.ToDo_State(pb.ToDo_State_value[t.Todo.State]),
+		Reminder:    &timestamp.Timestamp{Seconds: t.Todo.Reminder},
+		Owner:       t.Todo.Owner,
+		ListId:      t.Todo.ListID,
+		Version:     t.Todo.Version,
+		CreatedAt:   toTimestamp(t.Todo.CreatedAt),
+		UpdatedAt:   toTimestamp(t.Todo.UpdatedAt),
+		CompletedAt: toTimestamp(t.Todo.CompletedAt),
+		DeletedAt:   toTimestamp(t.Todo.DeletedAt),
+	}
+}
+
+// toTime returns the date of the timestamp stored by mongo, nil if it is missing
+func toTime(t *timestamp.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := time.Unix(t.GetSeconds(), int64(t.GetNanos())).UTC()
+	return &value
+}
+
+// toTimestamp returns the timestamp of the date stored by mongo, nil if it is missing
+func toTimestamp(t *time.Time) *timestamp.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return &timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
+
+// Use in the histories
+type historyInMongo struct {
+	ToDoID    string
+	Type      string
+	Actor     string
+	Time      *time.Time
+	RequestID string
+	Version   int64
+	Changes   []changeInMongo
+	Owner     string
+	ListID    string
+}
+
+type changeInMongo struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+func newHistoryInMongo(r *pb.HistoryRecord) historyInMongo {
+	h := historyInMongo{
+		ToDoID:    r.GetToDoId(),
+		Type:      r.GetType().String(),
+		Actor:     r.GetActor(),
+		Time:      toTime(r.GetTime()),
+		RequestID: r.GetRequestId(),
+		Version:   r.GetVersion(),
+		Owner:     r.GetOwner(),
+		ListID:    r.GetListId(),
+	}
+	for _, change := range r.GetChanges() {
+		h.Changes = append(h.Changes, changeInMongo{Field: change.GetField(), OldValue: change.GetOldValue(), NewValue: change.GetNewValue()})
+	}
+	return h
+}
+
+func (h *historyInMongo) record() *pb.HistoryRecord {
+	r := &pb.HistoryRecord{
+		ToDoId:    h.ToDoID,
+		Type:      pb.Event_Type(pb.Event_Type_value[h.Type]),
+		Actor:     h.Actor,
+		Time:      toTimestamp(h.Time),
+		RequestId: h.RequestID,
+		Version:   h.Version,
+		Owner:     h.Owner,
+		ListId:    h.ListID,
+	}
+	for _, change := range h.Changes {
+		r.Changes = append(r.Changes, &pb.HistoryRecord_Change{Field: change.Field, OldValue: change.OldValue, NewValue: change.NewValue})
+	}
+	return r
+}
+
+// Use in the lists
+type listInMongo struct {
+	Name    string
+	Owner   string
+	Members []memberInMongo
+}
+
+type memberInMongo struct {
+	Subject string
+	Role    string
+}
+
+type listInMongoWithID struct {
+	ID   primitive.ObjectID `bson:"_id"`
+	List listInMongo        `bson:"inline"`
+}
+
+func newListInMongo(l *pb.List) (t listInMongo) {
+	t.Name = l.GetName()
+	t.Owner = l.GetOwner()
+	t.Members = []memberInMongo{}
+	for _, member := range l.GetMembers() {
+		t.Members = append(t.Members, memberInMongo{Subject: member.GetSubject(), Role: member.GetRole().String()})
+	}
+	return
+}
+
+func (t *listInMongoWithID) list() *pb.List {
+	list := &pb.List{Id: t.ID.Hex(), Name: t.List.Name, Owner: t.List.Owner}
+	for _, member := range t.List.Members {
+		list.Members = append(list.Members, &pb.List_Member{
+			Subject: member.Subject,
+			Role:    pb.List_Role(pb.List_Role_value[member.Role]),
+		})
+	}
+	return list
+}
+
+// Mongo stores the todos, the lists and the histories in three mongo collections
+type Mongo struct {
+	client            *mongo.Client
+	todoCollection    *mongo.Collection
+	listCollection    *mongo.Collection
+	historyCollection *mongo.Collection
+	// shared the client belongs to another store, Close keeps it connected
+	shared bool
+}
+
+// validate the implementation
+var _ Store = &Mongo{}
+
+// NewMongo connects to the mongo behind url
+func NewMongo(ctx context.Context, url string) (*Mongo, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+	if err != nil {
+		return nil, err
+	}
+	return newMongo(ctx, client, database, ""), nil
+}
+
+// The isolations of the tenants in mongo
+const (
+	// IsolationDatabase the collections of a tenant are in the database challenge_<tenant>
+	IsolationDatabase = "database"
+	// IsolationCollection the collections of a tenant are named <tenant>_todo, <tenant>_list and <tenant>_history in the database challenge
+	IsolationCollection = "collection"
+)
+
+// Tenant returns the store of the tenant on the same connection, its Close keeps the connection.
+// The store without tenant uses the collections of NewMongo.
+func (m *Mongo) Tenant(ctx context.Context, tenant string, isolation string) (*Mongo, error) {
+	db, prefix := database, ""
+	switch {
+	case tenant == "":
+	case isolation == IsolationDatabase:
+		db = database + "_" + tenant
+	case isolation == IsolationCollection:
+		prefix = tenant + "_"
+	default:
+		return nil, fmt.Errorf("unknown isolation %q", isolation)
+	}
+	t := newMongo(ctx, m.client, db, prefix)
+	t.shared = true
+	return t, nil
+}
+
+// newMongo returns the store of the collections prefixed by prefix in the database db
+func newMongo(ctx context.Context, client *mongo.Client, db string, prefix string) *Mongo {
+	m := &Mongo{
+		client:            client,
+		todoCollection:    client.Database(db).Collection(prefix + collection),
+		listCollection:    client.Database(db).Collection(prefix + listCollection),
+		historyCollection: client.Database(db).Collection(prefix + historyCollection),
+	}
+	// the store works without the indexes, it is only slower
+	if err := m.createIndexes(ctx); err != nil {
+		log.WithError(err).Warn("the indexes of the todos and the lists are not created")
+	}
+	return m
+}
+
+// IndexCreationTimeout the maximum duration of the creation of the indexes
+const IndexCreationTimeout = 10 * time.Second
+
+// MaxQueryTime the maximum duration of a search in mongo, the server stops it after (maxTimeMS)
+const MaxQueryTime = 10 * time.Second
+
+// createIndexes creates the indexes on the owner and the list of the todos, on the owner and the members of the lists
+// and on the todo of the records, the searches of a user filter on them, and the text index of the full text searches
+func (m *Mongo) createIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, IndexCreationTimeout)
+	defer cancel()
+	_, err := m.todoCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: keyOwner, Value: 1}}},
+		{Keys: bson.D{{Key: keyListID, Value: 1}}},
+		{Keys: bson.D{{Key: keyDeletedAt, Value: 1}}},
+		// no language: the words are not stemmed like in the other stores
+		{Keys: bson.D{{Key: keyTitle, Value: "text"}, {Key: keyDescription, Value: "text"}}, Options: options.Index().SetDefaultLanguage("none")},
+	})
+	if err != nil {
+		return wrap(err)
+	}
+	_, err = m.listCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: keyOwner, Value: 1}}},
+		{Keys: bson.D{{Key: keySubject, Value: 1}}},
+	})
+	if err != nil {
+		return wrap(err)
+	}
+	_, err = m.historyCollection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: keyToDoID, Value: 1}}})
+	return wrap(err)
+}
+
+func objectID(id string) (primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return oid, ErrInvalidID
+	}
+	return oid, nil
+}
+
+// insertedID returns the hexadecimal of the ID of an inserted document, ErrUnexpectedID if it isn't an ObjectID
+func insertedID(id interface{}) (string, error) {
+	if oid, ok := id.(primitive.ObjectID); ok {
+		return oid.Hex(), nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnexpectedID, id)
+}
+
+// Create a todo
+func (m *Mongo) Create(ctx context.Context, todo *pb.ToDo) (string, error) {
+	result, err := m.todoCollection.InsertOne(ctx, newTodo(newToDo("", todo, now())))
+	if err != nil {
+		return "", wrap(err)
+	}
+	return insertedID(result.InsertedID)
+}
+
+// CreateMany creates todos with InsertMany, in a transaction if atomic is set
+func (m *Mongo) CreateMany(ctx context.Context, todos []*pb.ToDo, atomic bool) ([]Result, error) {
+	var results []Result
+	err := m.run(ctx, atomic, func(ctx context.Context) error {
+		t := now()
+		results = make([]Result, len(todos))
+		documents := make([]interface{}, len(todos))
+		for i, todo := range todos {
+			oid := primitive.NewObjectID()
+			documents[i] = todoInMongoWithID{ID: oid, Todo: newTodo(newToDo(oid.Hex(), todo, t))}
+			results[i] = Result{ID: oid.Hex(), Count: 1}
+		}
+		// an atomic batch stops on the first failure, the others go on
+		_, err := m.todoCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(atomic))
+		if e, ok := err.(mongo.BulkWriteException); ok && e.WriteConcernError == nil {
+			for _, failure := range e.WriteErrors {
+				results[failure.Index] = Result{Err: failure}
+			}
+			if abort(results, atomic) {
+				return ErrBatchAborted
+			}
+			return nil
+		}
+		return wrap(err)
+	})
+	return batchResults(results, err)
+}
+
+// run calls fn in a transaction if atomic is set, the transaction is aborted when fn fails.
+// The transactions need a replica set, ErrAtomicUnsupported is returned on a standalone server.
+func (m *Mongo) run(ctx context.Context, atomic bool, fn func(ctx context.Context) error) error {
+	if !atomic {
+		return fn(ctx)
+	}
+	session, err := m.client.StartSession()
+	if err != nil {
+		return wrap(err)
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return wrap(err)
+}
+
+// batchResults returns the results of a batch run by run, an aborted batch has its results
+func batchResults(results []Result, err error) ([]Result, error) {
+	if errors.Is(err, ErrBatchAborted) {
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// trashFilter returns the filter of the todo oid in the trash if trashed is set or else out of it,
+// the todos out of the trash have no deletedat
+func trashFilter(oid primitive.ObjectID, trashed bool) bson.D {
+	if trashed {
+		return bson.D{{Key: keyID, Value: oid}, {Key: keyDeletedAt, Value: bson.D{{Key: "$ne", Value: nil}}}}
+	}
+	return bson.D{{Key: keyID, Value: oid}, {Key: keyDeletedAt, Value: nil}}
+}
+
+// Read a todo
+func (m *Mongo) Read(ctx context.Context, id string) (*pb.ToDo, error) {
+	return m.read(ctx, id, false)
+}
+
+// read returns the todo in the trash if trashed is set or else out of it
+func (m *Mongo) read(ctx context.Context, id string, trashed bool) (*pb.ToDo, error) {
+	oid, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	result := m.todoCollection.FindOne(ctx, trashFilter(oid, trashed))
+
+	objMongo := &todoInMongoWithID{}
+	if err := result.Decode(objMongo); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+		return nil, wrap(err)
+	}
+	return objMongo.todo(), nil
+}
+
+// Update a todo
+func (m *Mongo) Update(ctx context.Context, todo *pb.ToDo, paths []string, version int64) (int64, error) {
+	return retryWrite(version, func() (int64, error) { return m.update(ctx, todo, paths, version) })
+}
+
+// retryWrite calls write again while it fails with ErrVersionMismatch if the version requested is any, 0
+func retryWrite(version int64, write func() (int64, error)) (int64, error) {
+	for {
+		count, err := write()
+		if version != 0 || !errors.Is(err, ErrVersionMismatch) {
+			return count, err
+		}
+	}
+}
+
+// update reads the todo then updates it only if it still has the version read
+func (m *Mongo) update(ctx context.Context, todo *pb.ToDo, paths []string, version int64) (int64, error) {
+	current, err := m.Read(ctx, todo.GetId())
+	if err != nil {
+		return 0, err
+	}
+	if err := checkVersion(current, version); err != nil {
+		return 0, err
+	}
+	// the todo is compared like in the other stores, the version changes only with the todo
+	next := proto.Clone(current).(*pb.ToDo)
+	setFields(next, todo, paths)
+	if proto.Equal(current, next) {
+		return 0, nil
+	}
+	stampUpdated(current, next, now())
+
+	values := newTodo(next)
+	set := bson.D{{Key: keyUpdatedAt, Value: values.UpdatedAt}, {Key: keyCompletedAt, Value: values.CompletedAt}}
+	for _, path := range paths {
+		switch path {
+		case FieldTitle:
+			set = append(set, primitive.E{Key: path, Value: values.Title})
+		case FieldDescription:
+			set = append(set, primitive.E{Key: path, Value: values.Description})
+		case FieldTags:
+			set = append(set, primitive.E{Key: path, Value: values.Tags})
+		case FieldState:
+			set = append(set, primitive.E{Key: path, Value: values.State})
+		case FieldReminder:
+			set = append(set, primitive.E{Key: path, Value: values.Reminder})
+		case FieldListID:
+			set = append(set, primitive.E{Key: keyListID, Value: values.ListID})
+		}
+	}
+	oid, _ := objectID(todo.GetId())
+	result, err := m.todoCollection.UpdateOne(ctx, versionFilter(oid, current.GetVersion()), bson.D{
+		{Key: "$set", Value: set},
+		{Key: "$inc", Value: bson.D{{Key: keyVersion, Value: 1}}},
+	})
+	if err != nil {
+		return 0, wrap(err)
+	}
+	if result.MatchedCount == 0 {
+		return 0, m.missing(ctx, oid, false)
+	}
+	return result.ModifiedCount, nil
+}
+
+// versionFilter returns the filter of the todo out of the trash with the version, any version if 0
+func versionFilter(oid primitive.ObjectID, version int64) bson.D {
+	filter := trashFilter(oid, false)
+	if version != 0 {
+		filter = append(filter, bson.E{Key: keyVersion, Value: version})
+	}
+	return filter
+}
+
+// missing returns why no todo in the trash or out of it matches a filter with the version: ErrNotFound or ErrVersionMismatch
+func (m *Mongo) missing(ctx context.Context, oid primitive.ObjectID, trashed bool) error {
+	count, err := m.todoCollection.CountDocuments(ctx, trashFilter(oid, trashed))
+	if err != nil {
+		return wrap(err)
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return ErrVersionMismatch
+}
+
+// UpdateMany updates todos with BulkWrite
+func (m *Mongo) UpdateMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	return m.changeMany(ctx, changes, atomic, applyUpdate)
+}
+
+// DeleteMany moves todos to the trash with BulkWrite
+func (m *Mongo) DeleteMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	return m.changeMany(ctx, changes, atomic, applyTrash)
+}
+
+// changeMany reads the todos of the changes, applies them then replaces the todos with BulkWrite, in a transaction if atomic is set.
+// The todos are replaced only if they still have the version read, the changes of the others fail with ErrVersionMismatch.
+func (m *Mongo) changeMany(ctx context.Context, changes []Change, atomic bool,
+	apply func(*pb.ToDo, Change, *timestamp.Timestamp) (*pb.ToDo, error)) ([]Result, error) {
+	ids := make([]string, len(changes))
+	for i, c := range changes {
+		ids[i] = c.ToDo.GetId()
+	}
+
+	var results []Result
+	err := m.run(ctx, atomic, func(ctx context.Context) error {
+		read, err := m.readMany(ctx, ids)
+		if err != nil {
+			return err
+		}
+		get := func(id string) (*pb.ToDo, error) {
+			if _, err := objectID(id); err != nil {
+				return nil, err
+			}
+			todo, ok := read[id]
+			if !ok {
+				return nil, ErrNotFound
+			}
+			return todo, checkTrash(todo, false)
+		}
+		var changed map[string]*pb.ToDo
+		results, changed = applyMany(changes, atomic, get, apply)
+		if changed == nil {
+			return ErrBatchAborted
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		models := make([]mongo.WriteModel, 0, len(changed))
+		// item the index of the change of each model
+		item := make([]int, 0, len(changed))
+		for i, c := range changes {
+			todo, ok := changed[c.ToDo.GetId()]
+			if !ok || results[i].Err != nil {
+				continue
+			}
+			oid, _ := objectID(todo.GetId())
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(versionFilter(oid, read[todo.GetId()].GetVersion())).
+				SetReplacement(newTodo(todo)))
+			item = append(item, i)
+		}
+		result, err := m.todoCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		failed := 0
+		if e, ok := err.(mongo.BulkWriteException); ok && e.WriteConcernError == nil {
+			// the other writes of the unordered batch are applied
+			for _, failure := range e.WriteErrors {
+				results[item[failure.Index]] = Result{Err: failure}
+			}
+			failed = len(e.WriteErrors)
+		} else if err != nil {
+			return wrap(err)
+		}
+		if result.MatchedCount+int64(failed) < int64(len(models)) {
+			// some todos have changed since they were read
+			if err := m.conflicts(ctx, changes, results, changed); err != nil {
+				return err
+			}
+		}
+		if abort(results, atomic) {
+			return ErrBatchAborted
+		}
+		return nil
+	})
+	return batchResults(results, err)
+}
+
+// readMany returns the todos by ID in the trash or out of it, the invalid and missing IDs are skipped
+func (m *Mongo) readMany(ctx context.Context, ids []string) (map[string]*pb.ToDo, error) {
+	oids := []primitive.ObjectID{}
+	for _, id := range ids {
+		if oid, err := objectID(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	todos := map[string]*pb.ToDo{}
+	if len(oids) == 0 {
+		return todos, nil
+	}
+	cur, err := m.todoCollection.Find(ctx, bson.D{{Key: keyID, Value: bson.D{{Key: "$in", Value: oids}}}})
+	if err != nil {
+		return nil, wrap(err)
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var result todoInMongoWithID
+		if err := cur.Decode(&result); err != nil {
+			return nil, wrap(err)
+		}
+		todos[result.ID.Hex()] = result.todo()
+	}
+	return todos, wrap(cur.Err())
+}
+
+// conflicts fails with ErrVersionMismatch the changes of the todos which are not replaced by changed
+func (m *Mongo) conflicts(ctx context.Context, changes []Change, results []Result, changed map[string]*pb.ToDo) error {
+	ids := make([]string, 0, len(changed))
+	for id := range changed {
+		ids = append(ids, id)
+	}
+	stored, err := m.readMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i, c := range changes {
+		id := c.ToDo.GetId()
+		next, ok := changed[id]
+		if !ok || results[i].Err != nil {
+			continue
+		}
+		if todo := stored[id]; todo == nil || todo.GetVersion() != next.GetVersion() || !proto.Equal(todo.GetUpdatedAt(), next.GetUpdatedAt()) {
+			results[i] = Result{Err: ErrVersionMismatch}
+		}
+	}
+	return nil
+}
+
+// Delete moves a todo to the trash
+func (m *Mongo) Delete(ctx context.Context, id string, version int64) (int64, error) {
+	return retryWrite(version, func() (int64, error) {
+		current, err := m.Read(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		if err := checkVersion(current, version); err != nil {
+			return 0, err
+		}
+		return m.trash(ctx, current, true)
+	})
+}
+
+// trash moves the todo current to the trash or out of it if it has still the version read
+func (m *Mongo) trash(ctx context.Context, current *pb.ToDo, trashed bool) (int64, error) {
+	next := proto.Clone(current).(*pb.ToDo)
+	stampTrashed(current, next, trashed, now())
+	values := newTodo(next)
+	oid, _ := objectID(current.GetId())
+	filter := trashFilter(oid, !trashed)
+	if version := current.GetVersion(); version != 0 {
+		filter = append(filter, bson.E{Key: keyVersion, Value: version})
+	}
+	result, err := m.todoCollection.UpdateOne(ctx, filter, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: keyDeletedAt, Value: values.DeletedAt},
+			{Key: keyUpdatedAt, Value: values.UpdatedAt},
+			{Key: keyCompletedAt, Value: values.CompletedAt},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: keyVersion, Value: 1}}},
+	})
+	if err != nil {
+		return 0, wrap(err)
+	}
+	if result.MatchedCount == 0 {
+		return 0, m.missing(ctx, oid, !trashed)
+	}
+	return result.ModifiedCount, nil
+}
+
+// ReadTrashed reads a todo in the trash
+func (m *Mongo) ReadTrashed(ctx context.Context, id string) (*pb.ToDo, error) {
+	return m.read(ctx, id, true)
+}
+
+// Restore takes a todo out of the trash
+func (m *Mongo) Restore(ctx context.Context, id string) (int64, error) {
+	return retryWrite(0, func() (int64, error) {
+		current, err := m.ReadTrashed(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		return m.trash(ctx, current, false)
+	})
+}
+
+// Purge removes a todo from the trash
+func (m *Mongo) Purge(ctx context.Context, id string, version int64) (int64, error) {
+	oid, err := objectID(id)
+	if err != nil {
+		return 0, err
+	}
+	filter := trashFilter(oid, true)
+	if version != 0 {
+		filter = append(filter, bson.E{Key: keyVersion, Value: version})
+	}
+	result, err := m.todoCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, wrap(err)
+	}
+	if result.DeletedCount == 0 {
+		return 0, m.missing(ctx, oid, true)
+	}
+	return result.DeletedCount, nil
+}
+
+// sortKeys the keys of the orders
+var sortKeys = map[string]string{
+	OrderCreated:   keyID,
+	OrderUpdated:   keyUpdatedAt,
+	OrderCompleted: keyCompletedAt,
+	OrderDeleted:   keyDeletedAt,
+	OrderReminder:  keyReminder,
+	OrderTitle:     keyTitle,
+	OrderState:     keyState,
+}
+
+// mongoFilter returns the mongo filter of the request
+func mongoFilter(r *pb.SearchRequest) bson.D {
+	// the todos out of the trash have no deletedat, the range of deletedAt is in the same condition
+	trash := bson.E{Key: "$eq", Value: nil}
+	if r.GetTrashed() {
+		trash.Key = "$ne"
+	}
+	filter := bson.D{{Key: keyDeletedAt, Value: append(bson.D{trash}, bounds(r.GetDeletedAt())...)}}
+	if owner := r.GetOwner(); owner != "" {
+		filter = append(filter, bson.E{Key: keyOwner, Value: owner})
+	}
+	if listID := r.GetListId(); listID != "" {
+		filter = append(filter, bson.E{Key: keyListID, Value: listID})
+	}
+
+	if pattern := r.GetPattern(); pattern != "" {
+		filter = append(filter, patternFilter(r.GetMatchMode(), pattern))
+	}
+
+	if states := r.GetStates(); len(states) > 0 {
+		filter = append(filter, bson.E{Key: keyState, Value: bson.D{{Key: "$in", Value: toString(states)}}})
+	}
+
+	if tags := r.GetTags(); len(tags) > 0 {
+		filter = append(filter, bson.E{Key: keyTags, Value: bson.D{{Key: "$all", Value: tags}}})
+	}
+
+	filter = appendRange(filter, keyCreatedAt, r.GetCreatedAt())
+	filter = appendRange(filter, keyUpdatedAt, r.GetUpdatedAt())
+	filter = appendRange(filter, keyCompletedAt, r.GetCompletedAt())
+	return filter
+}
+
+// appendRange appends the bounds of the range on the key to the filter if it has any, the missing dates are in no range with a bound
+func appendRange(filter bson.D, key string, r *pb.TimeRange) bson.D {
+	if b := bounds(r); len(b) > 0 {
+		return append(filter, bson.E{Key: key, Value: b})
+	}
+	return filter
+}
+
+// bounds returns the conditions of the bounds of the range, empty without bound
+func bounds(r *pb.TimeRange) bson.D {
+	result := bson.D{}
+	if after := r.GetAfter(); after != nil {
+		result = append(result, bson.E{Key: "$gte", Value: toTime(after)})
+	}
+	if before := r.GetBefore(); before != nil {
+		result = append(result, bson.E{Key: "$lt", Value: toTime(before)})
+	}
+	return result
+}
+
+// patternFilter returns the mongo filter of the pattern in the mode, the pattern is a regex only in REGEX
+func patternFilter(mode pb.SearchRequest_MatchMode, pattern string) bson.E {
+	switch mode {
+	case pb.SearchRequest_PREFIX:
+		return bson.E{Key: keyDescription, Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(pattern)}}
+	case pb.SearchRequest_REGEX:
+		return bson.E{Key: keyDescription, Value: primitive.Regex{Pattern: pattern}}
+	case pb.SearchRequest_FULLTEXT:
+		// the quoted words are all required
+		words := Words(pattern)
+		for i, word := range words {
+			words[i] = `"` + word + `"`
+		}
+		return bson.E{Key: "$text", Value: bson.D{{Key: "$search", Value: strings.Join(words, " ")}}}
+	default:
+		return bson.E{Key: keyDescription, Value: primitive.Regex{Pattern: regexp.QuoteMeta(pattern)}}
+	}
+}
+
+// find returns the cursor on the todos of the query
+func (m *Mongo) find(ctx context.Context, filter bson.D, q Query) (*mongo.Cursor, error) {
+	direction := 1
+	if q.Descending {
+		direction = -1
+	}
+	sortKey, ok := sortKeys[q.OrderBy]
+	if !ok {
+		sortKey = keyID
+	}
+	opts := options.Find().SetMaxTime(MaxQueryTime).SetSkip(q.Offset).SetSort(bson.D{{Key: sortKey, Value: direction}, {Key: keyID, Value: direction}})
+	if q.Limit > 0 {
+		opts.SetLimit(q.Limit)
+	}
+	cur, err := m.todoCollection.Find(ctx, filter, opts)
+	return cur, wrap(err)
+}
+
+// Search todos
+func (m *Mongo) Search(ctx context.Context, q Query) ([]*pb.ToDo, int64, error) {
+	filter := mongoFilter(q.Request)
+	log.Info(filter)
+	total, err := m.todoCollection.CountDocuments(ctx, filter, options.Count().SetMaxTime(MaxQueryTime))
+	if err != nil {
+		return nil, 0, wrap(err)
+	}
+
+	elements := []*pb.ToDo{}
+	err = m.iterate(ctx, filter, q, func(todo *pb.ToDo) error {
+		elements = append(elements, todo)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return elements, total, nil
+}
+
+// Iterate todos, the todos are decoded one by one from the cursor
+func (m *Mongo) Iterate(ctx context.Context, q Query, fn func(*pb.ToDo) error) error {
+	return m.iterate(ctx, mongoFilter(q.Request), q, fn)
+}
+
+func (m *Mongo) iterate(ctx context.Context, filter bson.D, q Query, fn func(*pb.ToDo) error) error {
+	cur, err := m.find(ctx, filter, q)
+	if err != nil {
+		return err
+	}
+
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var result todoInMongoWithID
+		if err := cur.Decode(&result); err != nil {
+			return wrap(err)
+		}
+		log.Debug(result)
+		if err := fn(result.todo()); err != nil {
+			return err
+		}
+	}
+	return wrap(cur.Err())
+}
+
+// CreateList creates a list
+func (m *Mongo) CreateList(ctx context.Context, list *pb.List) (string, error) {
+	result, err := m.listCollection.InsertOne(ctx, newListInMongo(list))
+	if err != nil {
+		return "", wrap(err)
+	}
+	return insertedID(result.InsertedID)
+}
+
+// ReadList reads a list
+func (m *Mongo) ReadList(ctx context.Context, id string) (*pb.List, error) {
+	oid, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	result := &listInMongoWithID{}
+	if err := m.listCollection.FindOne(ctx, bson.M{keyID: oid}).Decode(result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrListNotFound
+		}
+		return nil, wrap(err)
+	}
+	return result.list(), nil
+}
+
+// UpdateList updates a list
+func (m *Mongo) UpdateList(ctx context.Context, list *pb.List) (int64, error) {
+	oid, err := objectID(list.GetId())
+	if err != nil {
+		return 0, err
+	}
+	values := newListInMongo(list)
+	set := bson.D{{Key: keyName, Value: values.Name}, {Key: keyMembers, Value: values.Members}}
+	result, err := m.listCollection.UpdateOne(ctx, bson.M{keyID: oid}, bson.D{{Key: "$set", Value: set}})
+	if err != nil {
+		return 0, wrap(err)
+	}
+	if result.MatchedCount == 0 {
+		return 0, ErrListNotFound
+	}
+	return result.ModifiedCount, nil
+}
+
+// DeleteList deletes a list
+func (m *Mongo) DeleteList(ctx context.Context, id string) (int64, error) {
+	oid, err := objectID(id)
+	if err != nil {
+		return 0, err
+	}
+	result, err := m.listCollection.DeleteOne(ctx, bson.M{keyID: oid})
+	if err != nil {
+		return 0, wrap(err)
+	}
+	if result.DeletedCount == 0 {
+		return 0, ErrListNotFound
+	}
+	return result.DeletedCount, nil
+}
+
+// SearchLists searches the lists of a subject
+func (m *Mongo) SearchLists(ctx context.Context, subject string) ([]*pb.List, error) {
+	filter := bson.D{}
+	if subject != "" {
+		filter = bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: keyOwner, Value: subject}},
+			bson.D{{Key: keySubject, Value: subject}},
+		}}}
+	}
+	cur, err := m.listCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: keyID, Value: 1}}))
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	defer cur.Close(ctx)
+	lists := []*pb.List{}
+	for cur.Next(ctx) {
+		var result listInMongoWithID
+		if err := cur.Decode(&result); err != nil {
+			return nil, wrap(err)
+		}
+		lists = append(lists, result.list())
+	}
+	return lists, wrap(cur.Err())
+}
+
+// AppendHistory appends a record to the history of its todo
+func (m *Mongo) AppendHistory(ctx context.Context, record *pb.HistoryRecord) error {
+	if _, err := objectID(record.GetToDoId()); err != nil {
+		return err
+	}
+	_, err := m.historyCollection.InsertOne(ctx, newHistoryInMongo(record))
+	return wrap(err)
+}
+
+// History returns the records of a todo in the order of insertion
+func (m *Mongo) History(ctx context.Context, id string) ([]*pb.HistoryRecord, error) {
+	if _, err := objectID(id); err != nil {
+		return nil, err
+	}
+	cur, err := m.historyCollection.Find(ctx, bson.M{keyToDoID: id}, options.Find().SetSort(bson.D{{Key: keyID, Value: 1}}))
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	defer cur.Close(ctx)
+	records := []*pb.HistoryRecord{}
+	for cur.Next(ctx) {
+		var result historyInMongo
+		if err := cur.Decode(&result); err != nil {
+			return nil, wrap(err)
+		}
+		records = append(records, result.record())
+	}
+	return records, wrap(cur.Err())
+}
+
+// Ping the mongo
+func (m *Mongo) Ping(ctx context.Context) error {
+	return wrap(m.client.Ping(ctx, nil))
+}
+
+// Close disconnects the client, the stores of the tenants keep it
+func (m *Mongo) Close() error {
+	if m.shared {
+		return nil
+	}
+	return m.client.Disconnect(context.Background())
+}
+
+// mongo codes of an invalid regular expression and of a query stopped by maxTimeMS
+const (
+	codeBadValue         = 2
+	codeRegexError       = 51091
+	codeMaxTimeExpired   = 50
+	codeIllegalOperation = 20
+)
+
+// wrap converts the errors of the driver in errors of the store
+func wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(mongo.CommandError); ok {
+		if e.HasErrorLabel("NetworkError") {
+			return fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		if e.Code == codeBadValue || e.Code == codeRegexError {
+			return fmt.Errorf("%w: %s", ErrInvalidPattern, e.Message)
+		}
+		if e.Code == codeIllegalOperation {
+			// the store runs no other illegal operation than a transaction on a standalone server
+			return fmt.Errorf("%w: %s", ErrAtomicUnsupported, e.Message)
+		}
+		if e.Code == codeMaxTimeExpired {
+			return fmt.Errorf("%w: %s", context.DeadlineExceeded, e.Message)
+		}
+		return err
+	}
+	if err == mongo.ErrClientDisconnected || strings.HasPrefix(err.Error(), "server selection error") {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return err
+}
+
+func toString(values []pb.ToDo_State) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.String()
+	}
+	return result
+}
@@ -0,0 +1,333 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// quotaExceeded counts the creations refused by the quotas
+var quotaExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "todod_tenant_quota_exceeded_total",
+	Help: "The number of creations refused by the quotas by tenant and resource.",
+}, []string{"tenant", "resource"})
+
+func init() {
+	prometheus.MustRegister(quotaExceeded)
+}
+
+// Quota the limits of each tenant, 0 means no limit.
+// The counts are checked before the creations, concurrent creations can exceed them slightly.
+type Quota struct {
+	// MaxToDos the maximum number of todos
+	MaxToDos int64
+	// MaxLists the maximum number of lists
+	MaxLists int64
+}
+
+// Router sends the calls to the store of the tenant of the context, the store without tenant
+// serves the calls without tenant like the health checks. The stores are opened on their first call.
+type Router struct {
+	open   func(tenant string) (Store, error)
+	quota  Quota
+	shared io.Closer
+
+	mu     sync.Mutex
+	stores map[string]Store
+}
+
+// validate the implementation
+var _ Store = &Router{}
+
+// NewRouter creates a router which opens the store of a tenant with open,
+// shared is closed after the stores, ex: the connection they share, nil if nothing.
+func NewRouter(open func(tenant string) (Store, error), quota Quota, shared io.Closer) *Router {
+	return &Router{
+		open:   open,
+		quota:  quota,
+		shared: shared,
+		stores: make(map[string]Store),
+	}
+}
+
+// store returns the store of the tenant of ctx
+func (r *Router) store(ctx context.Context) (Store, string, error) {
+	t := tenant.FromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.stores[t]; ok {
+		return s, t, nil
+	}
+	s, err := r.open(t)
+	if err != nil {
+		return nil, t, fmt.Errorf("%w: tenant %q: %v", ErrUnavailable, t, err)
+	}
+	r.stores[t] = s
+	return s, t, nil
+}
+
+// checkToDos returns ErrQuotaExceeded if the tenant t has as many todos as the quota, the todos in the trash don't count
+func (r *Router) checkToDos(ctx context.Context, s Store, t string) error {
+	if r.quota.MaxToDos <= 0 {
+		return nil
+	}
+	_, total, err := s.Search(ctx, Query{Request: &pb.SearchRequest{}, Limit: 1})
+	if err != nil {
+		return err
+	}
+	if total >= r.quota.MaxToDos {
+		quotaExceeded.WithLabelValues(t, "todos").Inc()
+		return fmt.Errorf("%w: the tenant %q has %d todos", ErrQuotaExceeded, t, r.quota.MaxToDos)
+	}
+	return nil
+}
+
+// Create a todo if the tenant has less todos than the quota, the todos in the trash don't count
+func (r *Router) Create(ctx context.Context, todo *pb.ToDo) (string, error) {
+	s, t, err := r.store(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := r.checkToDos(ctx, s, t); err != nil {
+		return "", err
+	}
+	return s.Create(ctx, todo)
+}
+
+// CreateMany creates the todos within the quota of the tenant, the todos over the quota fail with ErrQuotaExceeded
+func (r *Router) CreateMany(ctx context.Context, todos []*pb.ToDo, atomic bool) ([]Result, error) {
+	s, t, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	room := int64(len(todos))
+	if r.quota.MaxToDos > 0 {
+		_, total, err := s.Search(ctx, Query{Request: &pb.SearchRequest{}, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if left := r.quota.MaxToDos - total; left < room {
+			room = left
+		}
+	}
+	if room < 0 {
+		room = 0
+	}
+	if room == int64(len(todos)) {
+		return s.CreateMany(ctx, todos, atomic)
+	}
+
+	quotaExceeded.WithLabelValues(t, "todos").Inc()
+	results := make([]Result, len(todos))
+	for i := room; i < int64(len(todos)); i++ {
+		results[i].Err = fmt.Errorf("%w: the tenant %q has %d todos", ErrQuotaExceeded, t, r.quota.MaxToDos)
+	}
+	if abort(results, atomic) || room == 0 {
+		return results, nil
+	}
+	created, err := s.CreateMany(ctx, todos[:room], atomic)
+	if err != nil {
+		return nil, err
+	}
+	copy(results, created)
+	return results, nil
+}
+
+// Read a todo
+func (r *Router) Read(ctx context.Context, id string) (*pb.ToDo, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.Read(ctx, id)
+}
+
+// Update a todo
+func (r *Router) Update(ctx context.Context, todo *pb.ToDo, paths []string, version int64) (int64, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.Update(ctx, todo, paths, version)
+}
+
+// Delete moves a todo to the trash
+func (r *Router) Delete(ctx context.Context, id string, version int64) (int64, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.Delete(ctx, id, version)
+}
+
+// UpdateMany updates todos
+func (r *Router) UpdateMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.UpdateMany(ctx, changes, atomic)
+}
+
+// DeleteMany moves todos to the trash
+func (r *Router) DeleteMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.DeleteMany(ctx, changes, atomic)
+}
+
+// ReadTrashed reads a todo in the trash
+func (r *Router) ReadTrashed(ctx context.Context, id string) (*pb.ToDo, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ReadTrashed(ctx, id)
+}
+
+// Restore takes a todo out of the trash if the tenant has less todos than the quota, like Create
+func (r *Router) Restore(ctx context.Context, id string) (int64, error) {
+	s, t, err := r.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.checkToDos(ctx, s, t); err != nil {
+		return 0, err
+	}
+	return s.Restore(ctx, id)
+}
+
+// Purge removes a todo from the trash
+func (r *Router) Purge(ctx context.Context, id string, version int64) (int64, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.Purge(ctx, id, version)
+}
+
+// Search todos
+func (r *Router) Search(ctx context.Context, q Query) ([]*pb.ToDo, int64, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.Search(ctx, q)
+}
+
+// Iterate todos
+func (r *Router) Iterate(ctx context.Context, q Query, fn func(*pb.ToDo) error) error {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.Iterate(ctx, q, fn)
+}
+
+// CreateList creates a list if the tenant has less lists than the quota
+func (r *Router) CreateList(ctx context.Context, list *pb.List) (string, error) {
+	s, t, err := r.store(ctx)
+	if err != nil {
+		return "", err
+	}
+	if r.quota.MaxLists > 0 {
+		lists, err := s.SearchLists(ctx, "")
+		if err != nil {
+			return "", err
+		}
+		if int64(len(lists)) >= r.quota.MaxLists {
+			quotaExceeded.WithLabelValues(t, "lists").Inc()
+			return "", fmt.Errorf("%w: the tenant %q has %d lists", ErrQuotaExceeded, t, r.quota.MaxLists)
+		}
+	}
+	return s.CreateList(ctx, list)
+}
+
+// ReadList reads a list
+func (r *Router) ReadList(ctx context.Context, id string) (*pb.List, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ReadList(ctx, id)
+}
+
+// UpdateList updates a list
+func (r *Router) UpdateList(ctx context.Context, list *pb.List) (int64, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.UpdateList(ctx, list)
+}
+
+// DeleteList deletes a list
+func (r *Router) DeleteList(ctx context.Context, id string) (int64, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.DeleteList(ctx, id)
+}
+
+// SearchLists searches the lists of a subject
+func (r *Router) SearchLists(ctx context.Context, subject string) ([]*pb.List, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.SearchLists(ctx, subject)
+}
+
+// AppendHistory appends a record to the history of a todo
+func (r *Router) AppendHistory(ctx context.Context, record *pb.HistoryRecord) error {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.AppendHistory(ctx, record)
+}
+
+// History returns the history of a todo
+func (r *Router) History(ctx context.Context, id string) ([]*pb.HistoryRecord, error) {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.History(ctx, id)
+}
+
+// Ping checks the store of the tenant of ctx
+func (r *Router) Ping(ctx context.Context) error {
+	s, _, err := r.store(ctx)
+	if err != nil {
+		return err
+	}
+	return s.Ping(ctx)
+}
+
+// Close closes the opened stores then what they share, it returns the first error
+func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var first error
+	for t, s := range r.stores {
+		if err := s.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(r.stores, t)
+	}
+	if r.shared != nil {
+		if err := r.shared.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,130 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/sjeandeaux/todo/pkg/store"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// closer counts the calls of Close
+type closer struct {
+	closed int
+}
+
+func (c *closer) Close() error {
+	c.closed++
+	return nil
+}
+
+var _ = Describe("Router", func() {
+
+	var (
+		router *Router
+		opened []string
+		shared *closer
+		acme   context.Context
+		globex context.Context
+	)
+
+	BeforeEach(func() {
+		opened = nil
+		shared = &closer{}
+		router = NewRouter(func(t string) (Store, error) {
+			opened = append(opened, t)
+			return NewMemory(), nil
+		}, Quota{MaxToDos: 2, MaxLists: 1}, shared)
+		acme = tenant.NewContext(context.Background(), "acme")
+		globex = tenant.NewContext(context.Background(), "globex")
+	})
+
+	AfterEach(func() {
+		router.Close()
+	})
+
+	Context("As a store", func() {
+		contract(func() Store { return NewRouter(func(string) (Store, error) { return NewMemory(), nil }, Quota{}, nil) })
+	})
+
+	It("should isolate the tenants", func() {
+		id, err := router.Create(acme, &pb.ToDo{Title: "acme"})
+		Ω(err).NotTo(HaveOccurred())
+
+		_, err = router.Read(globex, id)
+		Ω(err).Should(Equal(ErrNotFound))
+		todos, _, err := router.Search(globex, Query{Request: &pb.SearchRequest{}})
+		Ω(err).NotTo(HaveOccurred())
+		Ω(todos).Should(BeEmpty())
+		Ω(router.Read(acme, id)).ShouldNot(BeNil())
+		Ω(opened).Should(Equal([]string{"acme", "globex"}))
+	})
+
+	It("should refuse the todos and the lists over the quota of the tenant", func() {
+		for i := 0; i < 2; i++ {
+			_, err := router.Create(acme, &pb.ToDo{Title: "acme"})
+			Ω(err).NotTo(HaveOccurred())
+		}
+		_, err := router.Create(acme, &pb.ToDo{Title: "acme"})
+		Ω(errors.Is(err, ErrQuotaExceeded)).Should(BeTrue())
+		_, err = router.Create(globex, &pb.ToDo{Title: "globex"})
+		Ω(err).NotTo(HaveOccurred())
+
+		_, err = router.CreateList(acme, &pb.List{Name: "acme"})
+		Ω(err).NotTo(HaveOccurred())
+		_, err = router.CreateList(acme, &pb.List{Name: "acme"})
+		Ω(errors.Is(err, ErrQuotaExceeded)).Should(BeTrue())
+	})
+
+	It("should restore the todos within the quota of the tenant", func() {
+		trashed, err := router.Create(acme, &pb.ToDo{Title: "trashed"})
+		Ω(err).NotTo(HaveOccurred())
+		Ω(router.Delete(acme, trashed, 0)).Should(Equal(int64(1)))
+		for i := 0; i < 2; i++ {
+			_, err := router.Create(acme, &pb.ToDo{Title: "acme"})
+			Ω(err).NotTo(HaveOccurred())
+		}
+
+		_, err = router.Restore(acme, trashed)
+		Ω(errors.Is(err, ErrQuotaExceeded)).Should(BeTrue())
+		_, total, err := router.Search(acme, Query{Request: &pb.SearchRequest{Trashed: true}, Limit: 1})
+		Ω(err).NotTo(HaveOccurred())
+		Ω(total).Should(Equal(int64(1)))
+	})
+
+	It("should create the batches within the quota of the tenant", func() {
+		todos := []*pb.ToDo{{Title: "one"}, {Title: "two"}, {Title: "three"}}
+		results, err := router.CreateMany(acme, todos, true)
+		Ω(err).NotTo(HaveOccurred())
+		Ω(results[0].Err).Should(Equal(ErrBatchAborted))
+		Ω(errors.Is(results[2].Err, ErrQuotaExceeded)).Should(BeTrue())
+
+		results, err = router.CreateMany(acme, todos, false)
+		Ω(err).NotTo(HaveOccurred())
+		Ω(results[1].Err).NotTo(HaveOccurred())
+		Ω(router.Read(acme, results[1].ID)).ShouldNot(BeNil())
+		Ω(errors.Is(results[2].Err, ErrQuotaExceeded)).Should(BeTrue())
+	})
+
+	It("should fail when the store of the tenant can't be opened", func() {
+		router = NewRouter(func(string) (Store, error) { return nil, errors.New("no file") }, Quota{}, nil)
+		_, err := router.Read(acme, "5dc2d3d4aba443c197307ea2")
+		Ω(errors.Is(err, ErrUnavailable)).Should(BeTrue())
+	})
+
+	It("should close the stores then what they share", func() {
+		Ω(router.Ping(acme)).Should(Succeed())
+		Ω(router.Close()).Should(Succeed())
+		Ω(shared.closed).Should(Equal(1))
+	})
+
+	It("should name the bolt file of a tenant", func() {
+		Ω(BoltFile("data/todo.db", "acme")).Should(Equal("data/todo.acme.db"))
+		Ω(BoltFile("todo", "acme")).Should(Equal("todo.acme"))
+		Ω(BoltFile("todo.db", "")).Should(Equal("todo.db"))
+	})
+})
@@ -0,0 +1,452 @@
+// Package store persists the todos behind the ToDoService, the Router isolates the tenants in their own stores.
+package store
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+var (
+	// ErrNotFound is returned when the todo doesn't exist.
+	ErrNotFound = errors.New("todo not found")
+	// ErrInvalidID is returned when the ID doesn't have the expected format.
+	ErrInvalidID = errors.New("invalid todo ID")
+	// ErrInvalidPattern is returned when the search pattern is not a valid regular expression.
+	ErrInvalidPattern = errors.New("invalid pattern")
+	// ErrListNotFound is returned when the list doesn't exist.
+	ErrListNotFound = errors.New("list not found")
+	// ErrUnavailable is returned when the backend can't be reached.
+	ErrUnavailable = errors.New("store unavailable")
+	// ErrQuotaExceeded is returned when the tenant has reached its quota of todos or lists.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrVersionMismatch is returned when the todo doesn't have the expected version.
+	ErrVersionMismatch = errors.New("the todo has changed, its version is not the expected one")
+	// ErrBatchAborted is returned on the items of an atomic batch which are not applied because another item failed.
+	ErrBatchAborted = errors.New("not applied, another item of the atomic batch failed")
+	// ErrAtomicUnsupported is returned when the backend can't apply a batch all or nothing.
+	ErrAtomicUnsupported = errors.New("the backend doesn't support the atomic batches")
+	// ErrDuplicateID is returned on the items of a batch whose todo is already in the batch.
+	ErrDuplicateID = errors.New("the todo is already in the batch")
+	// ErrUnexpectedID is returned when the backend returns an ID which doesn't have the expected type.
+	ErrUnexpectedID = errors.New("unexpected ID from the backend")
+)
+
+// The fields of a todo which can be updated, they follow the protobuf names.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldTags        = "tags"
+	FieldState       = "state"
+	FieldReminder    = "reminder"
+	FieldListID      = "listId"
+)
+
+// The orders of the search.
+const (
+	// OrderCreated the order of creation
+	OrderCreated = "created"
+	// OrderUpdated the order of the last changes
+	OrderUpdated = "updated"
+	// OrderCompleted the order of completion, the todos which are not DONE first
+	OrderCompleted = "completed"
+	// OrderDeleted the order of the moves to the trash
+	OrderDeleted = "deleted"
+	// OrderReminder the order of the reminders
+	OrderReminder = "reminder"
+	// OrderTitle the alphabetical order of the titles
+	OrderTitle = "title"
+	// OrderState the alphabetical order of the state names
+	OrderState = "state"
+)
+
+// Query a search with its order and the page to return
+type Query struct {
+	// Request the filters of the search, the todos in the trash are searched only with trashed
+	Request *pb.SearchRequest
+	// OrderBy one of the orders, OrderCreated if empty
+	OrderBy string
+	// Descending reverses the order
+	Descending bool
+	// Offset the number of todos to skip
+	Offset int64
+	// Limit the maximum of todos to return, 0 means no limit
+	Limit int64
+}
+
+// Change an update or a deletion of a batch
+type Change struct {
+	// ToDo the todo with its ID and the fields to update, only the ID is read on a deletion
+	ToDo *pb.ToDo
+	// Paths the fields to update
+	Paths []string
+	// Version the version the todo must have, 0 skips the check
+	Version int64
+}
+
+// Result the outcome of an item of a batch
+type Result struct {
+	// ID the ID of the created todo
+	ID string
+	// Count the number of created, updated or deleted todos
+	Count int64
+	// Err the failure of the item
+	Err error
+}
+
+// Store manages the todos in a backend.
+type Store interface {
+	// Create stores the todo with the version 1 and its timestamps and returns its ID.
+	Create(ctx context.Context, todo *pb.ToDo) (string, error)
+
+	// Read returns the todo by ID or ErrNotFound, the todos in the trash are not found.
+	Read(ctx context.Context, id string) (*pb.ToDo, error)
+
+	// Update sets the fields listed in paths on the todo with the same ID, increments its version and stamps it if it changes,
+	// and returns the number of modified todos, ErrNotFound or ErrVersionMismatch if version isn't 0 nor the one of the todo.
+	Update(ctx context.Context, todo *pb.ToDo, paths []string, version int64) (int64, error)
+
+	// Delete moves the todo by ID to the trash, increments its version and stamps it,
+	// and returns the number of deleted todos, ErrNotFound or ErrVersionMismatch if version isn't 0 nor the one of the todo.
+	Delete(ctx context.Context, id string, version int64) (int64, error)
+
+	// CreateMany stores the todos like Create and returns their results in the same order.
+	// If atomic is set, the todos are all created or none of them and the others fail with ErrBatchAborted when one fails.
+	// The error is the failure of the whole batch, ErrAtomicUnsupported if the backend can't apply it atomically.
+	CreateMany(ctx context.Context, todos []*pb.ToDo, atomic bool) ([]Result, error)
+
+	// UpdateMany applies the updates like Update and returns their results in the same order,
+	// a todo is changed once by batch, its next updates fail with ErrDuplicateID. Atomic and the error are like in CreateMany.
+	UpdateMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error)
+
+	// DeleteMany moves the todos of the changes to the trash like Delete and returns their results in the same order.
+	// Duplicates, atomic and the error are like in UpdateMany.
+	DeleteMany(ctx context.Context, changes []Change, atomic bool) ([]Result, error)
+
+	// ReadTrashed returns the todo by ID in the trash or ErrNotFound.
+	ReadTrashed(ctx context.Context, id string) (*pb.ToDo, error)
+
+	// Restore takes the todo by ID out of the trash, increments its version and stamps it,
+	// and returns the number of restored todos or ErrNotFound.
+	Restore(ctx context.Context, id string) (int64, error)
+
+	// Purge removes the todo by ID from the trash if it has still the version, any version if 0,
+	// and returns the number of purged todos, ErrNotFound or ErrVersionMismatch.
+	Purge(ctx context.Context, id string, version int64) (int64, error)
+
+	// Search returns the page of todos matching the query and the number of todos matching it.
+	Search(ctx context.Context, q Query) ([]*pb.ToDo, int64, error)
+
+	// Iterate calls fn on each todo matching the query in the order,
+	// it stops on the first error of fn and returns it.
+	Iterate(ctx context.Context, q Query, fn func(*pb.ToDo) error) error
+
+	// CreateList stores the list and returns its ID.
+	CreateList(ctx context.Context, list *pb.List) (string, error)
+
+	// ReadList returns the list by ID or ErrListNotFound.
+	ReadList(ctx context.Context, id string) (*pb.List, error)
+
+	// UpdateList replaces the name and the members of the list with the same ID, the owner is kept,
+	// and returns the number of modified lists or ErrListNotFound.
+	UpdateList(ctx context.Context, list *pb.List) (int64, error)
+
+	// DeleteList removes the list by ID and returns the number of deleted lists or ErrListNotFound,
+	// the todos of the list are kept.
+	DeleteList(ctx context.Context, id string) (int64, error)
+
+	// SearchLists returns the lists owned by or shared with subject in the order of creation,
+	// all the lists if subject is empty.
+	SearchLists(ctx context.Context, subject string) ([]*pb.List, error)
+
+	// AppendHistory appends the record to the history of its todo, the history is kept after the deletion of the todo.
+	AppendHistory(ctx context.Context, record *pb.HistoryRecord) error
+
+	// History returns the records of the todo id in the order they were appended, empty if it has none.
+	History(ctx context.Context, id string) ([]*pb.HistoryRecord, error)
+
+	// Ping checks the backend is reachable.
+	Ping(ctx context.Context) error
+
+	// Close releases the resources of the backend.
+	Close() error
+}
+
+// setFields copies the fields listed in paths from src to dst.
+func setFields(dst *pb.ToDo, src *pb.ToDo, paths []string) {
+	for _, path := range paths {
+		switch path {
+		case FieldTitle:
+			dst.Title = src.GetTitle()
+		case FieldDescription:
+			dst.Description = src.GetDescription()
+		case FieldTags:
+			dst.Tags = append([]string(nil), src.GetTags()...)
+		case FieldState:
+			dst.State = src.GetState()
+		case FieldReminder:
+			dst.Reminder = &timestamp.Timestamp{Seconds: src.GetReminder().GetSeconds()}
+		case FieldListID:
+			dst.ListId = src.GetListId()
+		}
+	}
+}
+
+// newToDo returns the todo stored by Create with the ID and the version 1 created at t
+func newToDo(id string, todo *pb.ToDo, t *timestamp.Timestamp) *pb.ToDo {
+	stored := &pb.ToDo{
+		Id:       id,
+		Reminder: &timestamp.Timestamp{},
+		Owner:    todo.GetOwner(),
+		Version:  1,
+	}
+	setFields(stored, todo, []string{FieldTitle, FieldDescription, FieldTags, FieldState, FieldReminder, FieldListID})
+	stampCreated(stored, t)
+	return stored
+}
+
+// applyUpdate returns the todo current with the fields of the change at t, nil if it doesn't change
+func applyUpdate(current *pb.ToDo, c Change, t *timestamp.Timestamp) (*pb.ToDo, error) {
+	if err := checkVersion(current, c.Version); err != nil {
+		return nil, err
+	}
+	next := proto.Clone(current).(*pb.ToDo)
+	setFields(next, c.ToDo, c.Paths)
+	if proto.Equal(current, next) {
+		return nil, nil
+	}
+	next.Version++
+	stampUpdated(current, next, t)
+	return next, nil
+}
+
+// applyTrash returns the todo current moved to the trash by the change at t
+func applyTrash(current *pb.ToDo, c Change, t *timestamp.Timestamp) (*pb.ToDo, error) {
+	if err := checkVersion(current, c.Version); err != nil {
+		return nil, err
+	}
+	next := proto.Clone(current).(*pb.ToDo)
+	stampTrashed(current, next, true, t)
+	return next, nil
+}
+
+// applyMany applies the changes with apply on the todos out of the trash returned by get,
+// the changes on a todo which is already in the batch fail with ErrDuplicateID.
+// It returns the results and the changed todos by ID to store, nil if the atomic batch is aborted.
+func applyMany(changes []Change, atomic bool, get func(id string) (*pb.ToDo, error),
+	apply func(*pb.ToDo, Change, *timestamp.Timestamp) (*pb.ToDo, error)) ([]Result, map[string]*pb.ToDo) {
+	t := now()
+	results := make([]Result, len(changes))
+	changed := map[string]*pb.ToDo{}
+	seen := map[string]bool{}
+	for i, c := range changes {
+		id := c.ToDo.GetId()
+		if seen[id] {
+			results[i].Err = ErrDuplicateID
+			continue
+		}
+		seen[id] = true
+		current, err := get(id)
+		var next *pb.ToDo
+		if err == nil {
+			next, err = apply(current, c, t)
+		}
+		switch {
+		case err != nil:
+			results[i].Err = err
+		case next != nil:
+			changed[id] = next
+			results[i].Count = 1
+		}
+	}
+	if abort(results, atomic) {
+		return results, nil
+	}
+	return results, changed
+}
+
+// abort fails the items of an atomic batch with ErrBatchAborted if one of them failed, it tells if the batch is aborted
+func abort(results []Result, atomic bool) bool {
+	if !atomic {
+		return false
+	}
+	failed := false
+	for _, r := range results {
+		failed = failed || r.Err != nil
+	}
+	if !failed {
+		return false
+	}
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = Result{Err: ErrBatchAborted}
+		}
+	}
+	return true
+}
+
+// now returns the time of a change, truncated to the millisecond like the dates of mongo
+func now() *timestamp.Timestamp {
+	t, _ := ptypes.TimestampProto(time.Now().Truncate(time.Millisecond))
+	return t
+}
+
+// stampCreated sets the timestamps of the todo created at t
+func stampCreated(todo *pb.ToDo, t *timestamp.Timestamp) {
+	todo.CreatedAt = t
+	stampUpdated(nil, todo, t)
+}
+
+// stampUpdated sets the timestamps of the todo changed from current at t,
+// completedAt is set when the state moves to DONE and cleared when it moves back.
+func stampUpdated(current *pb.ToDo, next *pb.ToDo, t *timestamp.Timestamp) {
+	next.UpdatedAt = t
+	switch {
+	case next.GetState() != pb.ToDo_DONE:
+		next.CompletedAt = nil
+	case current.GetState() != pb.ToDo_DONE:
+		next.CompletedAt = t
+	}
+}
+
+// stampTrashed moves next, the todo current changed at t, to the trash or out of it
+func stampTrashed(current *pb.ToDo, next *pb.ToDo, trashed bool, t *timestamp.Timestamp) {
+	next.DeletedAt = nil
+	if trashed {
+		next.DeletedAt = t
+	}
+	next.Version++
+	stampUpdated(current, next, t)
+}
+
+// checkTrash returns ErrNotFound if the todo is in the trash and trashed isn't set or the other way around
+func checkTrash(todo *pb.ToDo, trashed bool) error {
+	if (todo.GetDeletedAt() != nil) != trashed {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkVersion returns ErrVersionMismatch if the todo doesn't have the expected version, 0 skips the check
+func checkVersion(todo *pb.ToDo, version int64) error {
+	if version != 0 && todo.GetVersion() != version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// newList copies the fields of list stored by the backends with the ID
+func newList(id string, list *pb.List) *pb.List {
+	stored := &pb.List{Id: id, Name: list.GetName(), Owner: list.GetOwner()}
+	for _, member := range list.GetMembers() {
+		stored.Members = append(stored.Members, &pb.List_Member{Subject: member.GetSubject(), Role: member.GetRole()})
+	}
+	return stored
+}
+
+// sharedWith tells if subject is the owner or a member of the list, all the subjects are if subject is empty
+func sharedWith(list *pb.List, subject string) bool {
+	if subject == "" || list.GetOwner() == subject {
+		return true
+	}
+	for _, member := range list.GetMembers() {
+		if member.GetSubject() == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// sortLists sorts the lists in the order of creation
+func sortLists(lists []*pb.List) []*pb.List {
+	sort.Slice(lists, func(i, j int) bool { return lists[i].GetId() < lists[j].GetId() })
+	return lists
+}
+
+// less compares two todos in the order, the IDs break the ties
+func less(orderBy string, a *pb.ToDo, b *pb.ToDo) bool {
+	var c int
+	switch orderBy {
+	case OrderUpdated:
+		c = compareTimestamp(a.GetUpdatedAt(), b.GetUpdatedAt())
+	case OrderCompleted:
+		c = compareTimestamp(a.GetCompletedAt(), b.GetCompletedAt())
+	case OrderDeleted:
+		c = compareTimestamp(a.GetDeletedAt(), b.GetDeletedAt())
+	case OrderReminder:
+		c = compareInt64(a.GetReminder().GetSeconds(), b.GetReminder().GetSeconds())
+	case OrderTitle:
+		c = strings.Compare(a.GetTitle(), b.GetTitle())
+	case OrderState:
+		c = strings.Compare(a.GetState().String(), b.GetState().String())
+	}
+	if c == 0 {
+		return a.GetId() < b.GetId()
+	}
+	return c < 0
+}
+
+func compareInt64(a int64, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareTimestamp compares two timestamps, a missing timestamp is before the others
+func compareTimestamp(a *timestamp.Timestamp, b *timestamp.Timestamp) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if c := compareInt64(a.GetSeconds(), b.GetSeconds()); c != 0 {
+		return c
+	}
+	return compareInt64(int64(a.GetNanos()), int64(b.GetNanos()))
+}
+
+// page sorts the todos and returns the page of the query
+func page(todos []*pb.ToDo, q Query) []*pb.ToDo {
+	sort.Slice(todos, func(i, j int) bool {
+		if q.Descending {
+			return less(q.OrderBy, todos[j], todos[i])
+		}
+		return less(q.OrderBy, todos[i], todos[j])
+	})
+	if q.Offset >= int64(len(todos)) {
+		return []*pb.ToDo{}
+	}
+	todos = todos[q.Offset:]
+	if q.Limit > 0 && q.Limit < int64(len(todos)) {
+		todos = todos[:q.Limit]
+	}
+	return todos
+}
+
+// each calls fn on the todos, it stops on the first error or when ctx is done
+func each(ctx context.Context, todos []*pb.ToDo, fn func(*pb.ToDo) error) error {
+	for _, todo := range todos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
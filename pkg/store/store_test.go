@@ -0,0 +1,31 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/sjeandeaux/todo/pkg/store"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestStore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Store Suite")
+}
+
+// search returns all the todos matching the request in the order of creation
+func search(store Store, r *pb.SearchRequest) []*pb.ToDo {
+	todos, total, err := store.Search(context.TODO(), Query{Request: r})
+	Ω(err).NotTo(HaveOccurred())
+	Ω(todos).Should(HaveLen(int(total)))
+	return todos
+}
+
+// unstamped returns the todo without its timestamps, the tests of the timestamps check them
+func unstamped(todo *pb.ToDo) *pb.ToDo {
+	todo.CreatedAt, todo.UpdatedAt, todo.CompletedAt = nil, nil, nil
+	return todo
+}
@@ -0,0 +1,17 @@
+package tenant_test
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// mockServerStream a stream with a context
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,159 @@
+// Package tenant isolates the data of the teams which share todod,
+// the tenant of a call comes from its token or from the metadata x-tenant.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Header the metadata of the tenant, the REST API forwards the HTTP header X-Tenant in it
+const Header = "x-tenant"
+
+// name the tenants name databases and collections, they are short and without special characters
+var name = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// requests counts the calls by tenant
+var requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "todod_tenant_requests_total",
+	Help: "The number of calls by tenant, method and code.",
+}, []string{"tenant", "method", "code"})
+
+func init() {
+	prometheus.MustRegister(requests)
+}
+
+type tenantKey struct{}
+
+// NewContext returns a context with the tenant
+func NewContext(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant of the context, empty without tenant
+func FromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// Resolver finds the tenant of the calls among the known tenants
+type Resolver struct {
+	tenants map[string]bool
+}
+
+// NewResolver returns the resolver of the tenants, the names are lowercase letters, digits, _ and -
+func NewResolver(tenants []string) (*Resolver, error) {
+	r := &Resolver{tenants: make(map[string]bool, len(tenants))}
+	for _, tenant := range tenants {
+		if tenant = strings.TrimSpace(tenant); !name.MatchString(tenant) {
+			return nil, fmt.Errorf("the tenant %q is not %s", tenant, name)
+		}
+		r.tenants[tenant] = true
+	}
+	if len(r.tenants) == 0 {
+		return nil, fmt.Errorf("no tenant")
+	}
+	return r, nil
+}
+
+// Tenants returns the known tenants in the alphabetical order
+func (r *Resolver) Tenants() []string {
+	tenants := make([]string, 0, len(r.tenants))
+	for tenant := range r.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// Resolve returns the tenant of the call: the tenant of the token, else the metadata x-tenant.
+// The metadata can't choose another tenant than the one of the token.
+func (r *Resolver) Resolve(ctx context.Context) (string, error) {
+	var fromToken, fromHeader string
+	if identity, ok := auth.FromContext(ctx); ok {
+		fromToken = identity.Tenant
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(Header); len(values) > 0 {
+			fromHeader = values[0]
+		}
+	}
+
+	tenant := fromToken
+	switch {
+	case fromToken != "" && fromHeader != "" && fromHeader != fromToken:
+		return "", status.Errorf(codes.PermissionDenied, "the token is for the tenant %q", fromToken)
+	case fromToken == "" && fromHeader == "":
+		return "", status.Errorf(codes.InvalidArgument, "the tenant is required in the token or the metadata %s", Header)
+	case fromToken == "":
+		tenant = fromHeader
+	}
+	if !r.tenants[tenant] {
+		return "", status.Errorf(codes.PermissionDenied, "unknown tenant %q", tenant)
+	}
+	return tenant, nil
+}
+
+// exempt the health checks and the reflection don't have a tenant
+func exempt(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/") || strings.HasPrefix(method, "/grpc.reflection.")
+}
+
+// resolve puts the tenant of the call in its context
+func (r *Resolver) resolve(ctx context.Context) (context.Context, string, error) {
+	tenant, err := r.Resolve(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	ctxlogrus.AddFields(ctx, log.Fields{"tenant": tenant})
+	return NewContext(ctx, tenant), tenant, nil
+}
+
+// UnaryServerInterceptor returns the interceptor which puts the tenant in the context of the unary calls,
+// it must follow the authentication
+func (r *Resolver) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if exempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, tenant, err := r.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		requests.WithLabelValues(tenant, info.FullMethod, status.Code(err).String()).Inc()
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns the interceptor which puts the tenant in the context of the streams,
+// it must follow the authentication
+func (r *Resolver) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if exempt(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		ctx, tenant, err := r.resolve(stream.Context())
+		if err != nil {
+			return err
+		}
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		err = handler(srv, wrapped)
+		requests.WithLabelValues(tenant, info.FullMethod, status.Code(err).String()).Inc()
+		return err
+	}
+}
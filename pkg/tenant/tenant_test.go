@@ -0,0 +1,112 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sjeandeaux/todo/pkg/auth"
+	. "github.com/sjeandeaux/todo/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTenant(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tenant Suite")
+}
+
+var _ = Describe("Tenant", func() {
+
+	var resolver *Resolver
+
+	BeforeEach(func() {
+		var err error
+		resolver, err = NewResolver([]string{"acme", " globex"})
+		Ω(err).NotTo(HaveOccurred())
+	})
+
+	// call returns the context with the token of tenant and the metadata x-tenant
+	call := func(fromToken string, fromHeader string) context.Context {
+		ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "alice", Tenant: fromToken})
+		if fromHeader != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(Header, fromHeader))
+		}
+		return ctx
+	}
+
+	Describe("NewResolver", func() {
+		It("should return the tenants in order", func() {
+			Ω(resolver.Tenants()).Should(Equal([]string{"acme", "globex"}))
+		})
+
+		It("should refuse the names which can't name a database", func() {
+			for _, tenants := range [][]string{{"ACME"}, {"a.b"}, {""}, {"a/b"}, {}} {
+				_, err := NewResolver(tenants)
+				Ω(err).Should(HaveOccurred(), "%v", tenants)
+			}
+		})
+	})
+
+	Describe("Resolve", func() {
+		It("should return the tenant of the token", func() {
+			Ω(resolver.Resolve(call("acme", ""))).Should(Equal("acme"))
+			Ω(resolver.Resolve(call("acme", "acme"))).Should(Equal("acme"))
+		})
+
+		It("should return the tenant of the metadata without tenant in the token", func() {
+			Ω(resolver.Resolve(call("", "globex"))).Should(Equal("globex"))
+		})
+
+		It("should deny another tenant than the one of the token", func() {
+			_, err := resolver.Resolve(call("acme", "globex"))
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+
+		It("should deny an unknown tenant", func() {
+			_, err := resolver.Resolve(call("", "initech"))
+			Ω(status.Code(err)).Should(Equal(codes.PermissionDenied))
+		})
+
+		It("should require a tenant", func() {
+			_, err := resolver.Resolve(context.Background())
+			Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("Interceptors", func() {
+		It("should put the tenant in the context of the unary calls", func() {
+			var tenant string
+			_, err := resolver.UnaryServerInterceptor()(call("", "acme"), nil, &grpc.UnaryServerInfo{FullMethod: "/v1.ToDoService/Read"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					tenant = FromContext(ctx)
+					return nil, nil
+				})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(tenant).Should(Equal("acme"))
+		})
+
+		It("should refuse the calls without tenant except the health checks", func() {
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+			_, err := resolver.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/v1.ToDoService/Read"}, handler)
+			Ω(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			_, err = resolver.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+			Ω(err).NotTo(HaveOccurred())
+		})
+
+		It("should put the tenant in the context of the streams", func() {
+			var tenant string
+			err := resolver.StreamServerInterceptor()(nil, &mockServerStream{ctx: call("globex", "")}, &grpc.StreamServerInfo{FullMethod: "/v1.ToDoService/Watch"},
+				func(srv interface{}, stream grpc.ServerStream) error {
+					tenant = FromContext(stream.Context())
+					return nil
+				})
+			Ω(err).NotTo(HaveOccurred())
+			Ω(tenant).Should(Equal("globex"))
+		})
+	})
+})
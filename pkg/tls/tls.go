@@ -0,0 +1,184 @@
+// Package tls builds the TLS configurations of todod and todo-cli.
+// The server reads its certificates again when the files change, no restart is needed to renew them.
+package tls
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNoCertificate the CA file has no PEM certificate
+var ErrNoCertificate = errors.New("no certificate in the file")
+
+// Server the TLS configuration of the server on the certificate, the key and optionally the CA of the clients
+type Server struct {
+	files []string
+
+	mu     sync.Mutex
+	stamps []stamp
+	config *tls.Config
+}
+
+// stamp identifies a version of a file
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewServer loads the certificate and the key, the clients must present a certificate signed by clientCAFile if it is not empty
+func NewServer(certFile, keyFile, clientCAFile string) (*Server, error) {
+	s := &Server{files: []string{certFile, keyFile}}
+	if clientCAFile != "" {
+		s.files = append(s.files, clientCAFile)
+	}
+	if _, err := s.current(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Config returns the configuration to give to the listener, each handshake uses the last certificates
+func (s *Server) Config() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return s.current()
+		},
+	}
+}
+
+// Loopback returns the configuration of a client of the server on the same host like the REST gateway,
+// it presents the certificate of the server and only trusts it.
+// With a client CA the certificate must be valid for the client authentication too.
+func (s *Server) Loopback() *tls.Config {
+	return &tls.Config{
+		// the certificate is checked by VerifyPeerCertificate, its name can be anything but localhost
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			config, err := s.current()
+			if err != nil {
+				return err
+			}
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], config.Certificates[0].Certificate[0]) {
+				return errors.New("the certificate is not the one of the server")
+			}
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			config, err := s.current()
+			if err != nil {
+				return nil, err
+			}
+			return &config.Certificates[0], nil
+		},
+	}
+}
+
+// current returns the configuration, it is loaded again if a file changed.
+// The previous configuration is kept if the new files are invalid, during a renewal for example.
+func (s *Server) current() (*tls.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stamps := make([]stamp, len(s.files))
+	for i, file := range s.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return s.keep(err)
+		}
+		stamps[i] = stamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	if s.config != nil && sameStamps(stamps, s.stamps) {
+		return s.config, nil
+	}
+
+	config, err := s.load()
+	if err != nil {
+		return s.keep(err)
+	}
+	if s.config != nil {
+		log.WithField("files", s.files).Info("the certificates are reloaded")
+	}
+	s.config, s.stamps = config, stamps
+	return config, nil
+}
+
+// keep returns the previous configuration if there is one or err
+func (s *Server) keep(err error) (*tls.Config, error) {
+	if s.config == nil {
+		return nil, err
+	}
+	log.WithError(err).Warn("the certificates can't be reloaded, the previous ones are used")
+	return s.config, nil
+}
+
+func (s *Server) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(s.files[0], s.files[1])
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2"},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if len(s.files) > 2 {
+		if config.ClientCAs, err = loadPool(s.files[2]); err != nil {
+			return nil, err
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+func sameStamps(a, b []stamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
+
+// NewClient returns the configuration of a client which verifies the server with caFile or the system CAs if it is empty,
+// it presents the certificate certFile if it is not empty.
+func NewClient(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		var err error
+		if config.RootCAs, err = loadPool(caFile); err != nil {
+			return nil, err
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func loadPool(file string) (*x509.CertPool, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("%s: %w", file, ErrNoCertificate)
+	}
+	return pool, nil
+}
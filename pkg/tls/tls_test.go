@@ -0,0 +1,253 @@
+package tls_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/sjeandeaux/todo/pkg/tls"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTLS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TLS Suite")
+}
+
+// authority a CA which signs the certificates of the tests
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newAuthority(name string) *authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Ω(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Ω(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Ω(err).NotTo(HaveOccurred())
+	return &authority{cert: cert, key: key}
+}
+
+// write writes the certificate of the CA in file
+func (a *authority) write(file string) {
+	Ω(ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.cert.Raw}), 0600)).Should(Succeed())
+}
+
+// issue writes a certificate for localhost valid for the server and the client authentications
+func (a *authority) issue(serial int64, certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Ω(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	Ω(err).NotTo(HaveOccurred())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	Ω(err).NotTo(HaveOccurred())
+	Ω(ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).Should(Succeed())
+	Ω(ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)).Should(Succeed())
+}
+
+// handshake connects client to a server on server and returns the serial number of the certificate of the server
+func handshake(server *cryptotls.Config, client *cryptotls.Config) (int64, error) {
+	lis, err := cryptotls.Listen("tcp", "localhost:0", server)
+	Ω(err).NotTo(HaveOccurred())
+	defer lis.Close()
+	go func(lis net.Listener) {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if conn.(*cryptotls.Conn).Handshake() == nil {
+			conn.Write([]byte{1})
+		}
+	}(lis)
+
+	client = client.Clone()
+	if client.ServerName == "" {
+		client.ServerName = "localhost"
+	}
+	conn, err := cryptotls.Dial("tcp", lis.Addr().String(), client)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	// the server checks the certificate of the client after the handshake of the client in TLS 1.3,
+	// it writes only if it accepts the client
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return 0, err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+var _ = Describe("TLS", func() {
+
+	var (
+		dir                                   string
+		ca, other                             *authority
+		caFile, certFile, keyFile, clientCert string
+		clientKey                             string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "tls")
+		Ω(err).NotTo(HaveOccurred())
+		caFile = filepath.Join(dir, "ca.pem")
+		certFile = filepath.Join(dir, "server.pem")
+		keyFile = filepath.Join(dir, "server.key")
+		clientCert = filepath.Join(dir, "client.pem")
+		clientKey = filepath.Join(dir, "client.key")
+
+		ca, other = newAuthority("todo CA"), newAuthority("other CA")
+		ca.write(caFile)
+		ca.issue(10, certFile, keyFile)
+		ca.issue(20, clientCert, clientKey)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Context("With a server certificate", func() {
+		It("should be verified by the client with the CA", func() {
+			server, err := NewServer(certFile, keyFile, "")
+			Ω(err).NotTo(HaveOccurred())
+			client, err := NewClient(caFile, "", "")
+			Ω(err).NotTo(HaveOccurred())
+
+			Ω(handshake(server.Config(), client)).Should(Equal(int64(10)))
+		})
+
+		It("should be rejected by the client with another CA", func() {
+			server, err := NewServer(certFile, keyFile, "")
+			Ω(err).NotTo(HaveOccurred())
+			other.write(caFile)
+			client, err := NewClient(caFile, "", "")
+			Ω(err).NotTo(HaveOccurred())
+
+			_, err = handshake(server.Config(), client)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should be reloaded when the files change", func() {
+			server, err := NewServer(certFile, keyFile, "")
+			Ω(err).NotTo(HaveOccurred())
+			client, err := NewClient(caFile, "", "")
+			Ω(err).NotTo(HaveOccurred())
+			Ω(handshake(server.Config(), client)).Should(Equal(int64(10)))
+
+			ca.issue(11, certFile, keyFile)
+			later := time.Now().Add(time.Minute)
+			Ω(os.Chtimes(certFile, later, later)).Should(Succeed())
+
+			Ω(handshake(server.Config(), client)).Should(Equal(int64(11)))
+		})
+
+		It("should keep the previous certificate when the new one is invalid", func() {
+			server, err := NewServer(certFile, keyFile, "")
+			Ω(err).NotTo(HaveOccurred())
+			client, err := NewClient(caFile, "", "")
+			Ω(err).NotTo(HaveOccurred())
+
+			Ω(ioutil.WriteFile(certFile, []byte("renewing"), 0600)).Should(Succeed())
+
+			Ω(handshake(server.Config(), client)).Should(Equal(int64(10)))
+		})
+
+		It("should fail on missing files", func() {
+			_, err := NewServer(filepath.Join(dir, "missing.pem"), keyFile, "")
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should fail on a CA without certificate", func() {
+			Ω(ioutil.WriteFile(caFile, []byte("nothing"), 0600)).Should(Succeed())
+			_, err := NewClient(caFile, "", "")
+			Ω(err).Should(MatchError(ContainSubstring(ErrNoCertificate.Error())))
+		})
+	})
+
+	Context("With a client CA", func() {
+		It("should accept the clients with a certificate signed by the CA", func() {
+			server, err := NewServer(certFile, keyFile, caFile)
+			Ω(err).NotTo(HaveOccurred())
+			client, err := NewClient(caFile, clientCert, clientKey)
+			Ω(err).NotTo(HaveOccurred())
+
+			Ω(handshake(server.Config(), client)).Should(Equal(int64(10)))
+		})
+
+		It("should reject the clients without certificate", func() {
+			server, err := NewServer(certFile, keyFile, caFile)
+			Ω(err).NotTo(HaveOccurred())
+			client, err := NewClient(caFile, "", "")
+			Ω(err).NotTo(HaveOccurred())
+
+			_, err = handshake(server.Config(), client)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should reject the clients with a certificate signed by another CA", func() {
+			server, err := NewServer(certFile, keyFile, caFile)
+			Ω(err).NotTo(HaveOccurred())
+			other.issue(30, clientCert, clientKey)
+			client, err := NewClient(caFile, clientCert, clientKey)
+			Ω(err).NotTo(HaveOccurred())
+
+			_, err = handshake(server.Config(), client)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should accept the loopback of the server", func() {
+			server, err := NewServer(certFile, keyFile, caFile)
+			Ω(err).NotTo(HaveOccurred())
+
+			Ω(handshake(server.Config(), server.Loopback())).Should(Equal(int64(10)))
+		})
+	})
+
+	Context("With the loopback of another server", func() {
+		It("should reject the certificate", func() {
+			server, err := NewServer(certFile, keyFile, "")
+			Ω(err).NotTo(HaveOccurred())
+			ca.issue(40, clientCert, clientKey)
+			otherServer, err := NewServer(clientCert, clientKey, "")
+			Ω(err).NotTo(HaveOccurred())
+
+			_, err = handshake(server.Config(), otherServer.Loopback())
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
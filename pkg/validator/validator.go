@@ -0,0 +1,331 @@
+// Package validator checks and normalises the messages received by the service.
+package validator
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// The limits on the fields of a todo
+const (
+	MaxTitleLength       = 256
+	MaxDescriptionLength = 4096
+	MaxTags              = 32
+	MaxTagLength         = 64
+)
+
+// MaxListNameLength the limit on the name of a list
+const MaxListNameLength = 256
+
+// The limits on the pattern of a search
+const (
+	MaxPatternLength = 256
+	MaxPatternWords  = 16
+	// MaxPatternRepeat the maximum count of a repetition x{n,m} in a regex
+	MaxPatternRepeat = 100
+)
+
+// Violation a field which is not valid
+type Violation struct {
+	// Field the path of the field ex: tags[1]
+	Field string
+	// Description why it is not valid
+	Description string
+}
+
+// Error the violations found in a message
+type Error struct {
+	Violations []Violation
+}
+
+// Error the violations in one line
+func (e *Error) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", v.Field, v.Description)
+	}
+	return strings.Join(messages, ", ")
+}
+
+func (e *Error) add(field string, format string, args ...interface{}) {
+	e.Violations = append(e.Violations, Violation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+func (e *Error) orNil() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ToDo normalises the title and the tags and checks the fields of todo listed in paths, all the fields if paths is nil.
+func ToDo(todo *pb.ToDo, paths []string) error {
+	checked := map[string]bool{}
+	for _, path := range paths {
+		checked[path] = true
+	}
+	check := func(field string) bool {
+		return paths == nil || checked[field]
+	}
+
+	e := &Error{}
+	if check("title") {
+		title := strings.TrimSpace(todo.GetTitle())
+		todo.Title = title
+		if title == "" {
+			e.add("title", "must not be empty")
+		} else if l := utf8.RuneCountInString(title); l > MaxTitleLength {
+			e.add("title", "must not exceed %d characters, got %d", MaxTitleLength, l)
+		}
+	}
+	if check("description") {
+		if l := utf8.RuneCountInString(todo.GetDescription()); l > MaxDescriptionLength {
+			e.add("description", "must not exceed %d characters, got %d", MaxDescriptionLength, l)
+		}
+	}
+	if check("tags") {
+		todo.Tags = normaliseTags(e, "tags", todo.GetTags())
+	}
+	if check("state") {
+		checkState(e, "state", todo.GetState())
+	}
+	if check("reminder") {
+		if reminder := todo.GetReminder(); reminder != nil {
+			if reminder.GetSeconds() < 0 {
+				e.add("reminder", "must not be negative")
+			} else if _, err := ptypes.Timestamp(reminder); err != nil {
+				e.add("reminder", "%v", err)
+			}
+		}
+	}
+	return e.orNil()
+}
+
+// SearchRequest normalises the tags and checks the states, the pattern and the time ranges of the request
+func SearchRequest(r *pb.SearchRequest) error {
+	e := &Error{}
+	r.Tags = normaliseTags(e, "tags", r.GetTags())
+	for i, state := range r.GetStates() {
+		checkState(e, fmt.Sprintf("states[%d]", i), state)
+	}
+	checkPattern(e, r.GetMatchMode(), r.GetPattern())
+	checkRange(e, "createdAt", r.GetCreatedAt())
+	checkRange(e, "updatedAt", r.GetUpdatedAt())
+	checkRange(e, "completedAt", r.GetCompletedAt())
+	checkRange(e, "deletedAt", r.GetDeletedAt())
+	return e.orNil()
+}
+
+// List normalises the name of the list and checks it
+func List(list *pb.List) error {
+	e := &Error{}
+	name := strings.TrimSpace(list.GetName())
+	list.Name = name
+	if name == "" {
+		e.add("name", "must not be empty")
+	} else if l := utf8.RuneCountInString(name); l > MaxListNameLength {
+		e.add("name", "must not exceed %d characters, got %d", MaxListNameLength, l)
+	}
+	return e.orNil()
+}
+
+// ShareListRequest normalises the subject and checks the role of the request
+func ShareListRequest(r *pb.ShareListRequest) error {
+	e := &Error{}
+	r.Subject = strings.TrimSpace(r.GetSubject())
+	if r.Subject == "" {
+		e.add("subject", "must not be empty")
+	}
+	if _, ok := pb.List_Role_name[int32(r.GetRole())]; !ok {
+		e.add("role", "unknown role %d", r.GetRole())
+	}
+	return e.orNil()
+}
+
+// normaliseTags trims, lowercases and removes the duplicates
+func normaliseTags(e *Error, field string, tags []string) []string {
+	if len(tags) > MaxTags {
+		e.add(field, "must not have more than %d tags, got %d", MaxTags, len(tags))
+	}
+	seen := map[string]bool{}
+	result := []string{}
+	for i, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		switch {
+		case tag == "":
+			e.add(fmt.Sprintf("%s[%d]", field, i), "must not be blank")
+		case utf8.RuneCountInString(tag) > MaxTagLength:
+			e.add(fmt.Sprintf("%s[%d]", field, i), "must not exceed %d characters", MaxTagLength)
+		case !seen[tag]:
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// checkPattern checks the length of the pattern and its syntax in the mode.
+// A regex is RE2 without nested repetitions, alternations under a repetition nor large counts:
+// a repeated part matches a text in one way only, so the stores which backtrack don't backtrack exponentially.
+func checkPattern(e *Error, mode pb.SearchRequest_MatchMode, pattern string) {
+	if _, ok := pb.SearchRequest_MatchMode_name[int32(mode)]; !ok {
+		e.add("matchMode", "unknown match mode %d", mode)
+		return
+	}
+	if l := utf8.RuneCountInString(pattern); l > MaxPatternLength {
+		e.add("pattern", "must not exceed %d characters, got %d", MaxPatternLength, l)
+		return
+	}
+	if pattern == "" {
+		return
+	}
+	switch mode {
+	case pb.SearchRequest_REGEX:
+		re, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			e.add("pattern", "must be a RE2 regular expression: %v", err)
+			return
+		}
+		if err := checkRegex(re, false); err != "" {
+			e.add("pattern", "%s", err)
+		} else if err := checkAlternations(pattern); err != "" {
+			e.add("pattern", "%s", err)
+		}
+	case pb.SearchRequest_FULLTEXT:
+		words := strings.FieldsFunc(pattern, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if len(words) == 0 {
+			e.add("pattern", "must have a word")
+		} else if len(words) > MaxPatternWords {
+			e.add("pattern", "must not have more than %d words, got %d", MaxPatternWords, len(words))
+		}
+	}
+}
+
+// checkRegex returns why the regex is too complex, empty if it isn't
+func checkRegex(re *syntax.Regexp, repeated bool) string {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		if repeated {
+			return "must not nest the repetitions"
+		}
+		if re.Op == syntax.OpRepeat && (re.Max > MaxPatternRepeat || re.Min > MaxPatternRepeat) {
+			return fmt.Sprintf("must not repeat more than %d times", MaxPatternRepeat)
+		}
+		repeated = true
+	}
+	for _, sub := range re.Sub {
+		if err := checkRegex(sub, repeated); err != "" {
+			return err
+		}
+	}
+	return ""
+}
+
+// checkAlternations returns why an alternation of the RE2 regex pattern is under a repetition, empty if none is.
+// The parser merges the alternations of characters in classes, so the pattern given to the stores is scanned.
+func checkAlternations(pattern string) string {
+	// groups tells for each opened group if it has an alternation
+	groups := []bool{false}
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if strings.HasPrefix(pattern[i:], `\Q`) {
+				end := strings.Index(pattern[i:], `\E`)
+				if end < 0 {
+					return ""
+				}
+				i += end + 1
+			} else {
+				i++
+			}
+		case '[':
+			i = classEnd(pattern, i)
+		case '(':
+			groups = append(groups, false)
+		case '|':
+			groups[len(groups)-1] = true
+		case ')':
+			alternation := groups[len(groups)-1]
+			groups = groups[:len(groups)-1]
+			if alternation && i+1 < len(pattern) && isQuantifier(pattern[i+1:]) {
+				return "must not repeat an alternation"
+			}
+			groups[len(groups)-1] = groups[len(groups)-1] || alternation
+		}
+	}
+	return ""
+}
+
+// classEnd returns the index of the ] which closes the class opened at i
+func classEnd(pattern string, i int) int {
+	i++
+	if i < len(pattern) && pattern[i] == '^' {
+		i++
+	}
+	if i < len(pattern) && pattern[i] == ']' {
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\':
+			i++
+		case strings.HasPrefix(pattern[i:], "[:"):
+			if end := strings.Index(pattern[i:], ":]"); end >= 0 {
+				i += end + 1
+			}
+		case pattern[i] == ']':
+			return i
+		}
+	}
+	return i
+}
+
+// isQuantifier tells if s starts with a repetition
+func isQuantifier(s string) bool {
+	switch s[0] {
+	case '*', '+', '?':
+		return true
+	case '{':
+		return len(s) > 1 && s[1] >= '0' && s[1] <= '9'
+	}
+	return false
+}
+
+// checkRange checks the bounds of the range are valid timestamps and after precedes before
+func checkRange(e *Error, field string, r *pb.TimeRange) {
+	after, okAfter := checkTimestamp(e, field+".after", r.GetAfter())
+	before, okBefore := checkTimestamp(e, field+".before", r.GetBefore())
+	if okAfter && okBefore && !after.Before(before) {
+		e.add(field, "after must precede before")
+	}
+}
+
+// checkTimestamp checks the timestamp if it is set, it returns its time and true if it is set and valid
+func checkTimestamp(e *Error, field string, t *timestamp.Timestamp) (time.Time, bool) {
+	if t == nil {
+		return time.Time{}, false
+	}
+	value, err := ptypes.Timestamp(t)
+	if err != nil {
+		e.add(field, "%v", err)
+		return time.Time{}, false
+	}
+	return value, true
+}
+
+func checkState(e *Error, field string, state pb.ToDo_State) {
+	if _, ok := pb.ToDo_State_name[int32(state)]; !ok {
+		e.add(field, "unknown state %d", state)
+	}
+}
@@ -0,0 +1,214 @@
+package validator_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	. "github.com/sjeandeaux/todo/pkg/validator"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestValidator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Validator Suite")
+}
+
+var _ = Describe("Validator", func() {
+
+	violations := func(err error) []Violation {
+		if err == nil {
+			return nil
+		}
+		return err.(*Error).Violations
+	}
+
+	Describe("ToDo", func() {
+		Context("With a valid todo", func() {
+			It("should normalise the tags", func() {
+				todo := &pb.ToDo{
+					Title:    "title",
+					Tags:     []string{" GoLang", "golang ", "K8s"},
+					Reminder: &timestamp.Timestamp{Seconds: 1573046180},
+					State:    pb.ToDo_DONE,
+				}
+				Ω(ToDo(todo, nil)).Should(Succeed())
+				Ω(todo.Tags).Should(Equal([]string{"golang", "k8s"}))
+			})
+		})
+
+		Context("With a todo without title", func() {
+			It("should fail on title", func() {
+				Ω(violations(ToDo(&pb.ToDo{Title: "  "}, nil))).Should(Equal([]Violation{{Field: "title", Description: "must not be empty"}}))
+			})
+		})
+
+		Context("With too long fields", func() {
+			It("should fail on each of them", func() {
+				todo := &pb.ToDo{
+					Title:       strings.Repeat("t", MaxTitleLength+1),
+					Description: strings.Repeat("d", MaxDescriptionLength+1),
+					Tags:        []string{strings.Repeat("g", MaxTagLength+1)},
+				}
+				Ω(violations(ToDo(todo, nil))).Should(Equal([]Violation{
+					{Field: "title", Description: "must not exceed 256 characters, got 257"},
+					{Field: "description", Description: "must not exceed 4096 characters, got 4097"},
+					{Field: "tags[0]", Description: "must not exceed 64 characters"},
+				}))
+			})
+		})
+
+		Context("With bad tags, state and reminder", func() {
+			It("should fail on each of them", func() {
+				todo := &pb.ToDo{
+					Title:    "title",
+					Tags:     []string{"go", " "},
+					State:    pb.ToDo_State(42),
+					Reminder: &timestamp.Timestamp{Seconds: -1},
+				}
+				Ω(violations(ToDo(todo, nil))).Should(Equal([]Violation{
+					{Field: "tags[1]", Description: "must not be blank"},
+					{Field: "state", Description: "unknown state 42"},
+					{Field: "reminder", Description: "must not be negative"},
+				}))
+			})
+		})
+
+		Context("With paths", func() {
+			It("should check only the fields in paths", func() {
+				todo := &pb.ToDo{Tags: []string{"Go"}, State: pb.ToDo_State(42)}
+				Ω(ToDo(todo, []string{"tags"})).Should(Succeed())
+				Ω(todo.Tags).Should(Equal([]string{"go"}))
+			})
+
+			It("should fail on an empty title in paths as the title can't be cleared", func() {
+				todo := &pb.ToDo{Title: " ", Description: ""}
+				Ω(violations(ToDo(todo, []string{"title", "description"}))).Should(Equal([]Violation{
+					{Field: "title", Description: "must not be empty"},
+				}))
+				Ω(ToDo(todo, []string{"description"})).Should(Succeed())
+			})
+		})
+	})
+
+	Describe("SearchRequest", func() {
+		Context("With tags and states", func() {
+			It("should normalise the tags", func() {
+				r := &pb.SearchRequest{Tags: []string{"Go", "go"}, States: []pb.ToDo_State{pb.ToDo_DONE}}
+				Ω(SearchRequest(r)).Should(Succeed())
+				Ω(r.Tags).Should(Equal([]string{"go"}))
+			})
+		})
+
+		Context("With an unknown state", func() {
+			It("should fail on the state", func() {
+				r := &pb.SearchRequest{States: []pb.ToDo_State{pb.ToDo_DONE, pb.ToDo_State(7)}}
+				Ω(violations(SearchRequest(r))).Should(Equal([]Violation{{Field: "states[1]", Description: "unknown state 7"}}))
+			})
+		})
+
+		Context("With a pattern", func() {
+			It("should accept the literal patterns which are not regexes", func() {
+				Ω(SearchRequest(&pb.SearchRequest{Pattern: "(["})).Should(Succeed())
+				Ω(SearchRequest(&pb.SearchRequest{Pattern: "^Read.*", MatchMode: pb.SearchRequest_REGEX})).Should(Succeed())
+			})
+
+			It("should fail on a too long pattern or an unknown mode", func() {
+				r := &pb.SearchRequest{Pattern: strings.Repeat("a", MaxPatternLength+1)}
+				Ω(violations(SearchRequest(r))).Should(Equal([]Violation{
+					{Field: "pattern", Description: fmt.Sprintf("must not exceed %d characters, got %d", MaxPatternLength, MaxPatternLength+1)},
+				}))
+				r = &pb.SearchRequest{Pattern: "a", MatchMode: pb.SearchRequest_MatchMode(7)}
+				Ω(violations(SearchRequest(r))).Should(Equal([]Violation{{Field: "matchMode", Description: "unknown match mode 7"}}))
+			})
+
+			It("should fail on the regexes which are not RE2 or too complex", func() {
+				for _, pattern := range []string{"([", `(a)\1`, "(?=a)", "(a+)+", "(a|b*)*", "a{1000}", "a{2,200}"} {
+					r := &pb.SearchRequest{Pattern: pattern, MatchMode: pb.SearchRequest_REGEX}
+					Ω(violations(SearchRequest(r))).Should(HaveLen(1), pattern)
+				}
+			})
+
+			It("should fail on the alternations under a repetition", func() {
+				for _, pattern := range []string{"(a|aa)+$", `(\w|\d)+x`, "((a|b)c)*", "(?:ab|cd){2}", "(a|b)?"} {
+					r := &pb.SearchRequest{Pattern: pattern, MatchMode: pb.SearchRequest_REGEX}
+					Ω(violations(SearchRequest(r))).Should(Equal([]Violation{{Field: "pattern", Description: "must not repeat an alternation"}}), pattern)
+				}
+			})
+
+			It("should accept the alternations which are not repeated", func() {
+				for _, pattern := range []string{"^(Read|Write)", `[(|)]+`, `\(a|b\)+`, `\Q(a|b)\E+`, "(a|b)[0-9]+", "(?i)micro(service|kernel)"} {
+					r := &pb.SearchRequest{Pattern: pattern, MatchMode: pb.SearchRequest_REGEX}
+					Ω(SearchRequest(r)).Should(Succeed(), pattern)
+				}
+			})
+
+			It("should fail on a full text pattern without words or with too many words", func() {
+				r := &pb.SearchRequest{Pattern: " - ", MatchMode: pb.SearchRequest_FULLTEXT}
+				Ω(violations(SearchRequest(r))).Should(Equal([]Violation{{Field: "pattern", Description: "must have a word"}}))
+				r = &pb.SearchRequest{Pattern: strings.Repeat("word ", MaxPatternWords+1), MatchMode: pb.SearchRequest_FULLTEXT}
+				Ω(violations(SearchRequest(r))).Should(HaveLen(1))
+			})
+		})
+
+		Context("With time ranges", func() {
+			It("should accept the open ranges", func() {
+				r := &pb.SearchRequest{
+					CreatedAt:   &pb.TimeRange{After: &timestamp.Timestamp{Seconds: 1573046180}},
+					UpdatedAt:   &pb.TimeRange{Before: &timestamp.Timestamp{Seconds: 1573046180}},
+					CompletedAt: &pb.TimeRange{After: &timestamp.Timestamp{Seconds: 1573046180}, Before: &timestamp.Timestamp{Seconds: 1573046181}},
+				}
+				Ω(SearchRequest(r)).Should(Succeed())
+			})
+
+			It("should fail on a bad timestamp or an empty range", func() {
+				r := &pb.SearchRequest{
+					CreatedAt: &pb.TimeRange{After: &timestamp.Timestamp{Nanos: -1}},
+					UpdatedAt: &pb.TimeRange{After: &timestamp.Timestamp{Seconds: 2}, Before: &timestamp.Timestamp{Seconds: 2}},
+					DeletedAt: &pb.TimeRange{Before: &timestamp.Timestamp{Seconds: -62135596801}},
+				}
+				v := violations(SearchRequest(r))
+				Ω(v).Should(HaveLen(3))
+				Ω(v[0].Field).Should(Equal("createdAt.after"))
+				Ω(v[1]).Should(Equal(Violation{Field: "updatedAt", Description: "after must precede before"}))
+				Ω(v[2].Field).Should(Equal("deletedAt.before"))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("should trim the name", func() {
+			list := &pb.List{Name: "  Groceries "}
+			Ω(List(list)).Should(Succeed())
+			Ω(list.Name).Should(Equal("Groceries"))
+		})
+
+		It("should fail on an empty or too long name", func() {
+			Ω(violations(List(&pb.List{Name: " "}))).Should(Equal([]Violation{{Field: "name", Description: "must not be empty"}}))
+			Ω(violations(List(&pb.List{Name: strings.Repeat("a", MaxListNameLength+1)}))).Should(Equal([]Violation{
+				{Field: "name", Description: fmt.Sprintf("must not exceed %d characters, got %d", MaxListNameLength, MaxListNameLength+1)},
+			}))
+		})
+	})
+
+	Describe("ShareListRequest", func() {
+		It("should fail on an empty subject and an unknown role", func() {
+			r := &pb.ShareListRequest{Subject: " ", Role: pb.List_Role(9)}
+			Ω(violations(ShareListRequest(r))).Should(Equal([]Violation{
+				{Field: "subject", Description: "must not be empty"},
+				{Field: "role", Description: "unknown role 9"},
+			}))
+		})
+	})
+
+	Describe("Error", func() {
+		It("should join the violations", func() {
+			err := &Error{Violations: []Violation{{Field: "title", Description: "must not be empty"}, {Field: "state", Description: "unknown state 42"}}}
+			Ω(err.Error()).Should(Equal("title: must not be empty, state: unknown state 42"))
+		})
+	})
+})
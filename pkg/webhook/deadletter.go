@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// DeadLetter a line of the dead-letter file
+type DeadLetter struct {
+	Time     time.Time       `json:"time"`
+	URL      string          `json:"url"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// deadLetter appends the failed deliveries in a JSON lines file
+type deadLetter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func openDeadLetter(path string) (*deadLetter, error) {
+	if path == "" {
+		return &deadLetter{}, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetter{file: file}, nil
+}
+
+func (dl *deadLetter) write(dv delivery, attempts int, cause error) error {
+	if dl.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(DeadLetter{
+		Time:     time.Now().UTC(),
+		URL:      dv.url,
+		Attempts: attempts,
+		Error:    cause.Error(),
+		Payload:  json.RawMessage(dv.body),
+	})
+	if err != nil {
+		return err
+	}
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	_, err = dl.file.Write(append(line, '\n'))
+	return err
+}
+
+func (dl *deadLetter) close() error {
+	if dl.file == nil {
+		return nil
+	}
+	return dl.file.Close()
+}
@@ -0,0 +1,345 @@
+// Package webhook posts the lifecycle events of the todos to HTTP receivers.
+//
+// Each delivery is a JSON payload signed with HMAC-SHA256 in the header X-Todo-Signature,
+// the failed deliveries are retried with an exponential backoff then written in a dead-letter file.
+// The dispatcher is a hook of the service so no event is lost between the handlers and the queues,
+// the events of a tenant only go to the receivers of the tenant.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+)
+
+// The types of the events
+const (
+	TypeCreated      = "todo.created"
+	TypeUpdated      = "todo.updated"
+	TypeDeleted      = "todo.deleted"
+	TypeRestored     = "todo.restored"
+	TypePurged       = "todo.purged"
+	TypeStateChanged = "todo.state_changed"
+	TypeReminder     = "todo.reminder"
+)
+
+// The headers of the deliveries
+const (
+	HeaderEvent     = "X-Todo-Event"
+	HeaderDelivery  = "X-Todo-Delivery"
+	HeaderSignature = "X-Todo-Signature"
+)
+
+// The default values of the configuration
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = time.Second
+	DefaultMaxBackoff  = time.Minute
+	DefaultQueueSize   = 1024
+	DefaultTimeout     = 10 * time.Second
+)
+
+// Payload the body of a delivery
+type Payload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Tenant    string          `json:"tenant,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+	ToDo      json.RawMessage `json:"toDo"`
+	Previous  json.RawMessage `json:"previous,omitempty"`
+}
+
+// Config the receivers and the delivery policy, the zero values use the defaults
+type Config struct {
+	// URLs the receivers of the events without tenant, each one receives all of them
+	URLs []string
+	// TenantURLs the receivers of the events of each tenant
+	TenantURLs map[string][]string
+	// Secret the key of the signatures, no signature if empty
+	Secret string
+	// MaxAttempts the number of attempts of a delivery before the dead-letter file
+	MaxAttempts int
+	// Backoff the delay before the first retry, it doubles after each attempt
+	Backoff time.Duration
+	// MaxBackoff the maximum delay between two attempts
+	MaxBackoff time.Duration
+	// QueueSize the number of pending deliveries by receiver
+	QueueSize int
+	// DeadLetter the JSON lines file of the failed deliveries, they are only logged if empty
+	DeadLetter string
+	// Client the HTTP client, a client with the timeout DefaultTimeout if nil
+	Client *http.Client
+}
+
+// Sign returns the value of the header X-Todo-Signature of body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// delivery a payload to send to a receiver
+type delivery struct {
+	url       string
+	eventType string
+	id        string
+	body      []byte
+}
+
+// Dispatcher sends the events to the receivers, one worker by receiver keeps the order of the events
+type Dispatcher struct {
+	config     Config
+	deadLetter *deadLetter
+	queues     map[string]chan delivery
+	// routes the receivers of each tenant, "" without tenant
+	routes map[string][]string
+	done   chan struct{}
+	// ctx cancels the sending deliveries on Close
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher starts the workers of the receivers
+func NewDispatcher(c Config) (*Dispatcher, error) {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = DefaultBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = DefaultQueueSize
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	dl, err := openDeadLetter(c.DeadLetter)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		config:     c,
+		deadLetter: dl,
+		queues:     make(map[string]chan delivery),
+		routes:     map[string][]string{"": c.URLs},
+		done:       make(chan struct{}),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	for t, urls := range c.TenantURLs {
+		if t != "" {
+			d.routes[t] = urls
+		}
+	}
+	for _, urls := range d.routes {
+		for _, url := range urls {
+			if _, ok := d.queues[url]; ok {
+				continue
+			}
+			queue := make(chan delivery, c.QueueSize)
+			d.queues[url] = queue
+			d.wg.Add(1)
+			go d.work(queue)
+		}
+	}
+	return d, nil
+}
+
+// ParseTenantURLs parses the receivers of the tenants written tenant=url separated by commas,
+// a tenant can have many receivers.
+func ParseTenantURLs(s string) (map[string][]string, error) {
+	urls := make(map[string][]string)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.Index(entry, "=")
+		if i <= 0 || i == len(entry)-1 {
+			return nil, fmt.Errorf("the receiver %q is not tenant=url", entry)
+		}
+		t := strings.TrimSpace(entry[:i])
+		urls[t] = append(urls[t], strings.TrimSpace(entry[i+1:]))
+	}
+	return urls, nil
+}
+
+// Changed queues the event for the receivers of its tenant, the dispatcher is a hook of the service
+func (d *Dispatcher) Changed(e *pb.Event) {
+	switch e.GetType() {
+	case pb.Event_CREATED:
+		d.publish(e.GetTenant(), TypeCreated, e.GetToDo(), nil)
+	case pb.Event_UPDATED:
+		d.publish(e.GetTenant(), TypeUpdated, e.GetToDo(), e.GetPrevious())
+		if e.GetPrevious() != nil && e.GetPrevious().GetState() != e.GetToDo().GetState() {
+			d.publish(e.GetTenant(), TypeStateChanged, e.GetToDo(), e.GetPrevious())
+		}
+	case pb.Event_DELETED:
+		d.publish(e.GetTenant(), TypeDeleted, e.GetToDo(), nil)
+	case pb.Event_RESTORED:
+		d.publish(e.GetTenant(), TypeRestored, e.GetToDo(), nil)
+	case pb.Event_PURGED:
+		d.publish(e.GetTenant(), TypePurged, e.GetToDo(), nil)
+	}
+}
+
+// Notify sends the reminder of the todo to the receivers of the tenant of ctx, the dispatcher is a notifier of the reminders
+func (d *Dispatcher) Notify(ctx context.Context, todo *pb.ToDo) error {
+	d.publish(tenant.FromContext(ctx), TypeReminder, todo, nil)
+	return nil
+}
+
+// publish queues the event for each receiver of the tenant t, a full queue goes to the dead-letter file
+func (d *Dispatcher) publish(t string, eventType string, todo *pb.ToDo, previous *pb.ToDo) {
+	urls := d.routes[t]
+	if len(urls) == 0 {
+		return
+	}
+	select {
+	case <-d.done:
+		log.WithField("event", eventType).Warn("webhook dispatcher closed")
+		return
+	default:
+	}
+	id, body, err := newPayload(t, eventType, todo, previous)
+	if err != nil {
+		log.WithError(err).WithField("event", eventType).Error("webhook payload")
+		return
+	}
+	for _, url := range urls {
+		dv := delivery{url: url, eventType: eventType, id: id, body: body}
+		select {
+		case d.queues[url] <- dv:
+		default:
+			d.fail(dv, 0, errors.New("too many pending deliveries"))
+		}
+	}
+}
+
+// newPayload returns the ID and the body of the delivery
+func newPayload(t string, eventType string, todo *pb.ToDo, previous *pb.ToDo) (string, []byte, error) {
+	p := Payload{ID: newID(), Type: eventType, Tenant: t, Timestamp: time.Now().UTC()}
+	marshaler := &jsonpb.Marshaler{}
+	value, err := marshaler.MarshalToString(todo)
+	if err != nil {
+		return "", nil, err
+	}
+	p.ToDo = json.RawMessage(value)
+	if previous != nil {
+		value, err := marshaler.MarshalToString(previous)
+		if err != nil {
+			return "", nil, err
+		}
+		p.Previous = json.RawMessage(value)
+	}
+	body, err := json.Marshal(p)
+	return p.ID, body, err
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// work sends the deliveries of a receiver until Close
+func (d *Dispatcher) work(queue chan delivery) {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.done:
+			return
+		case dv := <-queue:
+			d.deliver(dv)
+		}
+	}
+}
+
+// deliver sends the delivery with the retries
+func (d *Dispatcher) deliver(dv delivery) {
+	backoff := d.config.Backoff
+	var err error
+	for attempt := 1; attempt <= d.config.MaxAttempts; attempt++ {
+		var retry bool
+		if retry, err = d.send(dv); err == nil {
+			return
+		}
+		if !retry || attempt == d.config.MaxAttempts {
+			d.fail(dv, attempt, err)
+			return
+		}
+		log.WithError(err).WithField("url", dv.url).WithField("attempt", attempt).Warn("webhook retry")
+		select {
+		case <-d.done:
+			d.fail(dv, attempt, err)
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > d.config.MaxBackoff {
+			backoff = d.config.MaxBackoff
+		}
+	}
+}
+
+// send posts the delivery once, it tells if the failure can be retried
+func (d *Dispatcher) send(dv delivery) (bool, error) {
+	request, err := http.NewRequestWithContext(d.ctx, http.MethodPost, dv.url, bytes.NewReader(dv.body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, dv.eventType)
+	request.Header.Set(HeaderDelivery, dv.id)
+	if d.config.Secret != "" {
+		request.Header.Set(HeaderSignature, Sign(d.config.Secret, dv.body))
+	}
+
+	response, err := d.config.Client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	response.Body.Close()
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode <= 299:
+		return false, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return true, fmt.Errorf("status %s", response.Status)
+	}
+	return false, fmt.Errorf("status %s", response.Status)
+}
+
+func (d *Dispatcher) fail(dv delivery, attempts int, err error) {
+	log.WithError(err).WithField("url", dv.url).WithField("event", dv.eventType).Error("webhook not delivered")
+	if err := d.deadLetter.write(dv, attempts, err); err != nil {
+		log.WithError(err).Error("webhook dead letter")
+	}
+}
+
+// Close stops the workers, the pending deliveries are lost and the sending ones are cancelled
+func (d *Dispatcher) Close() error {
+	close(d.done)
+	d.cancel()
+	d.wg.Wait()
+	return d.deadLetter.close()
+}
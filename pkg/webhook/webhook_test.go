@@ -0,0 +1,261 @@
+package webhook_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sjeandeaux/todo/pkg/tenant"
+	. "github.com/sjeandeaux/todo/pkg/webhook"
+	pb "github.com/sjeandeaux/todo/todo-grpc/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Suite")
+}
+
+// received a request of the receiver
+type received struct {
+	header  http.Header
+	body    []byte
+	payload Payload
+}
+
+// receiver answers the statuses in order then 200, it never answers if hang is set
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	hang     bool
+	requests chan received
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	body, _ := ioutil.ReadAll(request.Body)
+	rcv := received{header: request.Header, body: body}
+	json.Unmarshal(body, &rcv.payload)
+	r.requests <- rcv
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hang {
+		<-request.Context().Done()
+		return
+	}
+	if len(r.statuses) > 0 {
+		w.WriteHeader(r.statuses[0])
+		r.statuses = r.statuses[1:]
+	}
+}
+
+var _ = Describe("Dispatcher", func() {
+
+	var (
+		dir        string
+		rcv        *receiver
+		server     *httptest.Server
+		dispatcher *Dispatcher
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "webhook")
+		Ω(err).NotTo(HaveOccurred())
+		rcv = &receiver{requests: make(chan received, 10)}
+		server = httptest.NewServer(rcv)
+
+		dispatcher, err = NewDispatcher(Config{
+			URLs:        []string{server.URL},
+			Secret:      "s3cr3t",
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			DeadLetter:  filepath.Join(dir, "dead.jsonl"),
+		})
+		Ω(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if dispatcher != nil {
+			dispatcher.Close()
+		}
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	deadLetters := func() []DeadLetter {
+		file, err := os.Open(filepath.Join(dir, "dead.jsonl"))
+		Ω(err).NotTo(HaveOccurred())
+		defer file.Close()
+		result := []DeadLetter{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var dl DeadLetter
+			Ω(json.Unmarshal(scanner.Bytes(), &dl)).Should(Succeed())
+			result = append(result, dl)
+		}
+		return result
+	}
+
+	Context("With a created todo", func() {
+		It("should post the signed event", func() {
+			dispatcher.Changed(&pb.Event{Type: pb.Event_CREATED, ToDo: &pb.ToDo{Id: "1", Title: "a"}})
+
+			var r received
+			Eventually(rcv.requests).Should(Receive(&r))
+			Ω(r.header.Get("Content-Type")).Should(Equal("application/json"))
+			Ω(r.header.Get(HeaderEvent)).Should(Equal(TypeCreated))
+			Ω(r.header.Get(HeaderDelivery)).Should(Equal(r.payload.ID))
+			Ω(r.header.Get(HeaderSignature)).Should(Equal(Sign("s3cr3t", r.body)))
+			Ω(r.payload.Type).Should(Equal(TypeCreated))
+			Ω(r.payload.ToDo).Should(MatchJSON(`{"id":"1","title":"a"}`))
+			Ω(r.payload.Previous).Should(BeEmpty())
+		})
+	})
+
+	Context("With a state change", func() {
+		It("should post the update and the state change", func() {
+			dispatcher.Changed(&pb.Event{Type: pb.Event_UPDATED, ToDo: &pb.ToDo{Id: "1", State: pb.ToDo_DONE}, Previous: &pb.ToDo{Id: "1"}})
+
+			var r received
+			Eventually(rcv.requests).Should(Receive(&r))
+			Ω(r.payload.Type).Should(Equal(TypeUpdated))
+			Eventually(rcv.requests).Should(Receive(&r))
+			Ω(r.payload.Type).Should(Equal(TypeStateChanged))
+			Ω(r.payload.ToDo).Should(MatchJSON(`{"id":"1","state":"DONE"}`))
+			Ω(r.payload.Previous).Should(MatchJSON(`{"id":"1"}`))
+		})
+	})
+
+	Context("With a todo restored then purged", func() {
+		It("should post both events", func() {
+			dispatcher.Changed(&pb.Event{Type: pb.Event_RESTORED, ToDo: &pb.ToDo{Id: "1"}})
+			dispatcher.Changed(&pb.Event{Type: pb.Event_PURGED, ToDo: &pb.ToDo{Id: "1"}})
+
+			var r received
+			Eventually(rcv.requests).Should(Receive(&r))
+			Ω(r.payload.Type).Should(Equal(TypeRestored))
+			Eventually(rcv.requests).Should(Receive(&r))
+			Ω(r.payload.Type).Should(Equal(TypePurged))
+		})
+	})
+
+	Context("With a reminder", func() {
+		It("should post it", func() {
+			Ω(dispatcher.Notify(context.TODO(), &pb.ToDo{Id: "1"})).Should(Succeed())
+
+			var r received
+			Eventually(rcv.requests).Should(Receive(&r))
+			Ω(r.payload.Type).Should(Equal(TypeReminder))
+		})
+	})
+
+	Context("With the receivers of a tenant", func() {
+		It("should post the events of the tenant only to them", func() {
+			acme := &receiver{requests: make(chan received, 10)}
+			acmeServer := httptest.NewServer(acme)
+			defer acmeServer.Close()
+			tenants, err := NewDispatcher(Config{URLs: []string{server.URL}, TenantURLs: map[string][]string{"acme": {acmeServer.URL}}})
+			Ω(err).NotTo(HaveOccurred())
+			defer tenants.Close()
+
+			tenants.Changed(&pb.Event{Type: pb.Event_CREATED, Tenant: "acme", ToDo: &pb.ToDo{Id: "1"}})
+			tenants.Changed(&pb.Event{Type: pb.Event_CREATED, Tenant: "globex", ToDo: &pb.ToDo{Id: "2"}})
+
+			var r received
+			Eventually(acme.requests).Should(Receive(&r))
+			Ω(r.payload.Tenant).Should(Equal("acme"))
+			Ω(r.payload.ToDo).Should(MatchJSON(`{"id":"1"}`))
+
+			Ω(tenants.Notify(tenant.NewContext(context.TODO(), "acme"), &pb.ToDo{Id: "3"})).Should(Succeed())
+			Eventually(acme.requests).Should(Receive(&r))
+			Ω(r.payload.Type).Should(Equal(TypeReminder))
+			Ω(r.payload.Tenant).Should(Equal("acme"))
+			Consistently(rcv.requests, 100*time.Millisecond).ShouldNot(Receive())
+			Ω(acme.requests).ShouldNot(Receive())
+		})
+	})
+
+	Context("With a receiver which fails then accepts", func() {
+		It("should retry the same delivery", func() {
+			rcv.statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+			dispatcher.Changed(&pb.Event{Type: pb.Event_DELETED, ToDo: &pb.ToDo{Id: "1"}})
+
+			var first, r received
+			Eventually(rcv.requests).Should(Receive(&first))
+			Eventually(rcv.requests).Should(Receive(&r))
+			Eventually(rcv.requests).Should(Receive(&r))
+			Ω(r.payload.ID).Should(Equal(first.payload.ID))
+			Consistently(rcv.requests, 100*time.Millisecond).ShouldNot(Receive())
+			Ω(deadLetters()).Should(BeEmpty())
+		})
+	})
+
+	Context("With a receiver which always fails", func() {
+		It("should write the delivery in the dead-letter file", func() {
+			rcv.statuses = []int{500, 500, 500}
+			dispatcher.Changed(&pb.Event{Type: pb.Event_CREATED, ToDo: &pb.ToDo{Id: "1"}})
+
+			for i := 0; i < 3; i++ {
+				Eventually(rcv.requests).Should(Receive())
+			}
+			Eventually(deadLetters).Should(HaveLen(1))
+			dl := deadLetters()[0]
+			Ω(dl.URL).Should(Equal(server.URL))
+			Ω(dl.Attempts).Should(Equal(3))
+			Ω(dl.Error).Should(ContainSubstring("500"))
+		})
+	})
+
+	Context("With a receiver which rejects", func() {
+		It("should not retry", func() {
+			rcv.statuses = []int{http.StatusBadRequest}
+			dispatcher.Changed(&pb.Event{Type: pb.Event_CREATED, ToDo: &pb.ToDo{Id: "1"}})
+
+			Eventually(rcv.requests).Should(Receive())
+			Eventually(deadLetters).Should(HaveLen(1))
+			Ω(deadLetters()[0].Attempts).Should(Equal(1))
+		})
+	})
+
+	Context("With a receiver which never answers", func() {
+		It("should cancel the delivery on Close", func() {
+			rcv.hang = true
+			dispatcher.Changed(&pb.Event{Type: pb.Event_CREATED, ToDo: &pb.ToDo{Id: "1"}})
+			Eventually(rcv.requests).Should(Receive())
+
+			closed := make(chan error, 1)
+			go func() { closed <- dispatcher.Close() }()
+			Eventually(closed).Should(Receive(BeNil()))
+			dispatcher = nil
+		})
+	})
+})
+
+var _ = Describe("ParseTenantURLs", func() {
+	It("should group the receivers by tenant", func() {
+		urls, err := ParseTenantURLs("acme=http://a/hook, acme=http://b/hook,globex=http://c/hook?x=1")
+		Ω(err).NotTo(HaveOccurred())
+		Ω(urls).Should(Equal(map[string][]string{
+			"acme":   {"http://a/hook", "http://b/hook"},
+			"globex": {"http://c/hook?x=1"},
+		}))
+	})
+
+	It("should refuse an entry without tenant or URL", func() {
+		_, err := ParseTenantURLs("http://a/hook")
+		Ω(err).Should(HaveOccurred())
+		_, err = ParseTenantURLs("acme=")
+		Ω(err).Should(HaveOccurred())
+	})
+})
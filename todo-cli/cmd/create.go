@@ -13,7 +13,7 @@ import (
 var createArgs = &client.ToDo{}
 
 var createCmd = &cobra.Command{
-	Use:   `create --title=<title> --description=<description> --state=[NOT_STARTED, IN_PROGRESS, DONE] --tags="tag1,tag2" --reminder=<duration>`,
+	Use:   `create --title=<title> --description=<description> --state=[NOT_STARTED, IN_PROGRESS, DONE] --tags="tag1,tag2" --reminder=<duration> --list=<list id>`,
 	Short: "Create a todo",
 	Run: func(createCmd *cobra.Command, args []string) {
 		client, err := cmdLine.client()
@@ -42,6 +42,8 @@ func init() {
 	createCmd.Flags().StringVarP(&createArgs.Description, "description", "", "description", "The description of todo")
 	createCmd.Flags().StringVarP(&createArgs.State, "state", "", "NOT_STARTED", "The state [NOT_STARTED, IN_PROGRESS, DONE]")
 	createCmd.Flags().StringSliceVarP(&createArgs.Tags, "tags", "", []string{}, "The tags tag1,...,tagN")
+	createCmd.Flags().StringVarP(&createArgs.Owner, "owner", "", "", "The owner of todo, only for an admin, the caller if empty")
+	createCmd.Flags().StringVarP(&createArgs.ListID, "list", "", "", "The ID of the list of todo, the role EDITOR is required on it")
 
 	now := time.Now().UTC().Add(1 * time.Hour)
 	createCmd.Flags().Int64VarP(&createArgs.Reminder, "reminder", "", now.Unix(), "The reminder")
@@ -8,11 +8,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var idDelete string
+var (
+	idDelete      string
+	versionDelete int64
+)
 
 var deleteCmd = &cobra.Command{
-	Use:   `delete --id=<title>`,
-	Short: "Delete a todo by ID",
+	Use:   `delete --id=<id> --if-version=<version>`,
+	Short: "Move a todo to the trash by ID",
 	Run: func(createCmd *cobra.Command, args []string) {
 		client, err := cmdLine.client()
 		if err != nil {
@@ -22,8 +25,8 @@ var deleteCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), cmdLine.timeout)
 		defer cancel()
 
-		// Create the todo
-		resp, err := client.Delete(ctx, idDelete)
+		// Delete the todo
+		resp, err := client.Delete(ctx, idDelete, versionDelete)
 
 		if err != nil {
 			log.Errorf("grpc client: %v\n", err)
@@ -37,4 +40,5 @@ var deleteCmd = &cobra.Command{
 
 func init() {
 	deleteCmd.Flags().StringVarP(&idDelete, "id", "", "", "The ID of todo")
+	deleteCmd.Flags().Int64VarP(&versionDelete, "if-version", "", 0, "Delete only if the todo still has this version, 0 to delete any version")
 }
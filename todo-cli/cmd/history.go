@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var idHistory string
+
+var historyCmd = &cobra.Command{
+	Use:   `history --id=<id>`,
+	Short: "Show the changes on a todo by ID",
+	Run: func(createCmd *cobra.Command, args []string) {
+		manager, err := cmdLine.client()
+		if err != nil {
+			log.Errorf("grpc client: %v\n", err)
+			os.Exit(1)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cmdLine.timeout)
+		defer cancel()
+
+		records, err := manager.History(ctx, idHistory)
+
+		if errors.Is(err, client.ErrNotFound) {
+			log.Infof("Todo:%q not found", idHistory)
+			return
+		}
+		if err != nil {
+			log.Errorf("grpc client: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, r := range records {
+			log.Infof("%s %s by %q version:%d request:%s", r.Time.Format(time.RFC3339), r.Type, r.Actor, r.Version, r.RequestID)
+			for _, c := range r.Changes {
+				log.Infof("  %s: %q -> %q", c.Field, c.OldValue, c.NewValue)
+			}
+		}
+	},
+}
+
+func init() {
+	historyCmd.Flags().StringVarP(&idHistory, "id", "", "", "The ID of todo")
+}
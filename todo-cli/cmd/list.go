@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var listArgs struct {
+	id      string
+	name    string
+	subject string
+	role    string
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Manage the lists of todos shared with other users",
+}
+
+var listCreateCmd = &cobra.Command{
+	Use:   `create --name=<name>`,
+	Short: "Create a list owned by the caller",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			id, err := manager.CreateList(ctx, listArgs.name)
+			if err != nil {
+				return err
+			}
+			log.Infof("ID:%q", id)
+			return nil
+		})
+	},
+}
+
+var listShareCmd = &cobra.Command{
+	Use:   `share --id=<id> --subject=<subject> --role=[VIEWER, EDITOR, OWNER]`,
+	Short: "Give a role on a list to a user",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			list, err := manager.ShareList(ctx, listArgs.id, listArgs.subject, listArgs.role)
+			if err != nil {
+				return err
+			}
+			log.Infof("List:%v", *list)
+			return nil
+		})
+	},
+}
+
+var listUnshareCmd = &cobra.Command{
+	Use:   `unshare --id=<id> --subject=<subject>`,
+	Short: "Remove a user from a list",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			list, err := manager.UnshareList(ctx, listArgs.id, listArgs.subject)
+			if err != nil {
+				return err
+			}
+			log.Infof("List:%v", *list)
+			return nil
+		})
+	},
+}
+
+var listLsCmd = &cobra.Command{
+	Use:   `ls`,
+	Short: "List the lists owned by or shared with the caller",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			lists, err := manager.Lists(ctx)
+			if err != nil {
+				return err
+			}
+			for _, list := range lists {
+				log.Infof("List:%v", list)
+			}
+			return nil
+		})
+	},
+}
+
+var listDeleteCmd = &cobra.Command{
+	Use:   `delete --id=<id>`,
+	Short: "Delete a list without todo",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			deleted, err := manager.DeleteList(ctx, listArgs.id)
+			if err != nil {
+				return err
+			}
+			log.Infof("Deleted:%t", deleted)
+			return nil
+		})
+	},
+}
+
+func init() {
+	listCreateCmd.Flags().StringVarP(&listArgs.name, "name", "", "", "The name of the list")
+	for _, cmd := range []*cobra.Command{listShareCmd, listUnshareCmd, listDeleteCmd} {
+		cmd.Flags().StringVarP(&listArgs.id, "id", "", "", "The ID of the list")
+	}
+	for _, cmd := range []*cobra.Command{listShareCmd, listUnshareCmd} {
+		cmd.Flags().StringVarP(&listArgs.subject, "subject", "", "", "The subject of the user")
+	}
+	listShareCmd.Flags().StringVarP(&listArgs.role, "role", "", "VIEWER", "The role [VIEWER, EDITOR, OWNER]")
+
+	listCmd.AddCommand(listCreateCmd)
+	listCmd.AddCommand(listShareCmd)
+	listCmd.AddCommand(listUnshareCmd)
+	listCmd.AddCommand(listLsCmd)
+	listCmd.AddCommand(listDeleteCmd)
+}
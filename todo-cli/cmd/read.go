@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +16,7 @@ var readCmd = &cobra.Command{
 	Use:   `read --id=<title>`,
 	Short: "Read a todo by ID",
 	Run: func(createCmd *cobra.Command, args []string) {
-		client, err := cmdLine.client()
+		manager, err := cmdLine.client()
 		if err != nil {
 			log.Errorf("grpc client: %v\n", err)
 			os.Exit(1)
@@ -23,8 +25,12 @@ var readCmd = &cobra.Command{
 		defer cancel()
 
 		// Create the todo
-		resp, err := client.Read(ctx, idRead)
+		resp, err := manager.Read(ctx, idRead)
 
+		if errors.Is(err, client.ErrNotFound) {
+			log.Infof("Todo:%q not found", idRead)
+			return
+		}
 		if err != nil {
 			log.Errorf("grpc client: %v\n", err)
 			os.Exit(1)
@@ -1,13 +1,16 @@
 package cmd
 
 import (
-	"crypto/tls"
+	"context"
 	"net"
+	"os"
 	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	log "github.com/sirupsen/logrus"
 	"github.com/sjeandeaux/todo/pkg/client"
+	"github.com/sjeandeaux/todo/pkg/config"
+	"github.com/sjeandeaux/todo/pkg/tls"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	grpc "google.golang.org/grpc"
@@ -20,6 +23,11 @@ type commandLine struct {
 	host      string
 	port      string
 	plaintext bool
+	caCert    string
+	cert      string
+	key       string
+	token     string
+	tenant    string
 
 	timeout time.Duration
 }
@@ -37,10 +45,27 @@ func (c *commandLine) client() (*client.ToDoManager, error) {
 	if c.plaintext {
 		opts = append(opts, grpc.WithInsecure())
 	} else {
-		var tlsConf tls.Config
-		tlsConf.InsecureSkipVerify = true
-		transportCredentials := credentials.NewTLS(&tlsConf)
-		opts = append(opts, grpc.WithTransportCredentials(transportCredentials))
+		tlsConf, err := tls.NewClient(c.caCert, c.cert, c.key)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConf)))
+	}
+
+	token := c.token
+	if token == "" {
+		// TODO_TOKEN is not the default value of the flag to keep it out of the help
+		token = config.LookupEnvOrString("TODO_TOKEN", "")
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(client.Token{Value: token, AllowInsecure: c.plaintext}))
+	}
+	tenant := c.tenant
+	if tenant == "" {
+		tenant = config.LookupEnvOrString("TODO_TENANT", "")
+	}
+	if tenant != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(client.Tenant(tenant)))
 	}
 
 	cc, err := grpc.Dial(net.JoinHostPort(c.host, c.port), opts...)
@@ -58,6 +83,18 @@ var rootCmd = &cobra.Command{
 	Long:  "A client which communicates with the daemon todod",
 }
 
+// run calls fn with a context bounded by the timeout, it exits on error
+func run(fn func(ctx context.Context) error) {
+	if err := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), cmdLine.timeout)
+		defer cancel()
+		return fn(ctx)
+	}(); err != nil {
+		log.Errorf("grpc client: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 // Execute executes the root command.
 func Execute() error {
 	return rootCmd.Execute()
@@ -72,16 +109,31 @@ func init() {
 	rootCmd.PersistentFlags().DurationVarP(&cmdLine.timeout, "timeout", "t", 3*time.Second, "The timeout when it calls the daemon")
 	rootCmd.PersistentFlags().StringVarP(&cmdLine.host, "host", "o", "localhost", "The host")
 	rootCmd.PersistentFlags().BoolVarP(&cmdLine.plaintext, "plaintext", "a", false, "The GRPC is plaintext")
+	rootCmd.PersistentFlags().StringVar(&cmdLine.caCert, "ca-cert", "", "The PEM CA which verifies the server, the system CAs if empty")
+	rootCmd.PersistentFlags().StringVar(&cmdLine.cert, "cert", "", "The PEM client certificate for the mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&cmdLine.key, "key", "", "The PEM key of the client certificate")
+
+	rootCmd.PersistentFlags().StringVar(&cmdLine.token, "token", "", "The API key or the JWT sent to the daemon (TODO_TOKEN)")
+	rootCmd.PersistentFlags().StringVar(&cmdLine.tenant, "tenant", "", "The tenant of the todos when the token doesn't have one (TODO_TENANT)")
 
 	viper.BindPFlag("log-level", rootCmd.PersistentFlags().Lookup("log-level"))
 	viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("host"))
 	viper.BindPFlag("port", rootCmd.PersistentFlags().Lookup("port"))
+	viper.BindPFlag("ca-cert", rootCmd.PersistentFlags().Lookup("ca-cert"))
+	viper.BindPFlag("cert", rootCmd.PersistentFlags().Lookup("cert"))
+	viper.BindPFlag("key", rootCmd.PersistentFlags().Lookup("key"))
 
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(readCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(purgeCmd)
+	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(versionCmd)
 
 }
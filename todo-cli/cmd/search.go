@@ -3,24 +3,22 @@ package cmd
 import (
 	"context"
 	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/client"
 	"github.com/spf13/cobra"
 )
 
-type commandLineSearchArgs struct {
-	pattern string
-	tags    []string
-	states  []string
-}
+var searchArgs = client.Query{}
 
-var searchArgs = commandLineSearchArgs{}
+var searchStream bool
 
 var searchCmd = &cobra.Command{
-	Use:   `search --pattern=<pattern> --tags=<tags> --states=<>`,
+	Use:   `search --pattern=<pattern> --match=<mode> --tags=<tags> --states=<> --order-by=<order> --page-size=<size> --list=<list id> --created-after=<time> --completed-before=<time> --stream`,
 	Short: "Search todo",
 	Run: func(createCmd *cobra.Command, args []string) {
-		client, err := cmdLine.client()
+		manager, err := cmdLine.client()
 		if err != nil {
 			log.Errorf("grpc client: %v\n", err)
 			os.Exit(1)
@@ -28,22 +26,71 @@ var searchCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), cmdLine.timeout)
 		defer cancel()
 
-		// Create the todo
-		resp, err := client.Search(ctx, searchArgs.pattern, searchArgs.tags, searchArgs.states)
+		if searchStream {
+			err := manager.Stream(ctx, searchArgs, func(todo client.ToDo) error {
+				log.Infof("Todo:%v", todo)
+				return nil
+			})
+			if err != nil {
+				log.Errorf("grpc client: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 
-		if err != nil {
-			log.Errorf("grpc client: %v\n", err)
-			os.Exit(1)
+		// Walk all the pages
+		it := manager.Iterate(ctx, searchArgs)
+		for it.Next() {
+			log.Infof("Todo:%v", it.ToDo())
 		}
 
-		for _, todo := range resp {
-			log.Infof("Todo:%v", todo)
+		if err := it.Err(); err != nil {
+			log.Errorf("grpc client: %v\n", err)
+			os.Exit(1)
 		}
+		log.Infof("Total:%d", it.TotalSize())
 	},
 }
 
 func init() {
-	searchCmd.Flags().StringVarP(&searchArgs.pattern, "pattern", "", "", "The pattern on description ex:.*")
-	searchCmd.Flags().StringSliceVarP(&searchArgs.states, "states", "", []string{}, "The states NOT_STARTED, IN_PROGRESS or DONE")
-	searchCmd.Flags().StringSliceVarP(&searchArgs.tags, "tags", "", []string{}, "The tags")
+	searchCmd.Flags().StringVarP(&searchArgs.Pattern, "pattern", "", "", "The pattern on description ex:micro")
+	searchCmd.Flags().StringVarP(&searchArgs.MatchMode, "match", "", client.MatchLiteral, "How the pattern matches [LITERAL, PREFIX, REGEX, FULLTEXT]")
+	searchCmd.Flags().StringSliceVarP(&searchArgs.States, "states", "", []string{}, "The states NOT_STARTED, IN_PROGRESS or DONE")
+	searchCmd.Flags().StringSliceVarP(&searchArgs.Tags, "tags", "", []string{}, "The tags")
+	searchCmd.Flags().StringVarP(&searchArgs.OrderBy, "order-by", "", "", `The order created, updated, completed, reminder, title or state, "title desc" for a descending order`)
+	searchCmd.Flags().Int32VarP(&searchArgs.PageSize, "page-size", "", 0, "The number of todos fetched by call")
+	searchCmd.Flags().StringVarP(&searchArgs.Owner, "owner", "", "", "The owner of the todos, only for an admin, all the owners if empty")
+	searchCmd.Flags().StringVarP(&searchArgs.ListID, "list", "", "", "The ID of the list of the todos, all the todos of the list for its members")
+	searchCmd.Flags().Var(timeValue{&searchArgs.Created.After}, "created-after", "The todos created from this time in RFC 3339 ex:2019-11-06T13:16:20Z")
+	searchCmd.Flags().Var(timeValue{&searchArgs.Created.Before}, "created-before", "The todos created before this time in RFC 3339")
+	searchCmd.Flags().Var(timeValue{&searchArgs.Updated.After}, "updated-after", "The todos changed from this time in RFC 3339")
+	searchCmd.Flags().Var(timeValue{&searchArgs.Updated.Before}, "updated-before", "The todos last changed before this time in RFC 3339")
+	searchCmd.Flags().Var(timeValue{&searchArgs.Completed.After}, "completed-after", "The todos completed from this time in RFC 3339")
+	searchCmd.Flags().Var(timeValue{&searchArgs.Completed.Before}, "completed-before", "The todos completed before this time in RFC 3339")
+	searchCmd.Flags().BoolVarP(&searchStream, "stream", "", false, "Receive the todos one by one, the page size is ignored")
+}
+
+// timeValue the flag of a time in RFC 3339, zero if it is not set
+type timeValue struct {
+	t *time.Time
+}
+
+func (v timeValue) String() string {
+	if v.t == nil || v.t.IsZero() {
+		return ""
+	}
+	return v.t.Format(time.RFC3339)
+}
+
+func (v timeValue) Set(s string) error {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return err
+	}
+	*v.t = t
+	return nil
+}
+
+func (v timeValue) Type() string {
+	return "time"
 }
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var trashArgs struct {
+	id string
+}
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Show the deleted todos until they are restored or purged",
+}
+
+var trashLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List the todos in the trash, the last deleted first",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			it := manager.Iterate(ctx, client.Query{Trashed: true, OrderBy: "deleted desc"})
+			for it.Next() {
+				todo := it.ToDo()
+				log.Infof("Deleted at %s:%v", todo.DeletedAt.Format(time.RFC3339), todo)
+			}
+			return it.Err()
+		})
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   `restore --id=<id>`,
+	Short: "Take a todo out of the trash by ID",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			restored, err := manager.Restore(ctx, trashArgs.id)
+			if err != nil {
+				return err
+			}
+			log.Infof("Restored:%t", restored)
+			return nil
+		})
+	},
+}
+
+var purgeCmd = &cobra.Command{
+	Use:   `purge --id=<id>`,
+	Short: "Remove a todo from the trash for good by ID",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(ctx context.Context) error {
+			manager, err := cmdLine.client()
+			if err != nil {
+				return err
+			}
+			purged, err := manager.Purge(ctx, trashArgs.id)
+			if err != nil {
+				return err
+			}
+			log.Infof("Purged:%t", purged)
+			return nil
+		})
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{restoreCmd, purgeCmd} {
+		cmd.Flags().StringVarP(&trashArgs.id, "id", "", "", "The ID of the todo in the trash")
+	}
+
+	trashCmd.AddCommand(trashLsCmd)
+}
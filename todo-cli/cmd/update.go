@@ -12,9 +12,24 @@ import (
 var updateArgs = &client.ToDo{}
 
 var updateCmd = &cobra.Command{
-	Use:   `update --id=<id> --title=<title> --description=<description> --state=[NOT_STARTED, IN_PROGRESS, DONE] --tags="tag1,tag2" --reminder=<duration>`,
+	Use:   `update --id=<id> --title=<title> --description=<description> --state=[NOT_STARTED, IN_PROGRESS, DONE] --tags="tag1,tag2" --reminder=<duration> --list=<list id> --if-version=<version>`,
 	Short: "Update a todo",
 	Run: func(createCmd *cobra.Command, args []string) {
+		// Update only the fields given on the command line
+		paths := []string{}
+		for _, field := range []string{client.FieldTitle, client.FieldDescription, client.FieldState, client.FieldTags, client.FieldReminder} {
+			if createCmd.Flags().Changed(field) {
+				paths = append(paths, field)
+			}
+		}
+		if createCmd.Flags().Changed("list") {
+			paths = append(paths, client.FieldListID)
+		}
+		if len(paths) == 0 {
+			log.Error("nothing to update")
+			os.Exit(1)
+		}
+
 		client, err := cmdLine.client()
 		if err != nil {
 			log.Errorf("grpc client: %v\n", err)
@@ -23,8 +38,7 @@ var updateCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), cmdLine.timeout)
 		defer cancel()
 
-		// Create the todo
-		resp, err := client.Update(ctx, *updateArgs)
+		resp, err := client.Update(ctx, *updateArgs, paths)
 
 		if err != nil {
 			log.Errorf("grpc client: %v\n", err)
@@ -43,4 +57,6 @@ func init() {
 	updateCmd.Flags().StringVarP(&updateArgs.State, "state", "", "", "The state [NOT_STARTED, IN_PROGRESS, DONE]")
 	updateCmd.Flags().StringSliceVarP(&updateArgs.Tags, "tags", "", []string{}, "The tags tag1,...,tagN")
 	updateCmd.Flags().Int64VarP(&updateArgs.Reminder, "reminder", "", 0, "The reminder")
+	updateCmd.Flags().StringVarP(&updateArgs.ListID, "list", "", "", "The ID of the list of todo, empty to remove it from its list")
+	updateCmd.Flags().Int64VarP(&updateArgs.Version, "if-version", "", 0, "Update only if the todo still has this version, 0 to update any version")
 }
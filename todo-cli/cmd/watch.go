@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sjeandeaux/todo/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var watchArgs = client.Query{}
+
+var watchCmd = &cobra.Command{
+	Use:   `watch --pattern=<pattern> --match=<mode> --tags=<tags> --states=<>`,
+	Short: "Watch the changes on the todos until Ctrl+C",
+	Run: func(createCmd *cobra.Command, args []string) {
+		manager, err := cmdLine.client()
+		if err != nil {
+			log.Errorf("grpc client: %v\n", err)
+			os.Exit(1)
+		}
+		// The watch has no timeout, it stops on interrupt
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			cancel()
+		}()
+
+		err = manager.Watch(ctx, watchArgs, func(e client.Event) error {
+			log.Infof("%s:%v", e.Type, e.ToDo)
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("grpc client: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVarP(&watchArgs.Pattern, "pattern", "", "", "The pattern on description ex:micro")
+	watchCmd.Flags().StringVarP(&watchArgs.MatchMode, "match", "", client.MatchLiteral, "How the pattern matches [LITERAL, PREFIX, REGEX, FULLTEXT]")
+	watchCmd.Flags().StringSliceVarP(&watchArgs.States, "states", "", []string{}, "The states NOT_STARTED, IN_PROGRESS or DONE")
+	watchCmd.Flags().StringSliceVarP(&watchArgs.Tags, "tags", "", []string{}, "The tags")
+	watchCmd.Flags().StringVarP(&watchArgs.Owner, "owner", "", "", "The owner of the todos, only for an admin, all the owners if empty")
+	watchCmd.Flags().StringVarP(&watchArgs.ListID, "list", "", "", "The ID of the list of the todos, all the todos of the list for its members")
+}
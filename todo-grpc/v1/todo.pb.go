@@ -7,7 +7,11 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	any "github.com/golang/protobuf/ptypes/any"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -54,6 +58,111 @@ func (ToDo_State) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_7238c4084676f823, []int{0, 0}
 }
 
+// MatchMode how the pattern matches the todos
+type SearchRequest_MatchMode int32
+
+const (
+	// the description contains the pattern
+	SearchRequest_LITERAL SearchRequest_MatchMode = 0
+	// the description starts with the pattern
+	SearchRequest_PREFIX SearchRequest_MatchMode = 1
+	// the description matches the regular expression in the RE2 syntax, without nested repetitions nor repeated alternations
+	SearchRequest_REGEX SearchRequest_MatchMode = 2
+	// the title or the description contain all the words of the pattern, whatever the case
+	SearchRequest_FULLTEXT SearchRequest_MatchMode = 3
+)
+
+var SearchRequest_MatchMode_name = map[int32]string{
+	0: "LITERAL",
+	1: "PREFIX",
+	2: "REGEX",
+	3: "FULLTEXT",
+}
+
+var SearchRequest_MatchMode_value = map[string]int32{
+	"LITERAL":  0,
+	"PREFIX":   1,
+	"REGEX":    2,
+	"FULLTEXT": 3,
+}
+
+func (x SearchRequest_MatchMode) String() string {
+	return proto.EnumName(SearchRequest_MatchMode_name, int32(x))
+}
+
+func (SearchRequest_MatchMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{21, 0}
+}
+
+// Type of change
+type Event_Type int32
+
+const (
+	Event_CREATED Event_Type = 0
+	Event_UPDATED Event_Type = 1
+	Event_DELETED Event_Type = 2
+	// the todo is out of the trash
+	Event_RESTORED Event_Type = 3
+	// the todo is removed from the trash for good
+	Event_PURGED Event_Type = 4
+)
+
+var Event_Type_name = map[int32]string{
+	0: "CREATED",
+	1: "UPDATED",
+	2: "DELETED",
+	3: "RESTORED",
+	4: "PURGED",
+}
+
+var Event_Type_value = map[string]int32{
+	"CREATED":  0,
+	"UPDATED":  1,
+	"DELETED":  2,
+	"RESTORED": 3,
+	"PURGED":   4,
+}
+
+func (x Event_Type) String() string {
+	return proto.EnumName(Event_Type_name, int32(x))
+}
+
+func (Event_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{24, 0}
+}
+
+// Role of a member
+type List_Role int32
+
+const (
+	// reads the todos of the list
+	List_VIEWER List_Role = 0
+	// creates, updates and deletes the todos of the list too
+	List_EDITOR List_Role = 1
+	// renames, shares and deletes the list too
+	List_OWNER List_Role = 2
+)
+
+var List_Role_name = map[int32]string{
+	0: "VIEWER",
+	1: "EDITOR",
+	2: "OWNER",
+}
+
+var List_Role_value = map[string]int32{
+	"VIEWER": 0,
+	"EDITOR": 1,
+	"OWNER":  2,
+}
+
+func (x List_Role) String() string {
+	return proto.EnumName(List_Role_name, int32(x))
+}
+
+func (List_Role) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{28, 0}
+}
+
 // ToDo a task to do
 type ToDo struct {
 	// Unique ID
@@ -67,10 +176,24 @@ type ToDo struct {
 	// Date to remind
 	Reminder *timestamp.Timestamp `protobuf:"bytes,5,opt,name=reminder,proto3" json:"reminder,omitempty"`
 	// State of todo
-	State                ToDo_State `protobuf:"varint,6,opt,name=state,proto3,enum=v1.ToDo_State" json:"state,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	State ToDo_State `protobuf:"varint,6,opt,name=state,proto3,enum=v1.ToDo_State" json:"state,omitempty"`
+	// Owner the subject of the caller who created the todo, set by the server
+	Owner string `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`
+	// ListId the ID of the list of the todo, empty if the todo is in no list
+	ListId string `protobuf:"bytes,8,opt,name=listId,proto3" json:"listId,omitempty"`
+	// Version incremented by each change of the todo from 1, set by the server
+	Version int64 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	// CreatedAt the time of the creation, set by the server
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,10,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	// UpdatedAt the time of the last change, set by the server
+	UpdatedAt *timestamp.Timestamp `protobuf:"bytes,11,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	// CompletedAt the time the state moved to DONE, unset if the todo isn't DONE, set by the server
+	CompletedAt *timestamp.Timestamp `protobuf:"bytes,12,opt,name=completedAt,proto3" json:"completedAt,omitempty"`
+	// DeletedAt the time the todo moved to the trash, unset if it isn't in the trash, set by the server
+	DeletedAt            *timestamp.Timestamp `protobuf:"bytes,13,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *ToDo) Reset()         { *m = ToDo{} }
@@ -140,6 +263,105 @@ func (m *ToDo) GetState() ToDo_State {
 	return ToDo_NOT_STARTED
 }
 
+func (m *ToDo) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *ToDo) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *ToDo) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ToDo) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *ToDo) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *ToDo) GetCompletedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CompletedAt
+	}
+	return nil
+}
+
+func (m *ToDo) GetDeletedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeletedAt
+	}
+	return nil
+}
+
+// TimeRange the times from after included to before excluded, a missing bound is open
+type TimeRange struct {
+	// after the first time of the range
+	After *timestamp.Timestamp `protobuf:"bytes,1,opt,name=after,proto3" json:"after,omitempty"`
+	// before the time which follows the range
+	Before               *timestamp.Timestamp `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *TimeRange) Reset()         { *m = TimeRange{} }
+func (m *TimeRange) String() string { return proto.CompactTextString(m) }
+func (*TimeRange) ProtoMessage()    {}
+func (*TimeRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{1}
+}
+
+func (m *TimeRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TimeRange.Unmarshal(m, b)
+}
+func (m *TimeRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TimeRange.Marshal(b, m, deterministic)
+}
+func (m *TimeRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimeRange.Merge(m, src)
+}
+func (m *TimeRange) XXX_Size() int {
+	return xxx_messageInfo_TimeRange.Size(m)
+}
+func (m *TimeRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimeRange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimeRange proto.InternalMessageInfo
+
+func (m *TimeRange) GetAfter() *timestamp.Timestamp {
+	if m != nil {
+		return m.After
+	}
+	return nil
+}
+
+func (m *TimeRange) GetBefore() *timestamp.Timestamp {
+	if m != nil {
+		return m.Before
+	}
+	return nil
+}
+
 // CreateRequest a request of creation
 type CreateRequest struct {
 	// The toDo to add
@@ -153,7 +375,7 @@ func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
 func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRequest) ProtoMessage()    {}
 func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{1}
+	return fileDescriptor_7238c4084676f823, []int{2}
 }
 
 func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
@@ -194,7 +416,7 @@ func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
 func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResponse) ProtoMessage()    {}
 func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{2}
+	return fileDescriptor_7238c4084676f823, []int{3}
 }
 
 func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
@@ -235,7 +457,7 @@ func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
 func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadRequest) ProtoMessage()    {}
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{3}
+	return fileDescriptor_7238c4084676f823, []int{4}
 }
 
 func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
@@ -276,7 +498,7 @@ func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
 func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse) ProtoMessage()    {}
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{4}
+	return fileDescriptor_7238c4084676f823, []int{5}
 }
 
 func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
@@ -307,7 +529,13 @@ func (m *ReadResponse) GetToDo() *ToDo {
 // UpdateRequest the todo to update
 type UpdateRequest struct {
 	// Task entity to update
-	ToDo                 *ToDo    `protobuf:"bytes,1,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	ToDo *ToDo `protobuf:"bytes,1,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	// Fields of toDo to update (title, description, tags, reminder, state, listId),
+	// a listed field which is empty is cleared, except title which can't be cleared (INVALID_ARGUMENT).
+	// Without mask the tags and the state are updated and the other fields only when they are set.
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+	// Version the todo must have, ABORTED if it has changed, no check if 0
+	ExpectedVersion      int64    `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -317,7 +545,7 @@ func (m *UpdateRequest) Reset()         { *m = UpdateRequest{} }
 func (m *UpdateRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateRequest) ProtoMessage()    {}
 func (*UpdateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{5}
+	return fileDescriptor_7238c4084676f823, []int{6}
 }
 
 func (m *UpdateRequest) XXX_Unmarshal(b []byte) error {
@@ -345,6 +573,20 @@ func (m *UpdateRequest) GetToDo() *ToDo {
 	return nil
 }
 
+func (m *UpdateRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UpdateRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
 // UpdateResponse the updated todo
 type UpdateResponse struct {
 	Updated              int64    `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
@@ -357,7 +599,7 @@ func (m *UpdateResponse) Reset()         { *m = UpdateResponse{} }
 func (m *UpdateResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateResponse) ProtoMessage()    {}
 func (*UpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{6}
+	return fileDescriptor_7238c4084676f823, []int{7}
 }
 
 func (m *UpdateResponse) XXX_Unmarshal(b []byte) error {
@@ -385,10 +627,12 @@ func (m *UpdateResponse) GetUpdated() int64 {
 	return 0
 }
 
-// DeleteRequest the todo to delete
+// DeleteRequest the todo to move to the trash
 type DeleteRequest struct {
 	// ID of todo
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Version the todo must have, ABORTED if it has changed, no check if 0
+	ExpectedVersion      int64    `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -398,7 +642,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{7}
+	return fileDescriptor_7238c4084676f823, []int{8}
 }
 
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
@@ -426,6 +670,13 @@ func (m *DeleteRequest) GetId() string {
 	return ""
 }
 
+func (m *DeleteRequest) GetExpectedVersion() int64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
 // DeleteResponse the delete response
 type DeleteResponse struct {
 	Deleted              int64    `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
@@ -438,7 +689,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{8}
+	return fileDescriptor_7238c4084676f823, []int{9}
 }
 
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
@@ -466,231 +717,2457 @@ func (m *DeleteResponse) GetDeleted() int64 {
 	return 0
 }
 
-// SearchRequest the search request
-type SearchRequest struct {
-	// pattern in description to filter
-	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
-	// tags to filter
-	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
-	// states to filter if empty all the state
-	States               []ToDo_State `protobuf:"varint,3,rep,packed,name=states,proto3,enum=v1.ToDo_State" json:"states,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+// RestoreRequest the todo to take out of the trash
+type RestoreRequest struct {
+	// ID of the todo in the trash
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
-func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
-func (*SearchRequest) ProtoMessage()    {}
-func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{9}
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{10}
 }
 
-func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SearchRequest.Unmarshal(m, b)
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
 }
-func (m *SearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SearchRequest.Marshal(b, m, deterministic)
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
 }
-func (m *SearchRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchRequest.Merge(m, src)
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
 }
-func (m *SearchRequest) XXX_Size() int {
-	return xxx_messageInfo_SearchRequest.Size(m)
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
 }
-func (m *SearchRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchRequest.DiscardUnknown(m)
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchRequest proto.InternalMessageInfo
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
 
-func (m *SearchRequest) GetPattern() string {
+func (m *RestoreRequest) GetId() string {
 	if m != nil {
-		return m.Pattern
+		return m.Id
 	}
 	return ""
 }
 
-func (m *SearchRequest) GetTags() []string {
-	if m != nil {
-		return m.Tags
-	}
-	return nil
+// RestoreResponse the number of restored todos
+type RestoreResponse struct {
+	Restored             int64    `protobuf:"varint,1,opt,name=restored,proto3" json:"restored,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SearchRequest) GetStates() []ToDo_State {
+func (m *RestoreResponse) Reset()         { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()    {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{11}
+}
+
+func (m *RestoreResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreResponse.Unmarshal(m, b)
+}
+func (m *RestoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreResponse.Merge(m, src)
+}
+func (m *RestoreResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreResponse.Size(m)
+}
+func (m *RestoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreResponse proto.InternalMessageInfo
+
+func (m *RestoreResponse) GetRestored() int64 {
 	if m != nil {
-		return m.States
+		return m.Restored
 	}
-	return nil
+	return 0
 }
 
-// SearchResponse the todos
-type SearchResponse struct {
-	// List of Todos
-	ToDos                []*ToDo  `protobuf:"bytes,1,rep,name=toDos,proto3" json:"toDos,omitempty"`
+// PurgeRequest the todo to remove from the trash for good
+type PurgeRequest struct {
+	// ID of the todo in the trash
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
-func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
-func (*SearchResponse) ProtoMessage()    {}
-func (*SearchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7238c4084676f823, []int{10}
+func (m *PurgeRequest) Reset()         { *m = PurgeRequest{} }
+func (m *PurgeRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeRequest) ProtoMessage()    {}
+func (*PurgeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{12}
 }
 
-func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SearchResponse.Unmarshal(m, b)
+func (m *PurgeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeRequest.Unmarshal(m, b)
 }
-func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SearchResponse.Marshal(b, m, deterministic)
+func (m *PurgeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeRequest.Marshal(b, m, deterministic)
 }
-func (m *SearchResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchResponse.Merge(m, src)
+func (m *PurgeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeRequest.Merge(m, src)
 }
-func (m *SearchResponse) XXX_Size() int {
-	return xxx_messageInfo_SearchResponse.Size(m)
+func (m *PurgeRequest) XXX_Size() int {
+	return xxx_messageInfo_PurgeRequest.Size(m)
 }
-func (m *SearchResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchResponse.DiscardUnknown(m)
+func (m *PurgeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchResponse proto.InternalMessageInfo
+var xxx_messageInfo_PurgeRequest proto.InternalMessageInfo
 
-func (m *SearchResponse) GetToDos() []*ToDo {
+func (m *PurgeRequest) GetId() string {
 	if m != nil {
-		return m.ToDos
+		return m.Id
 	}
-	return nil
+	return ""
 }
 
-func init() {
-	proto.RegisterEnum("v1.ToDo_State", ToDo_State_name, ToDo_State_value)
-	proto.RegisterType((*ToDo)(nil), "v1.ToDo")
-	proto.RegisterType((*CreateRequest)(nil), "v1.CreateRequest")
-	proto.RegisterType((*CreateResponse)(nil), "v1.CreateResponse")
-	proto.RegisterType((*ReadRequest)(nil), "v1.ReadRequest")
-	proto.RegisterType((*ReadResponse)(nil), "v1.ReadResponse")
-	proto.RegisterType((*UpdateRequest)(nil), "v1.UpdateRequest")
-	proto.RegisterType((*UpdateResponse)(nil), "v1.UpdateResponse")
-	proto.RegisterType((*DeleteRequest)(nil), "v1.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "v1.DeleteResponse")
-	proto.RegisterType((*SearchRequest)(nil), "v1.SearchRequest")
-	proto.RegisterType((*SearchResponse)(nil), "v1.SearchResponse")
+// PurgeResponse the number of purged todos
+type PurgeResponse struct {
+	Purged               int64    `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("todo-grpc/todo.proto", fileDescriptor_7238c4084676f823) }
-
-var fileDescriptor_7238c4084676f823 = []byte{
-	// 513 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x4d, 0x8f, 0xd3, 0x30,
-	0x10, 0x25, 0x1f, 0xcd, 0x76, 0x27, 0xdb, 0x6c, 0xb1, 0xf6, 0x60, 0x55, 0xc0, 0x46, 0x11, 0x42,
-	0x65, 0x45, 0x13, 0xda, 0x95, 0xb8, 0x03, 0xa9, 0x10, 0x97, 0x16, 0x39, 0xe1, 0xc2, 0x65, 0x95,
-	0x6d, 0x4c, 0x89, 0xd4, 0xd6, 0x21, 0x71, 0xfb, 0xcf, 0xb9, 0x71, 0x40, 0xb6, 0xe3, 0xb6, 0x89,
-	0x10, 0xe2, 0xe6, 0x79, 0x33, 0xf3, 0xe6, 0x79, 0x9e, 0x13, 0xb8, 0xe1, 0x2c, 0x67, 0x93, 0x75,
-	0x55, 0xae, 0x22, 0x71, 0x0a, 0xcb, 0x8a, 0x71, 0x86, 0xcc, 0xc3, 0x74, 0x74, 0xbb, 0x66, 0x6c,
-	0xbd, 0xa1, 0x91, 0x44, 0x1e, 0xf7, 0xdf, 0x23, 0x5e, 0x6c, 0x69, 0xcd, 0xb3, 0x6d, 0xa9, 0x8a,
-	0x82, 0x5f, 0x06, 0xd8, 0x29, 0x8b, 0x19, 0xf2, 0xc0, 0x2c, 0x72, 0x6c, 0xf8, 0xc6, 0xf8, 0x92,
-	0x98, 0x45, 0x8e, 0x6e, 0xa0, 0xc7, 0x0b, 0xbe, 0xa1, 0xd8, 0x94, 0x90, 0x0a, 0x90, 0x0f, 0x6e,
-	0x4e, 0xeb, 0x55, 0x55, 0x94, 0xbc, 0x60, 0x3b, 0x6c, 0xc9, 0xdc, 0x39, 0x84, 0x10, 0xd8, 0x3c,
-	0x5b, 0xd7, 0xd8, 0xf6, 0xad, 0xf1, 0x25, 0x91, 0x67, 0xf4, 0x0e, 0xfa, 0x15, 0xdd, 0x16, 0xbb,
-	0x9c, 0x56, 0xb8, 0xe7, 0x1b, 0x63, 0x77, 0x36, 0x0a, 0x95, 0xb0, 0x50, 0x0b, 0x0b, 0x53, 0x2d,
-	0x8c, 0x1c, 0x6b, 0xd1, 0x4b, 0xe8, 0xd5, 0x3c, 0xe3, 0x14, 0x3b, 0xbe, 0x31, 0xf6, 0x66, 0x5e,
-	0x78, 0x98, 0x86, 0x42, 0x6c, 0x98, 0x08, 0x94, 0xa8, 0x64, 0x70, 0x0f, 0x3d, 0x19, 0xa3, 0x6b,
-	0x70, 0x17, 0xcb, 0xf4, 0x21, 0x49, 0xdf, 0x93, 0x74, 0x1e, 0x0f, 0x9f, 0x08, 0xe0, 0xf3, 0xe2,
-	0xe1, 0x0b, 0x59, 0x7e, 0x22, 0xf3, 0x24, 0x19, 0x1a, 0xa8, 0x0f, 0x76, 0xbc, 0x5c, 0xcc, 0x87,
-	0x66, 0x30, 0x81, 0xc1, 0xc7, 0x8a, 0x0a, 0x16, 0xfa, 0x73, 0x4f, 0x6b, 0x8e, 0x9e, 0x81, 0xcd,
-	0x59, 0xcc, 0xe4, 0x06, 0xdc, 0x59, 0x5f, 0x8f, 0x22, 0x12, 0x0d, 0x7c, 0xf0, 0x74, 0x79, 0x5d,
-	0xb2, 0x5d, 0x4d, 0xbb, 0xfb, 0x0a, 0x9e, 0x83, 0x4b, 0x68, 0x96, 0x6b, 0xba, 0x6e, 0xfa, 0x0d,
-	0x5c, 0xa9, 0x74, 0xd3, 0xfe, 0xef, 0x71, 0x13, 0x18, 0x7c, 0x2d, 0xf3, 0xff, 0x56, 0x77, 0x07,
-	0x9e, 0x2e, 0x6f, 0xe8, 0x31, 0x5c, 0xec, 0x25, 0xa2, 0x34, 0x58, 0x44, 0x87, 0xc1, 0x2d, 0x0c,
-	0x62, 0xba, 0xa1, 0x27, 0xea, 0xae, 0xd2, 0x3b, 0xf0, 0x74, 0xc1, 0x89, 0x2c, 0x97, 0xc8, 0x91,
-	0xac, 0x09, 0x03, 0x0a, 0x83, 0x84, 0x66, 0xd5, 0xea, 0x87, 0x26, 0xc3, 0x70, 0x51, 0x66, 0x9c,
-	0xd3, 0x6a, 0xd7, 0x30, 0xea, 0xf0, 0xf8, 0x2e, 0xcc, 0xb3, 0x77, 0xf1, 0x0a, 0x1c, 0x69, 0x61,
-	0x8d, 0x2d, 0xdf, 0xfa, 0x8b, 0xc1, 0x4d, 0x36, 0x78, 0x0b, 0x9e, 0x1e, 0xd3, 0x48, 0x7a, 0x01,
-	0x3d, 0x71, 0xf3, 0x1a, 0x1b, 0xbe, 0xd5, 0x5a, 0x88, 0x82, 0x67, 0xbf, 0x0d, 0x70, 0x45, 0x9c,
-	0xd0, 0xea, 0x50, 0xac, 0x28, 0x8a, 0xc0, 0x51, 0xfe, 0xa1, 0xa7, 0xa2, 0xb4, 0x65, 0xfd, 0x08,
-	0x9d, 0x43, 0xcd, 0x80, 0xd7, 0x60, 0x0b, 0xbf, 0xd0, 0xb5, 0xc8, 0x9d, 0x19, 0x3b, 0x1a, 0x9e,
-	0x80, 0xa6, 0x34, 0x02, 0x47, 0x6d, 0x5f, 0x71, 0xb7, 0x8c, 0x53, 0xdc, 0x1d, 0x73, 0x22, 0x70,
-	0xd4, 0x86, 0x55, 0x43, 0xcb, 0x0e, 0xd5, 0xd0, 0x31, 0x20, 0x02, 0x47, 0xdd, 0x5f, 0x35, 0xb4,
-	0x56, 0xae, 0x1a, 0xda, 0xeb, 0xf9, 0xe0, 0x7d, 0xbb, 0x3a, 0xfd, 0x12, 0x0e, 0xd3, 0x47, 0x47,
-	0x7e, 0x66, 0xf7, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0xd7, 0xfb, 0xa7, 0xd4, 0x29, 0x04, 0x00,
-	0x00,
+func (m *PurgeResponse) Reset()         { *m = PurgeResponse{} }
+func (m *PurgeResponse) String() string { return proto.CompactTextString(m) }
+func (*PurgeResponse) ProtoMessage()    {}
+func (*PurgeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{13}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *PurgeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeResponse.Unmarshal(m, b)
+}
+func (m *PurgeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeResponse.Marshal(b, m, deterministic)
+}
+func (m *PurgeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeResponse.Merge(m, src)
+}
+func (m *PurgeResponse) XXX_Size() int {
+	return xxx_messageInfo_PurgeResponse.Size(m)
+}
+func (m *PurgeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeResponse.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_PurgeResponse proto.InternalMessageInfo
 
-// ToDoServiceClient is the client API for ToDoService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ToDoServiceClient interface {
-	// Create new todo
-	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
-	// Read the todo
-	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
-	// Update a todo
-	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
-	// Delete a todo
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	// Search a todos
-	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+func (m *PurgeResponse) GetPurged() int64 {
+	if m != nil {
+		return m.Purged
+	}
+	return 0
 }
 
-type toDoServiceClient struct {
+// BatchStatus the failure of an item of a batch, like the status of a failed call
+type BatchStatus struct {
+	// Code the gRPC code
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Details the details of the failure, ex: the field violations
+	Details              []*any.Any `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *BatchStatus) Reset()         { *m = BatchStatus{} }
+func (m *BatchStatus) String() string { return proto.CompactTextString(m) }
+func (*BatchStatus) ProtoMessage()    {}
+func (*BatchStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{14}
+}
+
+func (m *BatchStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchStatus.Unmarshal(m, b)
+}
+func (m *BatchStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchStatus.Marshal(b, m, deterministic)
+}
+func (m *BatchStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchStatus.Merge(m, src)
+}
+func (m *BatchStatus) XXX_Size() int {
+	return xxx_messageInfo_BatchStatus.Size(m)
+}
+func (m *BatchStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchStatus proto.InternalMessageInfo
+
+func (m *BatchStatus) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *BatchStatus) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *BatchStatus) GetDetails() []*any.Any {
+	if m != nil {
+		return m.Details
+	}
+	return nil
+}
+
+// BatchCreateRequest the todos to create in one call
+type BatchCreateRequest struct {
+	// Creations checked like Create, at most 1000
+	Requests []*CreateRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// Atomic creates all the todos or none of them, UNIMPLEMENTED if the store has no transaction
+	Atomic               bool     `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchCreateRequest) Reset()         { *m = BatchCreateRequest{} }
+func (m *BatchCreateRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateRequest) ProtoMessage()    {}
+func (*BatchCreateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{15}
+}
+
+func (m *BatchCreateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateRequest.Unmarshal(m, b)
+}
+func (m *BatchCreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateRequest.Merge(m, src)
+}
+func (m *BatchCreateRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateRequest.Size(m)
+}
+func (m *BatchCreateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateRequest proto.InternalMessageInfo
+
+func (m *BatchCreateRequest) GetRequests() []*CreateRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchCreateRequest) GetAtomic() bool {
+	if m != nil {
+		return m.Atomic
+	}
+	return false
+}
+
+// BatchCreateResponse the results in the order of the requests
+type BatchCreateResponse struct {
+	Results              []*BatchCreateResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *BatchCreateResponse) Reset()         { *m = BatchCreateResponse{} }
+func (m *BatchCreateResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateResponse) ProtoMessage()    {}
+func (*BatchCreateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{16}
+}
+
+func (m *BatchCreateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateResponse.Unmarshal(m, b)
+}
+func (m *BatchCreateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateResponse.Merge(m, src)
+}
+func (m *BatchCreateResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateResponse.Size(m)
+}
+func (m *BatchCreateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateResponse proto.InternalMessageInfo
+
+func (m *BatchCreateResponse) GetResults() []*BatchCreateResponse_Result {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// Result the ID of the created todo or the failure of its creation
+type BatchCreateResponse_Result struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Failure of the creation, ABORTED on the others when an item of an atomic batch fails
+	Status               *BatchStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BatchCreateResponse_Result) Reset()         { *m = BatchCreateResponse_Result{} }
+func (m *BatchCreateResponse_Result) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateResponse_Result) ProtoMessage()    {}
+func (*BatchCreateResponse_Result) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{16, 0}
+}
+
+func (m *BatchCreateResponse_Result) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateResponse_Result.Unmarshal(m, b)
+}
+func (m *BatchCreateResponse_Result) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateResponse_Result.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateResponse_Result) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateResponse_Result.Merge(m, src)
+}
+func (m *BatchCreateResponse_Result) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateResponse_Result.Size(m)
+}
+func (m *BatchCreateResponse_Result) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateResponse_Result.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateResponse_Result proto.InternalMessageInfo
+
+func (m *BatchCreateResponse_Result) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *BatchCreateResponse_Result) GetStatus() *BatchStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// BatchUpdateRequest the todos to update in one call
+type BatchUpdateRequest struct {
+	// Updates checked like Update, at most 1000. A todo updated twice gets the second update on top of the first.
+	Requests []*UpdateRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// Atomic updates all the todos or none of them, UNIMPLEMENTED if the store has no transaction
+	Atomic               bool     `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchUpdateRequest) Reset()         { *m = BatchUpdateRequest{} }
+func (m *BatchUpdateRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchUpdateRequest) ProtoMessage()    {}
+func (*BatchUpdateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{17}
+}
+
+func (m *BatchUpdateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpdateRequest.Unmarshal(m, b)
+}
+func (m *BatchUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpdateRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpdateRequest.Merge(m, src)
+}
+func (m *BatchUpdateRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchUpdateRequest.Size(m)
+}
+func (m *BatchUpdateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpdateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpdateRequest proto.InternalMessageInfo
+
+func (m *BatchUpdateRequest) GetRequests() []*UpdateRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchUpdateRequest) GetAtomic() bool {
+	if m != nil {
+		return m.Atomic
+	}
+	return false
+}
+
+// BatchUpdateResponse the results in the order of the requests
+type BatchUpdateResponse struct {
+	Results              []*BatchUpdateResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *BatchUpdateResponse) Reset()         { *m = BatchUpdateResponse{} }
+func (m *BatchUpdateResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchUpdateResponse) ProtoMessage()    {}
+func (*BatchUpdateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{18}
+}
+
+func (m *BatchUpdateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpdateResponse.Unmarshal(m, b)
+}
+func (m *BatchUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpdateResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpdateResponse.Merge(m, src)
+}
+func (m *BatchUpdateResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchUpdateResponse.Size(m)
+}
+func (m *BatchUpdateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpdateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpdateResponse proto.InternalMessageInfo
+
+func (m *BatchUpdateResponse) GetResults() []*BatchUpdateResponse_Result {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// Result the number of updated todos or the failure of the update
+type BatchUpdateResponse_Result struct {
+	Updated int64 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	// Failure of the update, ABORTED on the others when an item of an atomic batch fails
+	Status               *BatchStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BatchUpdateResponse_Result) Reset()         { *m = BatchUpdateResponse_Result{} }
+func (m *BatchUpdateResponse_Result) String() string { return proto.CompactTextString(m) }
+func (*BatchUpdateResponse_Result) ProtoMessage()    {}
+func (*BatchUpdateResponse_Result) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{18, 0}
+}
+
+func (m *BatchUpdateResponse_Result) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpdateResponse_Result.Unmarshal(m, b)
+}
+func (m *BatchUpdateResponse_Result) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpdateResponse_Result.Marshal(b, m, deterministic)
+}
+func (m *BatchUpdateResponse_Result) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpdateResponse_Result.Merge(m, src)
+}
+func (m *BatchUpdateResponse_Result) XXX_Size() int {
+	return xxx_messageInfo_BatchUpdateResponse_Result.Size(m)
+}
+func (m *BatchUpdateResponse_Result) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpdateResponse_Result.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpdateResponse_Result proto.InternalMessageInfo
+
+func (m *BatchUpdateResponse_Result) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+func (m *BatchUpdateResponse_Result) GetStatus() *BatchStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// BatchDeleteRequest the todos to move to the trash in one call
+type BatchDeleteRequest struct {
+	// Deletions checked like Delete, at most 1000
+	Requests []*DeleteRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// Atomic deletes all the todos or none of them, UNIMPLEMENTED if the store has no transaction
+	Atomic               bool     `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchDeleteRequest) Reset()         { *m = BatchDeleteRequest{} }
+func (m *BatchDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteRequest) ProtoMessage()    {}
+func (*BatchDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{19}
+}
+
+func (m *BatchDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteRequest.Unmarshal(m, b)
+}
+func (m *BatchDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteRequest.Merge(m, src)
+}
+func (m *BatchDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteRequest.Size(m)
+}
+func (m *BatchDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteRequest proto.InternalMessageInfo
+
+func (m *BatchDeleteRequest) GetRequests() []*DeleteRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchDeleteRequest) GetAtomic() bool {
+	if m != nil {
+		return m.Atomic
+	}
+	return false
+}
+
+// BatchDeleteResponse the results in the order of the requests
+type BatchDeleteResponse struct {
+	Results              []*BatchDeleteResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *BatchDeleteResponse) Reset()         { *m = BatchDeleteResponse{} }
+func (m *BatchDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteResponse) ProtoMessage()    {}
+func (*BatchDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{20}
+}
+
+func (m *BatchDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteResponse.Unmarshal(m, b)
+}
+func (m *BatchDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteResponse.Merge(m, src)
+}
+func (m *BatchDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteResponse.Size(m)
+}
+func (m *BatchDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteResponse proto.InternalMessageInfo
+
+func (m *BatchDeleteResponse) GetResults() []*BatchDeleteResponse_Result {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// Result the number of deleted todos or the failure of the deletion
+type BatchDeleteResponse_Result struct {
+	Deleted int64 `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// Failure of the deletion, ABORTED on the others when an item of an atomic batch fails
+	Status               *BatchStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BatchDeleteResponse_Result) Reset()         { *m = BatchDeleteResponse_Result{} }
+func (m *BatchDeleteResponse_Result) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteResponse_Result) ProtoMessage()    {}
+func (*BatchDeleteResponse_Result) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{20, 0}
+}
+
+func (m *BatchDeleteResponse_Result) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteResponse_Result.Unmarshal(m, b)
+}
+func (m *BatchDeleteResponse_Result) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteResponse_Result.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteResponse_Result) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteResponse_Result.Merge(m, src)
+}
+func (m *BatchDeleteResponse_Result) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteResponse_Result.Size(m)
+}
+func (m *BatchDeleteResponse_Result) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteResponse_Result.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteResponse_Result proto.InternalMessageInfo
+
+func (m *BatchDeleteResponse_Result) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+func (m *BatchDeleteResponse_Result) GetStatus() *BatchStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// SearchRequest the search request
+type SearchRequest struct {
+	// pattern in description to filter, see matchMode
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// tags to filter
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// states to filter if empty all the state
+	States []ToDo_State `protobuf:"varint,3,rep,packed,name=states,proto3,enum=v1.ToDo_State" json:"states,omitempty"`
+	// maximum number of todos in the response, the server chooses if 0
+	PageSize int32 `protobuf:"varint,4,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// token of the page to return, nextPageToken of the previous response.
+	// The pages are offsets in the order: a todo created, deleted or moved in the order between two pages shifts the next pages,
+	// so a todo can be skipped or returned twice, SearchStream returns all the todos without pages
+	PageToken string `protobuf:"bytes,5,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	// order of the todos: created (default), updated, completed, deleted, reminder, title or state, followed by " desc" for a descending order
+	OrderBy string `protobuf:"bytes,6,opt,name=orderBy,proto3" json:"orderBy,omitempty"`
+	// owner to filter, only an admin can search the todos of another owner out of a list
+	Owner string `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`
+	// list to filter, the members of the list search all its todos
+	ListId string `protobuf:"bytes,8,opt,name=listId,proto3" json:"listId,omitempty"`
+	// how the pattern matches, LITERAL by default
+	MatchMode SearchRequest_MatchMode `protobuf:"varint,9,opt,name=matchMode,proto3,enum=v1.SearchRequest_MatchMode" json:"matchMode,omitempty"`
+	// range of createdAt to filter
+	CreatedAt *TimeRange `protobuf:"bytes,10,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	// range of updatedAt to filter
+	UpdatedAt *TimeRange `protobuf:"bytes,11,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	// range of completedAt to filter, the todos which are not DONE are excluded
+	CompletedAt *TimeRange `protobuf:"bytes,12,opt,name=completedAt,proto3" json:"completedAt,omitempty"`
+	// searches the todos in the trash instead of the others
+	Trashed bool `protobuf:"varint,13,opt,name=trashed,proto3" json:"trashed,omitempty"`
+	// range of deletedAt to filter, only the todos in the trash have one
+	DeletedAt            *TimeRange `protobuf:"bytes,14,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
+func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchRequest) ProtoMessage()    {}
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{21}
+}
+
+func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchRequest.Unmarshal(m, b)
+}
+func (m *SearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchRequest.Merge(m, src)
+}
+func (m *SearchRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchRequest.Size(m)
+}
+func (m *SearchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchRequest proto.InternalMessageInfo
+
+func (m *SearchRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetStates() []ToDo_State {
+	if m != nil {
+		return m.States
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetMatchMode() SearchRequest_MatchMode {
+	if m != nil {
+		return m.MatchMode
+	}
+	return SearchRequest_LITERAL
+}
+
+func (m *SearchRequest) GetCreatedAt() *TimeRange {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetUpdatedAt() *TimeRange {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetCompletedAt() *TimeRange {
+	if m != nil {
+		return m.CompletedAt
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetTrashed() bool {
+	if m != nil {
+		return m.Trashed
+	}
+	return false
+}
+
+func (m *SearchRequest) GetDeletedAt() *TimeRange {
+	if m != nil {
+		return m.DeletedAt
+	}
+	return nil
+}
+
+// SearchResponse the todos
+type SearchResponse struct {
+	// List of Todos
+	ToDos []*ToDo `protobuf:"bytes,1,rep,name=toDos,proto3" json:"toDos,omitempty"`
+	// token of the next page, empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	// number of todos matching the request in all the pages
+	TotalSize            int64    `protobuf:"varint,3,opt,name=totalSize,proto3" json:"totalSize,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
+func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse) ProtoMessage()    {}
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{22}
+}
+
+func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResponse.Unmarshal(m, b)
+}
+func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse.Merge(m, src)
+}
+func (m *SearchResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchResponse.Size(m)
+}
+func (m *SearchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResponse proto.InternalMessageInfo
+
+func (m *SearchResponse) GetToDos() []*ToDo {
+	if m != nil {
+		return m.ToDos
+	}
+	return nil
+}
+
+func (m *SearchResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *SearchResponse) GetTotalSize() int64 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+// WatchRequest the filters of the todos to watch, the same as in SearchRequest
+type WatchRequest struct {
+	// pattern in description to filter, see matchMode
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// tags to filter
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// states to filter if empty all the state
+	States []ToDo_State `protobuf:"varint,3,rep,packed,name=states,proto3,enum=v1.ToDo_State" json:"states,omitempty"`
+	// owner to filter, only an admin can watch the todos of another owner out of a list
+	Owner string `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	// list to filter, the members of the list watch all its todos
+	ListId string `protobuf:"bytes,5,opt,name=listId,proto3" json:"listId,omitempty"`
+	// how the pattern matches, LITERAL by default
+	MatchMode            SearchRequest_MatchMode `protobuf:"varint,6,opt,name=matchMode,proto3,enum=v1.SearchRequest_MatchMode" json:"matchMode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{23}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetStates() []ToDo_State {
+	if m != nil {
+		return m.States
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetMatchMode() SearchRequest_MatchMode {
+	if m != nil {
+		return m.MatchMode
+	}
+	return SearchRequest_LITERAL
+}
+
+// Event a change on a todo
+type Event struct {
+	// Type of change
+	Type Event_Type `protobuf:"varint,1,opt,name=type,proto3,enum=v1.Event_Type" json:"type,omitempty"`
+	// The todo after the change, the deleted todo on DELETED and PURGED
+	ToDo *ToDo `protobuf:"bytes,2,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	// The todo before the change on UPDATED
+	Previous *ToDo `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"`
+	// Tenant of the todo, empty without tenants
+	Tenant               string   `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{24}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetType() Event_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Event_CREATED
+}
+
+func (m *Event) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
+	}
+	return nil
+}
+
+func (m *Event) GetPrevious() *ToDo {
+	if m != nil {
+		return m.Previous
+	}
+	return nil
+}
+
+func (m *Event) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+// HistoryRecord a change on a todo kept in its history, the records are never changed
+type HistoryRecord struct {
+	// toDoId the ID of the todo
+	ToDoId string `protobuf:"bytes,1,opt,name=toDoId,proto3" json:"toDoId,omitempty"`
+	// type of change
+	Type Event_Type `protobuf:"varint,2,opt,name=type,proto3,enum=v1.Event_Type" json:"type,omitempty"`
+	// actor the subject of the caller, empty without authentication
+	Actor string `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	// time of the change
+	Time *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	// requestId the ID of the request, the x-request-id header or else generated by the server
+	RequestId string `protobuf:"bytes,5,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	// version of the todo after the change, before it on PURGED
+	Version int64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	// changes the fields which changed
+	Changes []*HistoryRecord_Change `protobuf:"bytes,7,rep,name=changes,proto3" json:"changes,omitempty"`
+	// owner of the todo, the owner and the list give the access to the history
+	Owner string `protobuf:"bytes,8,opt,name=owner,proto3" json:"owner,omitempty"`
+	// listId the list of the todo
+	ListId               string   `protobuf:"bytes,9,opt,name=listId,proto3" json:"listId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryRecord) Reset()         { *m = HistoryRecord{} }
+func (m *HistoryRecord) String() string { return proto.CompactTextString(m) }
+func (*HistoryRecord) ProtoMessage()    {}
+func (*HistoryRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{25}
+}
+
+func (m *HistoryRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryRecord.Unmarshal(m, b)
+}
+func (m *HistoryRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryRecord.Marshal(b, m, deterministic)
+}
+func (m *HistoryRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryRecord.Merge(m, src)
+}
+func (m *HistoryRecord) XXX_Size() int {
+	return xxx_messageInfo_HistoryRecord.Size(m)
+}
+func (m *HistoryRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryRecord proto.InternalMessageInfo
+
+func (m *HistoryRecord) GetToDoId() string {
+	if m != nil {
+		return m.ToDoId
+	}
+	return ""
+}
+
+func (m *HistoryRecord) GetType() Event_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Event_CREATED
+}
+
+func (m *HistoryRecord) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *HistoryRecord) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *HistoryRecord) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *HistoryRecord) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *HistoryRecord) GetChanges() []*HistoryRecord_Change {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+func (m *HistoryRecord) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *HistoryRecord) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+// Change the values of a field before and after the change, empty if unset,
+// the tags are separated by commas and the reminder is in RFC 3339
+type HistoryRecord_Change struct {
+	// field the name of the field, ex: title
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// oldValue the value before the change, empty on CREATED
+	OldValue string `protobuf:"bytes,2,opt,name=oldValue,proto3" json:"oldValue,omitempty"`
+	// newValue the value after the change, empty on PURGED
+	NewValue             string   `protobuf:"bytes,3,opt,name=newValue,proto3" json:"newValue,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryRecord_Change) Reset()         { *m = HistoryRecord_Change{} }
+func (m *HistoryRecord_Change) String() string { return proto.CompactTextString(m) }
+func (*HistoryRecord_Change) ProtoMessage()    {}
+func (*HistoryRecord_Change) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{25, 0}
+}
+
+func (m *HistoryRecord_Change) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryRecord_Change.Unmarshal(m, b)
+}
+func (m *HistoryRecord_Change) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryRecord_Change.Marshal(b, m, deterministic)
+}
+func (m *HistoryRecord_Change) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryRecord_Change.Merge(m, src)
+}
+func (m *HistoryRecord_Change) XXX_Size() int {
+	return xxx_messageInfo_HistoryRecord_Change.Size(m)
+}
+func (m *HistoryRecord_Change) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryRecord_Change.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryRecord_Change proto.InternalMessageInfo
+
+func (m *HistoryRecord_Change) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *HistoryRecord_Change) GetOldValue() string {
+	if m != nil {
+		return m.OldValue
+	}
+	return ""
+}
+
+func (m *HistoryRecord_Change) GetNewValue() string {
+	if m != nil {
+		return m.NewValue
+	}
+	return ""
+}
+
+type GetHistoryRequest struct {
+	// Unique ID of the todo, deleted or not
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetHistoryRequest) Reset()         { *m = GetHistoryRequest{} }
+func (m *GetHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetHistoryRequest) ProtoMessage()    {}
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{26}
+}
+
+func (m *GetHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetHistoryRequest.Merge(m, src)
+}
+func (m *GetHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetHistoryRequest.Size(m)
+}
+func (m *GetHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetHistoryRequest proto.InternalMessageInfo
+
+func (m *GetHistoryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetHistoryResponse struct {
+	// records the changes in the order they happened
+	Records              []*HistoryRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetHistoryResponse) Reset()         { *m = GetHistoryResponse{} }
+func (m *GetHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*GetHistoryResponse) ProtoMessage()    {}
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{27}
+}
+
+func (m *GetHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetHistoryResponse.Unmarshal(m, b)
+}
+func (m *GetHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *GetHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetHistoryResponse.Merge(m, src)
+}
+func (m *GetHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_GetHistoryResponse.Size(m)
+}
+func (m *GetHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetHistoryResponse proto.InternalMessageInfo
+
+func (m *GetHistoryResponse) GetRecords() []*HistoryRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+// List a named list of todos shared with other users
+type List struct {
+	// Unique ID
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Name
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Owner the subject of the caller who created the list, set by the server
+	Owner string `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	// Members the users who share the list, set by ShareList and UnshareList
+	Members              []*List_Member `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *List) Reset()         { *m = List{} }
+func (m *List) String() string { return proto.CompactTextString(m) }
+func (*List) ProtoMessage()    {}
+func (*List) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{28}
+}
+
+func (m *List) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_List.Unmarshal(m, b)
+}
+func (m *List) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_List.Marshal(b, m, deterministic)
+}
+func (m *List) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_List.Merge(m, src)
+}
+func (m *List) XXX_Size() int {
+	return xxx_messageInfo_List.Size(m)
+}
+func (m *List) XXX_DiscardUnknown() {
+	xxx_messageInfo_List.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_List proto.InternalMessageInfo
+
+func (m *List) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *List) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *List) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *List) GetMembers() []*List_Member {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+// Member a user who shares the list
+type List_Member struct {
+	// subject of the user
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// role of the user
+	Role                 List_Role `protobuf:"varint,2,opt,name=role,proto3,enum=v1.List_Role" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *List_Member) Reset()         { *m = List_Member{} }
+func (m *List_Member) String() string { return proto.CompactTextString(m) }
+func (*List_Member) ProtoMessage()    {}
+func (*List_Member) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{28, 0}
+}
+
+func (m *List_Member) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_List_Member.Unmarshal(m, b)
+}
+func (m *List_Member) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_List_Member.Marshal(b, m, deterministic)
+}
+func (m *List_Member) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_List_Member.Merge(m, src)
+}
+func (m *List_Member) XXX_Size() int {
+	return xxx_messageInfo_List_Member.Size(m)
+}
+func (m *List_Member) XXX_DiscardUnknown() {
+	xxx_messageInfo_List_Member.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_List_Member proto.InternalMessageInfo
+
+func (m *List_Member) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *List_Member) GetRole() List_Role {
+	if m != nil {
+		return m.Role
+	}
+	return List_VIEWER
+}
+
+// CreateListRequest a request of creation
+type CreateListRequest struct {
+	// The list to add
+	List                 *List    `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateListRequest) Reset()         { *m = CreateListRequest{} }
+func (m *CreateListRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateListRequest) ProtoMessage()    {}
+func (*CreateListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{29}
+}
+
+func (m *CreateListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateListRequest.Unmarshal(m, b)
+}
+func (m *CreateListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateListRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateListRequest.Merge(m, src)
+}
+func (m *CreateListRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateListRequest.Size(m)
+}
+func (m *CreateListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateListRequest proto.InternalMessageInfo
+
+func (m *CreateListRequest) GetList() *List {
+	if m != nil {
+		return m.List
+	}
+	return nil
+}
+
+// CreateListResponse the ID
+type CreateListResponse struct {
+	// ID of created list
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateListResponse) Reset()         { *m = CreateListResponse{} }
+func (m *CreateListResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateListResponse) ProtoMessage()    {}
+func (*CreateListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{30}
+}
+
+func (m *CreateListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateListResponse.Unmarshal(m, b)
+}
+func (m *CreateListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateListResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateListResponse.Merge(m, src)
+}
+func (m *CreateListResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateListResponse.Size(m)
+}
+func (m *CreateListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateListResponse proto.InternalMessageInfo
+
+func (m *CreateListResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// ReadListRequest the ID of the list to read
+type ReadListRequest struct {
+	// ID of the list
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadListRequest) Reset()         { *m = ReadListRequest{} }
+func (m *ReadListRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadListRequest) ProtoMessage()    {}
+func (*ReadListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{31}
+}
+
+func (m *ReadListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadListRequest.Unmarshal(m, b)
+}
+func (m *ReadListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadListRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadListRequest.Merge(m, src)
+}
+func (m *ReadListRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadListRequest.Size(m)
+}
+func (m *ReadListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadListRequest proto.InternalMessageInfo
+
+func (m *ReadListRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// ReadListResponse the list
+type ReadListResponse struct {
+	// List read by ID
+	List                 *List    `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadListResponse) Reset()         { *m = ReadListResponse{} }
+func (m *ReadListResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadListResponse) ProtoMessage()    {}
+func (*ReadListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{32}
+}
+
+func (m *ReadListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadListResponse.Unmarshal(m, b)
+}
+func (m *ReadListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadListResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadListResponse.Merge(m, src)
+}
+func (m *ReadListResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadListResponse.Size(m)
+}
+func (m *ReadListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadListResponse proto.InternalMessageInfo
+
+func (m *ReadListResponse) GetList() *List {
+	if m != nil {
+		return m.List
+	}
+	return nil
+}
+
+// UpdateListRequest the list to rename
+type UpdateListRequest struct {
+	// List with the ID and the new name
+	List                 *List    `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateListRequest) Reset()         { *m = UpdateListRequest{} }
+func (m *UpdateListRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateListRequest) ProtoMessage()    {}
+func (*UpdateListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{33}
+}
+
+func (m *UpdateListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateListRequest.Unmarshal(m, b)
+}
+func (m *UpdateListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateListRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateListRequest.Merge(m, src)
+}
+func (m *UpdateListRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateListRequest.Size(m)
+}
+func (m *UpdateListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateListRequest proto.InternalMessageInfo
+
+func (m *UpdateListRequest) GetList() *List {
+	if m != nil {
+		return m.List
+	}
+	return nil
+}
+
+// UpdateListResponse the number of updated lists
+type UpdateListResponse struct {
+	Updated              int64    `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateListResponse) Reset()         { *m = UpdateListResponse{} }
+func (m *UpdateListResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateListResponse) ProtoMessage()    {}
+func (*UpdateListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{34}
+}
+
+func (m *UpdateListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateListResponse.Unmarshal(m, b)
+}
+func (m *UpdateListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateListResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateListResponse.Merge(m, src)
+}
+func (m *UpdateListResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateListResponse.Size(m)
+}
+func (m *UpdateListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateListResponse proto.InternalMessageInfo
+
+func (m *UpdateListResponse) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+// DeleteListRequest the list to delete
+type DeleteListRequest struct {
+	// ID of the list
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteListRequest) Reset()         { *m = DeleteListRequest{} }
+func (m *DeleteListRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteListRequest) ProtoMessage()    {}
+func (*DeleteListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{35}
+}
+
+func (m *DeleteListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteListRequest.Unmarshal(m, b)
+}
+func (m *DeleteListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteListRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteListRequest.Merge(m, src)
+}
+func (m *DeleteListRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteListRequest.Size(m)
+}
+func (m *DeleteListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteListRequest proto.InternalMessageInfo
+
+func (m *DeleteListRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// DeleteListResponse the number of deleted lists
+type DeleteListResponse struct {
+	Deleted              int64    `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteListResponse) Reset()         { *m = DeleteListResponse{} }
+func (m *DeleteListResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteListResponse) ProtoMessage()    {}
+func (*DeleteListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{36}
+}
+
+func (m *DeleteListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteListResponse.Unmarshal(m, b)
+}
+func (m *DeleteListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteListResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteListResponse.Merge(m, src)
+}
+func (m *DeleteListResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteListResponse.Size(m)
+}
+func (m *DeleteListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteListResponse proto.InternalMessageInfo
+
+func (m *DeleteListResponse) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+// SearchListsRequest the search of the lists of the caller
+type SearchListsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchListsRequest) Reset()         { *m = SearchListsRequest{} }
+func (m *SearchListsRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchListsRequest) ProtoMessage()    {}
+func (*SearchListsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{37}
+}
+
+func (m *SearchListsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchListsRequest.Unmarshal(m, b)
+}
+func (m *SearchListsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchListsRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchListsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchListsRequest.Merge(m, src)
+}
+func (m *SearchListsRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchListsRequest.Size(m)
+}
+func (m *SearchListsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchListsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchListsRequest proto.InternalMessageInfo
+
+// SearchListsResponse the lists
+type SearchListsResponse struct {
+	// Lists owned or shared with the caller, all the lists for an admin
+	Lists                []*List  `protobuf:"bytes,1,rep,name=lists,proto3" json:"lists,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchListsResponse) Reset()         { *m = SearchListsResponse{} }
+func (m *SearchListsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchListsResponse) ProtoMessage()    {}
+func (*SearchListsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{38}
+}
+
+func (m *SearchListsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchListsResponse.Unmarshal(m, b)
+}
+func (m *SearchListsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchListsResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchListsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchListsResponse.Merge(m, src)
+}
+func (m *SearchListsResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchListsResponse.Size(m)
+}
+func (m *SearchListsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchListsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchListsResponse proto.InternalMessageInfo
+
+func (m *SearchListsResponse) GetLists() []*List {
+	if m != nil {
+		return m.Lists
+	}
+	return nil
+}
+
+// ShareListRequest gives a role on the list to a user
+type ShareListRequest struct {
+	// ID of the list
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// subject of the user
+	Subject string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// role of the user, replaces the previous one
+	Role                 List_Role `protobuf:"varint,3,opt,name=role,proto3,enum=v1.List_Role" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ShareListRequest) Reset()         { *m = ShareListRequest{} }
+func (m *ShareListRequest) String() string { return proto.CompactTextString(m) }
+func (*ShareListRequest) ProtoMessage()    {}
+func (*ShareListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{39}
+}
+
+func (m *ShareListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShareListRequest.Unmarshal(m, b)
+}
+func (m *ShareListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShareListRequest.Marshal(b, m, deterministic)
+}
+func (m *ShareListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareListRequest.Merge(m, src)
+}
+func (m *ShareListRequest) XXX_Size() int {
+	return xxx_messageInfo_ShareListRequest.Size(m)
+}
+func (m *ShareListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShareListRequest proto.InternalMessageInfo
+
+func (m *ShareListRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ShareListRequest) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *ShareListRequest) GetRole() List_Role {
+	if m != nil {
+		return m.Role
+	}
+	return List_VIEWER
+}
+
+// ShareListResponse the shared list
+type ShareListResponse struct {
+	List                 *List    `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShareListResponse) Reset()         { *m = ShareListResponse{} }
+func (m *ShareListResponse) String() string { return proto.CompactTextString(m) }
+func (*ShareListResponse) ProtoMessage()    {}
+func (*ShareListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{40}
+}
+
+func (m *ShareListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShareListResponse.Unmarshal(m, b)
+}
+func (m *ShareListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShareListResponse.Marshal(b, m, deterministic)
+}
+func (m *ShareListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareListResponse.Merge(m, src)
+}
+func (m *ShareListResponse) XXX_Size() int {
+	return xxx_messageInfo_ShareListResponse.Size(m)
+}
+func (m *ShareListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShareListResponse proto.InternalMessageInfo
+
+func (m *ShareListResponse) GetList() *List {
+	if m != nil {
+		return m.List
+	}
+	return nil
+}
+
+// UnshareListRequest removes a user from the members of the list
+type UnshareListRequest struct {
+	// ID of the list
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// subject of the user
+	Subject              string   `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnshareListRequest) Reset()         { *m = UnshareListRequest{} }
+func (m *UnshareListRequest) String() string { return proto.CompactTextString(m) }
+func (*UnshareListRequest) ProtoMessage()    {}
+func (*UnshareListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{41}
+}
+
+func (m *UnshareListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnshareListRequest.Unmarshal(m, b)
+}
+func (m *UnshareListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnshareListRequest.Marshal(b, m, deterministic)
+}
+func (m *UnshareListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnshareListRequest.Merge(m, src)
+}
+func (m *UnshareListRequest) XXX_Size() int {
+	return xxx_messageInfo_UnshareListRequest.Size(m)
+}
+func (m *UnshareListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnshareListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnshareListRequest proto.InternalMessageInfo
+
+func (m *UnshareListRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *UnshareListRequest) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+// UnshareListResponse the list
+type UnshareListResponse struct {
+	List                 *List    `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnshareListResponse) Reset()         { *m = UnshareListResponse{} }
+func (m *UnshareListResponse) String() string { return proto.CompactTextString(m) }
+func (*UnshareListResponse) ProtoMessage()    {}
+func (*UnshareListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7238c4084676f823, []int{42}
+}
+
+func (m *UnshareListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnshareListResponse.Unmarshal(m, b)
+}
+func (m *UnshareListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnshareListResponse.Marshal(b, m, deterministic)
+}
+func (m *UnshareListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnshareListResponse.Merge(m, src)
+}
+func (m *UnshareListResponse) XXX_Size() int {
+	return xxx_messageInfo_UnshareListResponse.Size(m)
+}
+func (m *UnshareListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnshareListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnshareListResponse proto.InternalMessageInfo
+
+func (m *UnshareListResponse) GetList() *List {
+	if m != nil {
+		return m.List
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("v1.ToDo_State", ToDo_State_name, ToDo_State_value)
+	proto.RegisterEnum("v1.SearchRequest_MatchMode", SearchRequest_MatchMode_name, SearchRequest_MatchMode_value)
+	proto.RegisterEnum("v1.Event_Type", Event_Type_name, Event_Type_value)
+	proto.RegisterEnum("v1.List_Role", List_Role_name, List_Role_value)
+	proto.RegisterType((*ToDo)(nil), "v1.ToDo")
+	proto.RegisterType((*TimeRange)(nil), "v1.TimeRange")
+	proto.RegisterType((*CreateRequest)(nil), "v1.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "v1.CreateResponse")
+	proto.RegisterType((*ReadRequest)(nil), "v1.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "v1.ReadResponse")
+	proto.RegisterType((*UpdateRequest)(nil), "v1.UpdateRequest")
+	proto.RegisterType((*UpdateResponse)(nil), "v1.UpdateResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "v1.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "v1.DeleteResponse")
+	proto.RegisterType((*RestoreRequest)(nil), "v1.RestoreRequest")
+	proto.RegisterType((*RestoreResponse)(nil), "v1.RestoreResponse")
+	proto.RegisterType((*PurgeRequest)(nil), "v1.PurgeRequest")
+	proto.RegisterType((*PurgeResponse)(nil), "v1.PurgeResponse")
+	proto.RegisterType((*BatchStatus)(nil), "v1.BatchStatus")
+	proto.RegisterType((*BatchCreateRequest)(nil), "v1.BatchCreateRequest")
+	proto.RegisterType((*BatchCreateResponse)(nil), "v1.BatchCreateResponse")
+	proto.RegisterType((*BatchCreateResponse_Result)(nil), "v1.BatchCreateResponse.Result")
+	proto.RegisterType((*BatchUpdateRequest)(nil), "v1.BatchUpdateRequest")
+	proto.RegisterType((*BatchUpdateResponse)(nil), "v1.BatchUpdateResponse")
+	proto.RegisterType((*BatchUpdateResponse_Result)(nil), "v1.BatchUpdateResponse.Result")
+	proto.RegisterType((*BatchDeleteRequest)(nil), "v1.BatchDeleteRequest")
+	proto.RegisterType((*BatchDeleteResponse)(nil), "v1.BatchDeleteResponse")
+	proto.RegisterType((*BatchDeleteResponse_Result)(nil), "v1.BatchDeleteResponse.Result")
+	proto.RegisterType((*SearchRequest)(nil), "v1.SearchRequest")
+	proto.RegisterType((*SearchResponse)(nil), "v1.SearchResponse")
+	proto.RegisterType((*WatchRequest)(nil), "v1.WatchRequest")
+	proto.RegisterType((*Event)(nil), "v1.Event")
+	proto.RegisterType((*HistoryRecord)(nil), "v1.HistoryRecord")
+	proto.RegisterType((*HistoryRecord_Change)(nil), "v1.HistoryRecord.Change")
+	proto.RegisterType((*GetHistoryRequest)(nil), "v1.GetHistoryRequest")
+	proto.RegisterType((*GetHistoryResponse)(nil), "v1.GetHistoryResponse")
+	proto.RegisterType((*List)(nil), "v1.List")
+	proto.RegisterType((*List_Member)(nil), "v1.List.Member")
+	proto.RegisterType((*CreateListRequest)(nil), "v1.CreateListRequest")
+	proto.RegisterType((*CreateListResponse)(nil), "v1.CreateListResponse")
+	proto.RegisterType((*ReadListRequest)(nil), "v1.ReadListRequest")
+	proto.RegisterType((*ReadListResponse)(nil), "v1.ReadListResponse")
+	proto.RegisterType((*UpdateListRequest)(nil), "v1.UpdateListRequest")
+	proto.RegisterType((*UpdateListResponse)(nil), "v1.UpdateListResponse")
+	proto.RegisterType((*DeleteListRequest)(nil), "v1.DeleteListRequest")
+	proto.RegisterType((*DeleteListResponse)(nil), "v1.DeleteListResponse")
+	proto.RegisterType((*SearchListsRequest)(nil), "v1.SearchListsRequest")
+	proto.RegisterType((*SearchListsResponse)(nil), "v1.SearchListsResponse")
+	proto.RegisterType((*ShareListRequest)(nil), "v1.ShareListRequest")
+	proto.RegisterType((*ShareListResponse)(nil), "v1.ShareListResponse")
+	proto.RegisterType((*UnshareListRequest)(nil), "v1.UnshareListRequest")
+	proto.RegisterType((*UnshareListResponse)(nil), "v1.UnshareListResponse")
+}
+
+func init() { proto.RegisterFile("todo-grpc/todo.proto", fileDescriptor_7238c4084676f823) }
+
+var fileDescriptor_7238c4084676f823 = []byte{
+	// 2215 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0xcb, 0x6f, 0x1b, 0xc7,
+	0x19, 0xcf, 0xf2, 0xcd, 0x8f, 0xa2, 0x4c, 0x8d, 0x14, 0x7a, 0xb3, 0x71, 0x9d, 0xf5, 0xc6, 0x68,
+	0x14, 0xdb, 0x22, 0x2d, 0x19, 0x2d, 0x52, 0xa1, 0x0f, 0xd0, 0xe6, 0x5a, 0x51, 0x2b, 0x59, 0xea,
+	0x90, 0xb2, 0xdd, 0x06, 0xa8, 0xbb, 0xe2, 0x8e, 0xa9, 0x8d, 0xc9, 0x5d, 0x66, 0x77, 0x28, 0x47,
+	0x2d, 0x72, 0x69, 0xef, 0x3d, 0x24, 0xa7, 0x02, 0x3d, 0xf4, 0xda, 0x73, 0xff, 0x8e, 0x02, 0x05,
+	0x7a, 0xee, 0xad, 0x7f, 0x48, 0x31, 0x8f, 0x7d, 0x93, 0x92, 0x5c, 0xa0, 0x27, 0xed, 0xf7, 0xcd,
+	0x6f, 0xbe, 0xc7, 0xcc, 0x37, 0xdf, 0x43, 0x84, 0x0d, 0xea, 0xd9, 0xde, 0xd6, 0xd8, 0x9f, 0x8d,
+	0xba, 0xec, 0xab, 0x33, 0xf3, 0x3d, 0xea, 0xa1, 0xc2, 0xf9, 0xb6, 0xf6, 0xd1, 0xd8, 0xf3, 0xc6,
+	0x13, 0xd2, 0xe5, 0x9c, 0xd3, 0xf9, 0xeb, 0x2e, 0x75, 0xa6, 0x24, 0xa0, 0xd6, 0x74, 0x26, 0x40,
+	0x9a, 0x9e, 0x05, 0xbc, 0x76, 0xc8, 0xc4, 0x7e, 0x35, 0xb5, 0x82, 0x37, 0x12, 0xf1, 0x41, 0x16,
+	0x61, 0xb9, 0x17, 0x72, 0xe9, 0x96, 0x5c, 0xb2, 0x66, 0x4e, 0xd7, 0x72, 0x5d, 0x8f, 0x5a, 0xd4,
+	0xf1, 0xdc, 0x40, 0xae, 0x3e, 0xe0, 0x7f, 0x46, 0x5b, 0x63, 0xe2, 0x6e, 0x05, 0x6f, 0xad, 0xf1,
+	0x98, 0xf8, 0x5d, 0x6f, 0xc6, 0x11, 0x79, 0xb4, 0xf1, 0xb7, 0x12, 0x94, 0x86, 0x5e, 0xdf, 0x43,
+	0xab, 0x50, 0x70, 0x6c, 0x55, 0xd1, 0x95, 0xcd, 0x3a, 0x2e, 0x38, 0x36, 0xda, 0x80, 0x32, 0x75,
+	0xe8, 0x84, 0xa8, 0x05, 0xce, 0x12, 0x04, 0xd2, 0xa1, 0x61, 0x93, 0x60, 0xe4, 0x3b, 0x5c, 0xa0,
+	0x5a, 0xe4, 0x6b, 0x49, 0x16, 0x42, 0x50, 0xa2, 0xd6, 0x38, 0x50, 0x4b, 0x7a, 0x71, 0xb3, 0x8e,
+	0xf9, 0x37, 0xfa, 0x21, 0xd4, 0x7c, 0x32, 0x75, 0x5c, 0x9b, 0xf8, 0x6a, 0x59, 0x57, 0x36, 0x1b,
+	0x3b, 0x5a, 0x47, 0xf8, 0xd0, 0x09, 0xdd, 0xeb, 0x0c, 0xc3, 0x13, 0xc2, 0x11, 0x16, 0xdd, 0x85,
+	0x72, 0x40, 0x2d, 0x4a, 0xd4, 0x8a, 0xae, 0x6c, 0xae, 0xee, 0xac, 0x76, 0xce, 0xb7, 0x3b, 0xcc,
+	0xd8, 0xce, 0x80, 0x71, 0xb1, 0x58, 0x64, 0x96, 0x7a, 0x6f, 0x5d, 0xe2, 0xab, 0x55, 0x61, 0x29,
+	0x27, 0x50, 0x1b, 0x2a, 0x13, 0x27, 0xa0, 0xfb, 0xb6, 0x5a, 0xe3, 0x6c, 0x49, 0x21, 0x15, 0xaa,
+	0xe7, 0xc4, 0x0f, 0x98, 0xf5, 0x75, 0x5d, 0xd9, 0x2c, 0xe2, 0x90, 0x44, 0x9f, 0x41, 0x7d, 0xe4,
+	0x13, 0x8b, 0x12, 0xbb, 0x47, 0x55, 0xb8, 0xd2, 0xcc, 0x18, 0xcc, 0x76, 0xce, 0x67, 0xb6, 0xdc,
+	0xd9, 0xb8, 0x7a, 0x67, 0x04, 0x46, 0x3f, 0x86, 0xc6, 0xc8, 0x9b, 0xce, 0x26, 0x44, 0xec, 0x5d,
+	0xb9, 0x72, 0x6f, 0x12, 0xce, 0xf4, 0xda, 0x24, 0xdc, 0xdb, 0xbc, 0x5a, 0x6f, 0x04, 0x36, 0x1e,
+	0x41, 0x99, 0x9f, 0x21, 0xba, 0x01, 0x8d, 0x67, 0x47, 0xc3, 0x57, 0x83, 0x61, 0x0f, 0x0f, 0xcd,
+	0x7e, 0xeb, 0x3d, 0xc6, 0xd8, 0x7f, 0xf6, 0xea, 0x18, 0x1f, 0xed, 0x61, 0x73, 0x30, 0x68, 0x29,
+	0xa8, 0x06, 0xa5, 0xfe, 0xd1, 0x33, 0xb3, 0x55, 0x30, 0xbe, 0x82, 0x3a, 0x13, 0x86, 0x2d, 0x77,
+	0x4c, 0xd0, 0x43, 0x28, 0x5b, 0xaf, 0x29, 0xf1, 0x55, 0xe5, 0x4a, 0xbd, 0x02, 0x88, 0x76, 0xa0,
+	0x72, 0x4a, 0x5e, 0x7b, 0xbe, 0x08, 0xa9, 0xcb, 0xb7, 0x48, 0xa4, 0xb1, 0x05, 0xcd, 0x27, 0xfc,
+	0x98, 0x31, 0xf9, 0x6a, 0x4e, 0x02, 0x8a, 0x6e, 0x41, 0x89, 0x7a, 0x7d, 0x4f, 0x6a, 0xad, 0x85,
+	0x11, 0x81, 0x39, 0xd7, 0xd0, 0x61, 0x35, 0x84, 0x07, 0x33, 0xcf, 0x0d, 0x48, 0x36, 0xac, 0x8d,
+	0xef, 0x41, 0x03, 0x13, 0xcb, 0x0e, 0xc5, 0x65, 0x97, 0x1f, 0xc0, 0x8a, 0x58, 0x96, 0xdb, 0x2f,
+	0x57, 0xf7, 0x9d, 0x02, 0xcd, 0x13, 0x7e, 0x97, 0xd7, 0x32, 0x0f, 0xed, 0x02, 0x88, 0xab, 0x3f,
+	0xb4, 0x82, 0x37, 0x4b, 0x4f, 0xe1, 0x29, 0x4b, 0x05, 0x0c, 0x81, 0x13, 0x68, 0xb4, 0x09, 0x37,
+	0xc8, 0xd7, 0x33, 0x32, 0xa2, 0xc4, 0x7e, 0x2e, 0xe3, 0xb7, 0xc8, 0xe3, 0x37, 0xcb, 0x36, 0xee,
+	0xc1, 0x6a, 0x68, 0x94, 0xf4, 0x42, 0x85, 0xaa, 0x0c, 0x39, 0x6e, 0x58, 0x11, 0x87, 0xa4, 0xb1,
+	0x0f, 0xcd, 0x3e, 0x0f, 0x8a, 0x25, 0x07, 0xb2, 0x48, 0x6d, 0x61, 0xa9, 0xda, 0x50, 0x54, 0xac,
+	0x56, 0x46, 0x5c, 0xa8, 0x56, 0x92, 0xec, 0x9e, 0x30, 0x09, 0xa8, 0xe7, 0x2f, 0xd3, 0x6b, 0x6c,
+	0xc1, 0x8d, 0x08, 0x21, 0xc5, 0x69, 0x2c, 0x8b, 0x70, 0x56, 0x28, 0x2f, 0xa2, 0x8d, 0xdb, 0xb0,
+	0x72, 0x3c, 0xf7, 0xc7, 0x4b, 0xc5, 0x7d, 0x02, 0x4d, 0xb9, 0x2e, 0x85, 0xb5, 0xa1, 0x32, 0x63,
+	0x8c, 0x50, 0x94, 0xa4, 0x8c, 0x37, 0xd0, 0x78, 0x6c, 0xd1, 0xd1, 0x19, 0x7b, 0x1d, 0xf3, 0x80,
+	0x65, 0xb3, 0x91, 0x67, 0x13, 0x0e, 0x2a, 0x63, 0xfe, 0xcd, 0xdc, 0x9a, 0x92, 0x20, 0xb0, 0xc6,
+	0x61, 0x6e, 0x0c, 0x49, 0xd4, 0x61, 0x0e, 0x53, 0xcb, 0x99, 0x04, 0x6a, 0x51, 0x2f, 0x6e, 0x36,
+	0x76, 0x36, 0x72, 0x97, 0xdb, 0x73, 0x2f, 0x70, 0x08, 0x32, 0xbe, 0x00, 0xc4, 0x95, 0xa5, 0x43,
+	0x7c, 0x8b, 0xf9, 0xc9, 0x3f, 0x03, 0x55, 0xe1, 0x62, 0xd6, 0x58, 0x1c, 0xa5, 0x40, 0x38, 0x82,
+	0x30, 0x4f, 0x2c, 0xea, 0x4d, 0x9d, 0x11, 0xb7, 0xa6, 0x86, 0x25, 0x65, 0x7c, 0xab, 0xc0, 0x7a,
+	0x4a, 0xba, 0xf4, 0xfc, 0x33, 0xa8, 0xfa, 0x24, 0x98, 0x4f, 0x22, 0xe9, 0xb7, 0x99, 0xf4, 0x05,
+	0xc8, 0x0e, 0xe6, 0x30, 0x1c, 0xc2, 0xb5, 0x1e, 0x54, 0x04, 0x2b, 0x17, 0x25, 0x9f, 0x40, 0x25,
+	0xe0, 0x07, 0x26, 0x83, 0xfa, 0x46, 0x24, 0x52, 0x9c, 0x23, 0x96, 0xcb, 0x91, 0xc7, 0xe9, 0x57,
+	0xb3, 0xc4, 0xe3, 0x14, 0xe8, 0x1a, 0x1e, 0xff, 0x25, 0xf4, 0x38, 0x13, 0xfe, 0x97, 0x78, 0x9c,
+	0x46, 0xe6, 0x3c, 0xfe, 0x45, 0xe4, 0xf1, 0xd2, 0x27, 0xf4, 0xee, 0xbe, 0xa7, 0x1f, 0xdc, 0x12,
+	0xdf, 0x53, 0xa0, 0x77, 0xf1, 0x3d, 0xf3, 0x06, 0x2f, 0xf1, 0x3d, 0x8d, 0xbc, 0xdc, 0xf7, 0xc5,
+	0xef, 0xf8, 0xfa, 0xbe, 0xff, 0xbd, 0x04, 0xcd, 0x01, 0xb1, 0xfc, 0xd1, 0x59, 0xe8, 0xb7, 0x0a,
+	0xd5, 0x99, 0x45, 0x29, 0xf1, 0x5d, 0x19, 0x47, 0x21, 0x19, 0x75, 0x10, 0x85, 0x44, 0x07, 0xf1,
+	0x7d, 0xa1, 0x88, 0x88, 0x87, 0x95, 0x6f, 0x05, 0xe4, 0x2a, 0xcb, 0x11, 0x33, 0x6b, 0x4c, 0x06,
+	0xce, 0xef, 0x88, 0x5a, 0xe2, 0x6f, 0x36, 0xa2, 0xd1, 0x2d, 0xa8, 0xb3, 0xef, 0xa1, 0xf7, 0x86,
+	0xb8, 0xbc, 0x0d, 0xa9, 0xe3, 0x98, 0xc1, 0xec, 0xf1, 0x7c, 0x9b, 0xf8, 0x8f, 0x2f, 0x78, 0xb7,
+	0x51, 0xc7, 0x21, 0xf9, 0x8e, 0xfd, 0xc5, 0x8f, 0xa0, 0x3e, 0x65, 0x07, 0x70, 0xc8, 0xd2, 0x46,
+	0x9d, 0xf7, 0x2d, 0x1f, 0x32, 0x63, 0x53, 0xde, 0x77, 0x0e, 0x43, 0x08, 0x8e, 0xd1, 0xe8, 0x7e,
+	0xbe, 0x01, 0x69, 0x72, 0x3f, 0xc3, 0xa2, 0x9b, 0xec, 0x39, 0xee, 0xe7, 0x7b, 0x8e, 0x2c, 0x38,
+	0x5a, 0x47, 0xdd, 0x45, 0x6d, 0x46, 0x06, 0x9e, 0x44, 0xb0, 0xd3, 0xa0, 0xbe, 0x15, 0x9c, 0x11,
+	0x9b, 0xf7, 0x15, 0x35, 0x1c, 0x92, 0x4c, 0x6f, 0xdc, 0x73, 0xac, 0x2e, 0xd4, 0x1b, 0xb7, 0x19,
+	0x3f, 0x81, 0x7a, 0xe4, 0x29, 0x6a, 0x40, 0xf5, 0x60, 0x7f, 0x68, 0xe2, 0xde, 0x41, 0xeb, 0x3d,
+	0x04, 0x50, 0x39, 0xc6, 0xe6, 0xd3, 0xfd, 0x97, 0x2d, 0x05, 0xd5, 0xa1, 0x8c, 0xcd, 0x3d, 0xf3,
+	0x65, 0xab, 0x80, 0x56, 0xa0, 0xf6, 0xf4, 0xe4, 0xe0, 0x60, 0x68, 0xbe, 0x1c, 0xb6, 0x8a, 0x06,
+	0x85, 0xd5, 0xf0, 0xd8, 0x64, 0x38, 0xdf, 0x86, 0x32, 0xab, 0xa4, 0x61, 0x30, 0xc7, 0x05, 0x56,
+	0xb0, 0xd1, 0x5d, 0x68, 0xba, 0xe4, 0x6b, 0x7a, 0x1c, 0xdd, 0xb3, 0xc8, 0xd0, 0x69, 0x26, 0x8b,
+	0x04, 0xea, 0x51, 0x6b, 0xc2, 0xc3, 0x44, 0x54, 0xd1, 0x98, 0x61, 0xfc, 0x43, 0x81, 0x95, 0x17,
+	0x16, 0x8d, 0x2e, 0xeb, 0xff, 0x14, 0xaa, 0x51, 0x58, 0x95, 0x16, 0x87, 0x55, 0x79, 0x79, 0x58,
+	0x55, 0xde, 0x25, 0xac, 0x8c, 0x7f, 0x2a, 0x50, 0x36, 0xcf, 0x89, 0x4b, 0x91, 0x01, 0x25, 0x7a,
+	0x31, 0x13, 0xd5, 0x4c, 0x1a, 0xc6, 0x17, 0x3a, 0xc3, 0x8b, 0x19, 0xc1, 0x7c, 0x2d, 0xea, 0x60,
+	0x0a, 0x0b, 0x3b, 0x98, 0xbb, 0x50, 0x9b, 0xf9, 0xe4, 0xdc, 0xf1, 0xe6, 0x81, 0x5a, 0xcc, 0x20,
+	0xa2, 0x15, 0xe6, 0x04, 0x25, 0xae, 0xe5, 0x52, 0xe9, 0x9b, 0xa4, 0x8c, 0x3d, 0x28, 0x31, 0x4d,
+	0x2c, 0x12, 0x9e, 0x60, 0xb3, 0x27, 0x1a, 0xce, 0x06, 0x54, 0x4f, 0x8e, 0xfb, 0x9c, 0x50, 0x18,
+	0xd1, 0x37, 0x0f, 0x4c, 0x46, 0xf0, 0x60, 0xc0, 0xe6, 0x60, 0x78, 0x84, 0xcd, 0x7e, 0xab, 0xc8,
+	0x23, 0xe6, 0x04, 0xef, 0x99, 0xfd, 0x56, 0xc9, 0xf8, 0x63, 0x11, 0x9a, 0x9f, 0x3b, 0xac, 0xf6,
+	0x5f, 0x60, 0x32, 0xf2, 0x7c, 0x9b, 0xab, 0xf4, 0xfa, 0xde, 0x7e, 0x58, 0x95, 0x24, 0x15, 0xb9,
+	0x5c, 0xb8, 0xc4, 0xe5, 0x0d, 0x28, 0x5b, 0x23, 0xea, 0xf9, 0x72, 0x9c, 0x11, 0x04, 0xea, 0x40,
+	0x89, 0x4d, 0x6d, 0x6a, 0x69, 0x49, 0x9b, 0x16, 0x37, 0xab, 0x1c, 0xc7, 0x82, 0x4a, 0x66, 0xe9,
+	0xe8, 0xf2, 0x62, 0x46, 0x72, 0xec, 0xa8, 0xa4, 0xc7, 0x8e, 0x1d, 0xa8, 0x8e, 0xce, 0xd8, 0xc3,
+	0x09, 0xd4, 0x2a, 0x0f, 0x6a, 0x95, 0x19, 0x99, 0xf2, 0xae, 0xf3, 0x84, 0x03, 0x70, 0x08, 0x8c,
+	0x63, 0xa7, 0xb6, 0x38, 0x76, 0xea, 0xc9, 0xd8, 0xd1, 0x9e, 0x43, 0x45, 0x08, 0x60, 0xfb, 0xf8,
+	0xa0, 0x29, 0x0f, 0x49, 0x10, 0x2c, 0x69, 0x7a, 0x13, 0xfb, 0xb9, 0x35, 0x99, 0x87, 0x1d, 0x4d,
+	0x44, 0xb3, 0x35, 0x97, 0xbc, 0x15, 0x6b, 0xe2, 0x78, 0x22, 0xda, 0xf8, 0x18, 0xd6, 0xf6, 0x08,
+	0x8d, 0x2c, 0x5d, 0xdc, 0x79, 0xf5, 0x00, 0x25, 0x41, 0xf2, 0x1d, 0xdf, 0x67, 0x65, 0x89, 0xb9,
+	0x96, 0x2a, 0x7a, 0x29, 0xa7, 0x71, 0x88, 0x30, 0xfe, 0xad, 0x40, 0xe9, 0xc0, 0xc9, 0xcb, 0x66,
+	0xcf, 0xcf, 0xb5, 0xa6, 0xa1, 0xd1, 0xfc, 0x3b, 0x3e, 0x9a, 0x62, 0xf2, 0x68, 0x3e, 0x65, 0x3d,
+	0xdb, 0xf4, 0x94, 0xf8, 0x62, 0x30, 0x95, 0x95, 0x8a, 0x09, 0xed, 0x1c, 0x72, 0x3e, 0x0e, 0xd7,
+	0x35, 0x13, 0x2a, 0x82, 0xc5, 0xee, 0x2c, 0x98, 0x9f, 0x7e, 0x49, 0x46, 0x34, 0x7c, 0xf7, 0x92,
+	0x44, 0x77, 0xa0, 0xe4, 0x7b, 0x93, 0x30, 0xaa, 0x9a, 0x91, 0x2c, 0xec, 0x4d, 0x08, 0xe6, 0x4b,
+	0xc6, 0xa7, 0x50, 0x62, 0x14, 0x0b, 0xdb, 0xe7, 0xfb, 0xe6, 0x0b, 0x13, 0x8b, 0xa4, 0x67, 0xf6,
+	0xf7, 0x87, 0x47, 0x58, 0x24, 0xbd, 0xa3, 0x17, 0xcf, 0x4c, 0xdc, 0x2a, 0x18, 0xdb, 0xb0, 0x26,
+	0x3a, 0x2f, 0x26, 0x23, 0x31, 0x49, 0xb0, 0xeb, 0x4b, 0x4e, 0x12, 0x7c, 0x99, 0x73, 0x8d, 0xbb,
+	0x80, 0x92, 0x5b, 0x96, 0x0c, 0x3b, 0x77, 0x58, 0x13, 0x6d, 0xd9, 0x49, 0xb1, 0x59, 0xc8, 0x43,
+	0x68, 0xc5, 0x90, 0x78, 0xe8, 0xb9, 0x44, 0xf5, 0x36, 0xac, 0x89, 0xae, 0xe9, 0xfa, 0xd6, 0x76,
+	0x00, 0x25, 0xb7, 0x5c, 0x39, 0x95, 0x7c, 0x0c, 0x6b, 0xa2, 0x39, 0xb9, 0xcc, 0xf2, 0x0e, 0xa0,
+	0x24, 0xe8, 0xca, 0x99, 0x63, 0x03, 0x90, 0x48, 0x96, 0x0c, 0x1f, 0x48, 0xa9, 0xc6, 0x0f, 0x60,
+	0x3d, 0xc5, 0x8d, 0xeb, 0x0c, 0xb3, 0x3c, 0x55, 0x67, 0xb8, 0x1e, 0xc1, 0x36, 0x5e, 0x41, 0x6b,
+	0x70, 0x66, 0xf9, 0x97, 0x19, 0x98, 0x0c, 0x9f, 0xc2, 0xe2, 0xf0, 0x29, 0x2e, 0x0f, 0x9f, 0x6d,
+	0x58, 0x4b, 0x28, 0xb8, 0xd6, 0xc5, 0xfc, 0x14, 0xd0, 0x89, 0x1b, 0xfc, 0xcf, 0x56, 0x19, 0x8f,
+	0x60, 0x3d, 0xb5, 0xff, 0x3a, 0x4a, 0x77, 0xfe, 0xdc, 0x84, 0x06, 0xcb, 0xfe, 0x03, 0xe2, 0x9f,
+	0x3b, 0x23, 0x82, 0x3e, 0x87, 0x8a, 0x08, 0x4c, 0x94, 0x1f, 0x5a, 0x34, 0x94, 0x64, 0x09, 0xf1,
+	0xc6, 0xcd, 0x3f, 0xfc, 0xeb, 0x3f, 0xdf, 0x15, 0xd6, 0x8c, 0x7a, 0xf7, 0x7c, 0x9b, 0xff, 0x1b,
+	0x2d, 0xd8, 0x15, 0xa5, 0xa6, 0x07, 0x25, 0x16, 0x99, 0x88, 0xbf, 0xd4, 0xc4, 0xcc, 0xae, 0xb5,
+	0x62, 0x86, 0x94, 0xd1, 0xe6, 0x32, 0x5a, 0x68, 0x35, 0x92, 0xd1, 0xfd, 0xbd, 0x63, 0x7f, 0x83,
+	0x30, 0x54, 0x44, 0xdc, 0xa1, 0xfc, 0x3c, 0xa1, 0xa1, 0x24, 0x4b, 0x0a, 0xba, 0xc3, 0x05, 0x7d,
+	0xb8, 0xb3, 0x9e, 0x10, 0xc4, 0x8c, 0xe9, 0x38, 0xf6, 0x37, 0xd2, 0xac, 0x3d, 0xa8, 0x88, 0xb0,
+	0x43, 0xf9, 0x3e, 0x5d, 0x43, 0x49, 0x56, 0xda, 0xb8, 0x7b, 0x59, 0xe3, 0x4e, 0xa0, 0x2a, 0x27,
+	0x5c, 0x84, 0x84, 0x47, 0xc9, 0x81, 0x58, 0x5b, 0x4f, 0xf1, 0xd2, 0xf6, 0x19, 0xed, 0xb4, 0xac,
+	0x5d, 0x39, 0x07, 0xef, 0x2a, 0xf7, 0xd0, 0x21, 0x94, 0xf9, 0xa4, 0x8b, 0xf8, 0x31, 0x25, 0x87,
+	0x62, 0x6d, 0x2d, 0xc1, 0x91, 0x02, 0x3f, 0xe2, 0x02, 0x3f, 0x30, 0x36, 0x32, 0x02, 0xf9, 0x34,
+	0xcc, 0xc4, 0xfd, 0x56, 0xce, 0xc3, 0xf2, 0x52, 0xdb, 0xb9, 0x59, 0x51, 0x88, 0xbe, 0xb9, 0x64,
+	0x86, 0x34, 0x74, 0xae, 0x40, 0x33, 0xde, 0x8f, 0xaf, 0xf7, 0x34, 0x86, 0x25, 0x35, 0xc8, 0x9b,
+	0x6a, 0xe7, 0x66, 0xb3, 0xac, 0x86, 0xcc, 0x9d, 0x2d, 0xd3, 0x20, 0x60, 0x49, 0x0d, 0xf2, 0xde,
+	0xda, 0xb9, 0x09, 0x28, 0xab, 0x21, 0x73, 0x83, 0xcb, 0x34, 0x08, 0x18, 0xd3, 0xf0, 0x18, 0x2a,
+	0x22, 0x8b, 0x88, 0xa0, 0x48, 0x35, 0x65, 0x1a, 0x4a, 0xb2, 0xa4, 0xc8, 0x35, 0x2e, 0xb2, 0x81,
+	0xe2, 0xa8, 0x47, 0x7b, 0xb0, 0x22, 0x40, 0x03, 0xea, 0x13, 0x6b, 0xba, 0x48, 0x52, 0xd4, 0x6b,
+	0x19, 0x2a, 0xdf, 0x8f, 0x50, 0x2b, 0x36, 0x29, 0xe0, 0xdb, 0x1e, 0x2a, 0xe8, 0x67, 0x50, 0xe6,
+	0xed, 0xab, 0x88, 0x80, 0x64, 0x27, 0xab, 0xd5, 0xa3, 0xfe, 0x27, 0x7c, 0x77, 0xe8, 0x46, 0x2c,
+	0xe1, 0x2d, 0x83, 0x3e, 0x54, 0xd0, 0x17, 0x00, 0x71, 0xc9, 0x46, 0xef, 0xb3, 0x3d, 0xb9, 0x3a,
+	0xaf, 0xb5, 0xb3, 0x6c, 0xe9, 0xd9, 0x6d, 0x2e, 0x57, 0x45, 0x99, 0x10, 0xed, 0x9e, 0x49, 0x71,
+	0x43, 0x80, 0xb8, 0x72, 0x09, 0xe1, 0xb9, 0xe2, 0xa7, 0xb5, 0xb3, 0xec, 0x45, 0xc9, 0x82, 0x67,
+	0xe2, 0x5d, 0x9e, 0x86, 0xd0, 0x21, 0xd4, 0xc2, 0x32, 0x86, 0xd6, 0xc3, 0xfc, 0x90, 0x94, 0xb8,
+	0x91, 0x66, 0x2e, 0x4a, 0x1c, 0x5c, 0x9e, 0x78, 0x9b, 0xbf, 0x01, 0x88, 0x0b, 0x96, 0x30, 0x32,
+	0x57, 0xf3, 0xb4, 0x76, 0x96, 0xbd, 0x28, 0x89, 0x48, 0xa1, 0xec, 0x8f, 0x48, 0x22, 0xdc, 0xdc,
+	0x01, 0x40, 0x5c, 0xbb, 0x84, 0xfc, 0x5c, 0xc1, 0xd3, 0xda, 0x59, 0xf6, 0xa2, 0x84, 0x92, 0x30,
+	0xfa, 0x97, 0xd0, 0x48, 0x94, 0x32, 0x11, 0xe6, 0xf9, 0x8a, 0xa7, 0xdd, 0xcc, 0xf1, 0x17, 0xc5,
+	0x24, 0x97, 0x8b, 0x7e, 0x05, 0xf5, 0xa8, 0x0a, 0x21, 0x7e, 0x84, 0xd9, 0xaa, 0xa7, 0xbd, 0x9f,
+	0xe1, 0x2e, 0x4a, 0x2c, 0xb1, 0x91, 0xbb, 0xbc, 0xbe, 0xb0, 0x27, 0x63, 0x41, 0x23, 0x51, 0x6d,
+	0x84, 0xb5, 0xf9, 0xf2, 0xa5, 0xdd, 0xcc, 0xf1, 0x17, 0xa5, 0xc2, 0x84, 0x82, 0xb9, 0x1b, 0xaa,
+	0x78, 0xfc, 0x57, 0xe5, 0xd7, 0x2b, 0xf1, 0x4f, 0x34, 0xe7, 0xdb, 0xdf, 0xf6, 0xfe, 0xa4, 0x20,
+	0x1b, 0x6a, 0xec, 0x05, 0xe9, 0xbd, 0xe3, 0x7d, 0x34, 0x18, 0x9e, 0x11, 0x9d, 0x8d, 0x18, 0xdd,
+	0x9f, 0x0f, 0x8e, 0x9e, 0xe9, 0x53, 0x6b, 0x36, 0x73, 0xdc, 0xb1, 0xee, 0xbd, 0xd6, 0xe9, 0x19,
+	0xd1, 0x13, 0x65, 0xed, 0x01, 0x67, 0x88, 0x17, 0x16, 0xe8, 0x96, 0x1b, 0xbc, 0x25, 0xbe, 0x6e,
+	0xe9, 0x7c, 0x97, 0xc7, 0xab, 0xa7, 0x7e, 0x7a, 0xa1, 0x4f, 0x1c, 0x97, 0x74, 0x76, 0x8a, 0xdb,
+	0x9d, 0x87, 0xf7, 0x0a, 0x4a, 0x61, 0xa7, 0x65, 0xcd, 0x66, 0x13, 0x67, 0xc4, 0x7f, 0x6e, 0xe9,
+	0x7e, 0x19, 0x78, 0xee, 0x6e, 0x8e, 0x73, 0x5a, 0xe1, 0xe3, 0xc4, 0xa3, 0xff, 0x0e, 0x00, 0x51,
+	0x42, 0xcd, 0x16, 0x4b, 0x1a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ToDoServiceClient is the client API for ToDoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ToDoServiceClient interface {
+	// Create new todo
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	// Read the todo
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	// Update a todo
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	// Delete moves a todo to the trash, it is purged after the retention of the server
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Restore takes a todo out of the trash, the caller must be able to delete it
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
+	// Purge removes a todo from the trash for good, the caller must be able to delete it
+	Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error)
+	// BatchCreate creates many todos, the results tell which ones failed
+	BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error)
+	// BatchUpdate updates many todos, the results tell which ones failed
+	BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error)
+	// BatchDelete moves many todos to the trash, the results tell which ones failed
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+	// Search a todos
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// SearchStream streams the todos matching the filters in the order, pageSize and pageToken are ignored
+	SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (ToDoService_SearchStreamClient, error)
+	// Watch streams the changes on the todos matching the filters before or after the change
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ToDoService_WatchClient, error)
+	// GetHistory returns the changes on a todo, the caller must see the todo like for Read
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	// CreateList creates a list owned by the caller
+	CreateList(ctx context.Context, in *CreateListRequest, opts ...grpc.CallOption) (*CreateListResponse, error)
+	// ReadList reads a list of the caller
+	ReadList(ctx context.Context, in *ReadListRequest, opts ...grpc.CallOption) (*ReadListResponse, error)
+	// UpdateList renames a list, the caller must be an owner of the list
+	UpdateList(ctx context.Context, in *UpdateListRequest, opts ...grpc.CallOption) (*UpdateListResponse, error)
+	// DeleteList deletes an empty list, the caller must be an owner of the list
+	DeleteList(ctx context.Context, in *DeleteListRequest, opts ...grpc.CallOption) (*DeleteListResponse, error)
+	// SearchLists returns the lists owned by or shared with the caller
+	SearchLists(ctx context.Context, in *SearchListsRequest, opts ...grpc.CallOption) (*SearchListsResponse, error)
+	// ShareList gives a role on a list to a user, the caller must be an owner of the list
+	ShareList(ctx context.Context, in *ShareListRequest, opts ...grpc.CallOption) (*ShareListResponse, error)
+	// UnshareList removes a user from a list, the caller must be an owner of the list or the user
+	UnshareList(ctx context.Context, in *UnshareListRequest, opts ...grpc.CallOption) (*UnshareListResponse, error)
+}
+
+type toDoServiceClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewToDoServiceClient(cc *grpc.ClientConn) ToDoServiceClient {
-	return &toDoServiceClient{cc}
+func NewToDoServiceClient(cc *grpc.ClientConn) ToDoServiceClient {
+	return &toDoServiceClient{cc}
+}
+
+func (c *toDoServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
+	out := new(ReadResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Read", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
+	out := new(UpdateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error) {
+	out := new(PurgeResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Purge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error) {
+	out := new(BatchCreateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/BatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error) {
+	out := new(BatchUpdateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/BatchUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/BatchDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) SearchStream(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (ToDoService_SearchStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ToDoService_serviceDesc.Streams[0], "/v1.ToDoService/SearchStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &toDoServiceSearchStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ToDoService_SearchStreamClient interface {
+	Recv() (*ToDo, error)
+	grpc.ClientStream
+}
+
+type toDoServiceSearchStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *toDoServiceSearchStreamClient) Recv() (*ToDo, error) {
+	m := new(ToDo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *toDoServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ToDoService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ToDoService_serviceDesc.Streams[1], "/v1.ToDoService/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &toDoServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ToDoService_WatchClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type toDoServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *toDoServiceWatchClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *toDoServiceClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	out := new(GetHistoryResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/GetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) CreateList(ctx context.Context, in *CreateListRequest, opts ...grpc.CallOption) (*CreateListResponse, error) {
+	out := new(CreateListResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/CreateList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ReadList(ctx context.Context, in *ReadListRequest, opts ...grpc.CallOption) (*ReadListResponse, error) {
+	out := new(ReadListResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ReadList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *toDoServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
-	out := new(CreateResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Create", in, out, opts...)
+func (c *toDoServiceClient) UpdateList(ctx context.Context, in *UpdateListRequest, opts ...grpc.CallOption) (*UpdateListResponse, error) {
+	out := new(UpdateListResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/UpdateList", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *toDoServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
-	out := new(ReadResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Read", in, out, opts...)
+func (c *toDoServiceClient) DeleteList(ctx context.Context, in *DeleteListRequest, opts ...grpc.CallOption) (*DeleteListResponse, error) {
+	out := new(DeleteListResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/DeleteList", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *toDoServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
-	out := new(UpdateResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Update", in, out, opts...)
+func (c *toDoServiceClient) SearchLists(ctx context.Context, in *SearchListsRequest, opts ...grpc.CallOption) (*SearchListsResponse, error) {
+	out := new(SearchListsResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/SearchLists", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *toDoServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Delete", in, out, opts...)
+func (c *toDoServiceClient) ShareList(ctx context.Context, in *ShareListRequest, opts ...grpc.CallOption) (*ShareListResponse, error) {
+	out := new(ShareListResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ShareList", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *toDoServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
-	out := new(SearchResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Search", in, out, opts...)
+func (c *toDoServiceClient) UnshareList(ctx context.Context, in *UnshareListRequest, opts ...grpc.CallOption) (*UnshareListResponse, error) {
+	out := new(UnshareListResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/UnshareList", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -705,10 +3182,40 @@ type ToDoServiceServer interface {
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
 	// Update a todo
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
-	// Delete a todo
+	// Delete moves a todo to the trash, it is purged after the retention of the server
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Restore takes a todo out of the trash, the caller must be able to delete it
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
+	// Purge removes a todo from the trash for good, the caller must be able to delete it
+	Purge(context.Context, *PurgeRequest) (*PurgeResponse, error)
+	// BatchCreate creates many todos, the results tell which ones failed
+	BatchCreate(context.Context, *BatchCreateRequest) (*BatchCreateResponse, error)
+	// BatchUpdate updates many todos, the results tell which ones failed
+	BatchUpdate(context.Context, *BatchUpdateRequest) (*BatchUpdateResponse, error)
+	// BatchDelete moves many todos to the trash, the results tell which ones failed
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
 	// Search a todos
 	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// SearchStream streams the todos matching the filters in the order, pageSize and pageToken are ignored
+	SearchStream(*SearchRequest, ToDoService_SearchStreamServer) error
+	// Watch streams the changes on the todos matching the filters before or after the change
+	Watch(*WatchRequest, ToDoService_WatchServer) error
+	// GetHistory returns the changes on a todo, the caller must see the todo like for Read
+	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
+	// CreateList creates a list owned by the caller
+	CreateList(context.Context, *CreateListRequest) (*CreateListResponse, error)
+	// ReadList reads a list of the caller
+	ReadList(context.Context, *ReadListRequest) (*ReadListResponse, error)
+	// UpdateList renames a list, the caller must be an owner of the list
+	UpdateList(context.Context, *UpdateListRequest) (*UpdateListResponse, error)
+	// DeleteList deletes an empty list, the caller must be an owner of the list
+	DeleteList(context.Context, *DeleteListRequest) (*DeleteListResponse, error)
+	// SearchLists returns the lists owned by or shared with the caller
+	SearchLists(context.Context, *SearchListsRequest) (*SearchListsResponse, error)
+	// ShareList gives a role on a list to a user, the caller must be an owner of the list
+	ShareList(context.Context, *ShareListRequest) (*ShareListResponse, error)
+	// UnshareList removes a user from a list, the caller must be an owner of the list or the user
+	UnshareList(context.Context, *UnshareListRequest) (*UnshareListResponse, error)
 }
 
 // UnimplementedToDoServiceServer can be embedded to have forward compatible implementations.
@@ -727,9 +3234,54 @@ func (*UnimplementedToDoServiceServer) Update(ctx context.Context, req *UpdateRe
 func (*UnimplementedToDoServiceServer) Delete(ctx context.Context, req *DeleteRequest) (*DeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (*UnimplementedToDoServiceServer) Restore(ctx context.Context, req *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (*UnimplementedToDoServiceServer) Purge(ctx context.Context, req *PurgeRequest) (*PurgeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (*UnimplementedToDoServiceServer) BatchCreate(ctx context.Context, req *BatchCreateRequest) (*BatchCreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreate not implemented")
+}
+func (*UnimplementedToDoServiceServer) BatchUpdate(ctx context.Context, req *BatchUpdateRequest) (*BatchUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdate not implemented")
+}
+func (*UnimplementedToDoServiceServer) BatchDelete(ctx context.Context, req *BatchDeleteRequest) (*BatchDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
+}
 func (*UnimplementedToDoServiceServer) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
+func (*UnimplementedToDoServiceServer) SearchStream(req *SearchRequest, srv ToDoService_SearchStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchStream not implemented")
+}
+func (*UnimplementedToDoServiceServer) Watch(req *WatchRequest, srv ToDoService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (*UnimplementedToDoServiceServer) GetHistory(ctx context.Context, req *GetHistoryRequest) (*GetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (*UnimplementedToDoServiceServer) CreateList(ctx context.Context, req *CreateListRequest) (*CreateListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateList not implemented")
+}
+func (*UnimplementedToDoServiceServer) ReadList(ctx context.Context, req *ReadListRequest) (*ReadListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadList not implemented")
+}
+func (*UnimplementedToDoServiceServer) UpdateList(ctx context.Context, req *UpdateListRequest) (*UpdateListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateList not implemented")
+}
+func (*UnimplementedToDoServiceServer) DeleteList(ctx context.Context, req *DeleteListRequest) (*DeleteListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteList not implemented")
+}
+func (*UnimplementedToDoServiceServer) SearchLists(ctx context.Context, req *SearchListsRequest) (*SearchListsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchLists not implemented")
+}
+func (*UnimplementedToDoServiceServer) ShareList(ctx context.Context, req *ShareListRequest) (*ShareListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareList not implemented")
+}
+func (*UnimplementedToDoServiceServer) UnshareList(ctx context.Context, req *UnshareListRequest) (*UnshareListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareList not implemented")
+}
 
 func RegisterToDoServiceServer(s *grpc.Server, srv ToDoServiceServer) {
 	s.RegisterService(&_ToDoService_serviceDesc, srv)
@@ -807,6 +3359,96 @@ func _ToDoService_Delete_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ToDoService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Purge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Purge(ctx, req.(*PurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_BatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).BatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/BatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).BatchCreate(ctx, req.(*BatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_BatchUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).BatchUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/BatchUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).BatchUpdate(ctx, req.(*BatchUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/BatchDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ToDoService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SearchRequest)
 	if err := dec(in); err != nil {
@@ -825,6 +3467,192 @@ func _ToDoService_Search_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ToDoService_SearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ToDoServiceServer).SearchStream(m, &toDoServiceSearchStreamServer{stream})
+}
+
+type ToDoService_SearchStreamServer interface {
+	Send(*ToDo) error
+	grpc.ServerStream
+}
+
+type toDoServiceSearchStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *toDoServiceSearchStreamServer) Send(m *ToDo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ToDoService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ToDoServiceServer).Watch(m, &toDoServiceWatchServer{stream})
+}
+
+type ToDoService_WatchServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type toDoServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *toDoServiceWatchServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ToDoService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/GetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_CreateList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).CreateList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/CreateList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).CreateList(ctx, req.(*CreateListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_ReadList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).ReadList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/ReadList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).ReadList(ctx, req.(*ReadListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_UpdateList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).UpdateList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/UpdateList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).UpdateList(ctx, req.(*UpdateListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_DeleteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).DeleteList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/DeleteList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).DeleteList(ctx, req.(*DeleteListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_SearchLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchListsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).SearchLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/SearchLists",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).SearchLists(ctx, req.(*SearchListsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_ShareList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).ShareList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/ShareList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).ShareList(ctx, req.(*ShareListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_UnshareList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).UnshareList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/UnshareList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).UnshareList(ctx, req.(*UnshareListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ToDoService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.ToDoService",
 	HandlerType: (*ToDoServiceServer)(nil),
@@ -845,11 +3673,74 @@ var _ToDoService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ToDoService_Delete_Handler,
 		},
+		{
+			MethodName: "Restore",
+			Handler:    _ToDoService_Restore_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _ToDoService_Purge_Handler,
+		},
+		{
+			MethodName: "BatchCreate",
+			Handler:    _ToDoService_BatchCreate_Handler,
+		},
+		{
+			MethodName: "BatchUpdate",
+			Handler:    _ToDoService_BatchUpdate_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _ToDoService_BatchDelete_Handler,
+		},
 		{
 			MethodName: "Search",
 			Handler:    _ToDoService_Search_Handler,
 		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _ToDoService_GetHistory_Handler,
+		},
+		{
+			MethodName: "CreateList",
+			Handler:    _ToDoService_CreateList_Handler,
+		},
+		{
+			MethodName: "ReadList",
+			Handler:    _ToDoService_ReadList_Handler,
+		},
+		{
+			MethodName: "UpdateList",
+			Handler:    _ToDoService_UpdateList_Handler,
+		},
+		{
+			MethodName: "DeleteList",
+			Handler:    _ToDoService_DeleteList_Handler,
+		},
+		{
+			MethodName: "SearchLists",
+			Handler:    _ToDoService_SearchLists_Handler,
+		},
+		{
+			MethodName: "ShareList",
+			Handler:    _ToDoService_ShareList_Handler,
+		},
+		{
+			MethodName: "UnshareList",
+			Handler:    _ToDoService_UnshareList_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchStream",
+			Handler:       _ToDoService_SearchStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _ToDoService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "todo-grpc/todo.proto",
 }